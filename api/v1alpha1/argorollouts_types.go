@@ -17,8 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // RolloutManagerSpec defines the desired state of Argo Rollouts
@@ -34,6 +38,18 @@ type RolloutManagerSpec struct {
 	// with same or different value.
 	ExtraCommandArgs []string `json:"extraCommandArgs,omitempty"`
 
+	// Controller defines tuning options of the Argo Rollouts controller. These are preferred over the equivalent ExtraCommandArgs.
+	// +optional
+	Controller *RolloutManagerControllerSpec `json:"controller,omitempty"`
+
+	// TrafficRouting declares the traffic routing providers used with Argo Rollouts, and their options.
+	// +optional
+	TrafficRouting *RolloutManagerTrafficRoutingSpec `json:"trafficRouting,omitempty"`
+
+	// RBAC defines how the operator computes the rules of the Role/ClusterRole of the Rollouts controller.
+	// +optional
+	RBAC *RolloutManagerRBACSpec `json:"rbac,omitempty"`
+
 	// Image defines Argo Rollouts controller image (optional)
 	Image string `json:"image,omitempty"`
 
@@ -52,6 +68,22 @@ type RolloutManagerSpec struct {
 	// Resources requests/limits for Argo Rollout controller
 	ControllerResources *corev1.ResourceRequirements `json:"controllerResources,omitempty"`
 
+	// Volumes are additional volumes of the Rollouts controller Pod, added after the volumes generated by the operator.
+	// +optional
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// VolumeMounts are additional volume mounts of the Rollouts controller container. They may reference the additional Volumes.
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// InitContainers are additional init containers of the Rollouts controller Pod, run after the init containers of plugins with an image.
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// SidecarContainers are additional containers of the Rollouts controller Pod, run alongside the Rollouts controller container.
+	// +optional
+	SidecarContainers []corev1.Container `json:"sidecarContainers,omitempty"`
+
 	// SkipNotificationSecretDeployment lets you specify if the argo notification secret should be deployed
 	SkipNotificationSecretDeployment bool `json:"skipNotificationSecretDeployment,omitempty"`
 
@@ -61,11 +93,607 @@ type RolloutManagerSpec struct {
 	// HA options for High Availability support for Rollouts.
 	HA *RolloutManagerHASpec `json:"ha,omitempty"`
 
+	// Deployment defines the rollout options of the Rollouts controller Deployment.
+	// +optional
+	Deployment *RolloutManagerDeploymentSpec `json:"deployment,omitempty"`
+
 	// ImagePullPolicy specifies the image pull policy for the Rollouts controller.
 	// Valid values are: Always, IfNotPresent, Never
 	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
 	// +optional
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// Dashboard defines the options for the Argo Rollouts Dashboard, a web UI for viewing and managing Rollouts.
+	// +optional
+	Dashboard *RolloutManagerDashboardSpec `json:"dashboard,omitempty"`
+
+	// Notifications defines the notification services, templates, triggers and subscriptions of Argo Rollouts.
+	// When set, the operator manages the contents of the argo-rollouts-notification-configmap ConfigMap.
+	// +optional
+	Notifications *RolloutManagerNotificationsSpec `json:"notifications,omitempty"`
+
+	// Overrides are patches applied to the resources of the Rollouts controller, after they are generated by the operator.
+	// They allow setting fields that are not otherwise configurable via the RolloutManager.
+	// +optional
+	Overrides []RolloutManagerOverride `json:"overrides,omitempty"`
+
+	// ReconcileMode is Apply (the default) to apply the desired state of the resources of the Rollouts controller, or Observe to only report
+	// the changes that would be applied in .status.plannedChanges, without modifying the resources.
+	// +kubebuilder:validation:Enum=Apply;Observe
+	// +optional
+	ReconcileMode RolloutManagerReconcileMode `json:"reconcileMode,omitempty"`
+
+	// Paused stops the reconciliation of the resources of the Rollouts controller: while it is true, the operator does not create, update or
+	// delete any of them, for example to allow hot-patching them during an incident. The status of the RolloutManager is still reported.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// UnmanagedComponents are the components of the Rollouts controller whose resources are neither created, updated nor deleted by the operator.
+	// +optional
+	UnmanagedComponents []RolloutManagerComponent `json:"unmanagedComponents,omitempty"`
+
+	// Suspend scales the Deployment of the Rollouts controller to zero, for example during a freeze or a migration, while keeping all its other
+	// resources in place. The previous number of replicas is restored once it is false again.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// RolloutManagerComponent is a component of the Rollouts controller, which groups the resources that are reconciled together.
+// +kubebuilder:validation:Enum=Deployment;ConfigMap;NetworkPolicy;RBAC;MetricsService
+type RolloutManagerComponent string
+
+const (
+	// ComponentDeployment is the Deployment of the Rollouts controller, and the PodDisruptionBudget of its pods.
+	ComponentDeployment RolloutManagerComponent = "Deployment"
+	// ComponentConfigMap is the argo-rollouts-config ConfigMap, which configures the plugins of the Rollouts controller.
+	ComponentConfigMap RolloutManagerComponent = "ConfigMap"
+	// ComponentNetworkPolicy is the NetworkPolicy of the Rollouts controller.
+	ComponentNetworkPolicy RolloutManagerComponent = "NetworkPolicy"
+	// ComponentRBAC is the ServiceAccount of the Rollouts controller, its Role or ClusterRole and binding, the aggregated ClusterRoles,
+	// and the ServiceAccount, Role or ClusterRole and binding of the Dashboard.
+	ComponentRBAC RolloutManagerComponent = "RBAC"
+	// ComponentMetricsService is the metrics Service of the Rollouts controller, and its ServiceMonitor.
+	ComponentMetricsService RolloutManagerComponent = "MetricsService"
+)
+
+// RolloutManagerReconcileMode defines whether the operator applies the desired state of the resources of a RolloutManager, or only reports the changes that it would apply.
+type RolloutManagerReconcileMode string
+
+const (
+	// ReconcileModeApply applies the desired state of the resources of the RolloutManager.
+	ReconcileModeApply RolloutManagerReconcileMode = "Apply"
+	// ReconcileModeObserve reports the changes that would be applied to the resources of the RolloutManager in .status.plannedChanges, without modifying them.
+	ReconcileModeObserve RolloutManagerReconcileMode = "Observe"
+)
+
+// RolloutManagerOverridePatchType is the type of the patch of a RolloutManagerOverride.
+type RolloutManagerOverridePatchType string
+
+const (
+	// OverridePatchTypeStrategic is a strategic merge patch, as used by 'kubectl patch --type=strategic'.
+	OverridePatchTypeStrategic RolloutManagerOverridePatchType = "strategic"
+	// OverridePatchTypeJSON is a JSON patch (RFC 6902), as used by 'kubectl patch --type=json'.
+	OverridePatchTypeJSON RolloutManagerOverridePatchType = "json"
+)
+
+// RolloutManagerOverride defines a patch of a resource of the Rollouts controller.
+type RolloutManagerOverride struct {
+	// Kind of the resources to patch.
+	// +kubebuilder:validation:Enum=Deployment;Service;ServiceAccount;NetworkPolicy;Role;ClusterRole
+	Kind string `json:"kind"`
+	// Name of the resource to patch. When empty, every resource of Kind of the Rollouts controller is patched.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Type of the patch: 'strategic' (the default) or 'json'.
+	// +kubebuilder:validation:Enum=strategic;json
+	// +optional
+	Type RolloutManagerOverridePatchType `json:"type,omitempty"`
+	// Patch to apply to the resource, in YAML or JSON.
+	Patch string `json:"patch"`
+}
+
+// RolloutManagerControllerSpec defines tuning options of the Argo Rollouts controller.
+// Fields that are not set use the default value of the Argo Rollouts controller.
+type RolloutManagerControllerSpec struct {
+	// RolloutThreads is the number of worker threads used to reconcile Rollouts (--rollout-threads).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RolloutThreads *int32 `json:"rolloutThreads,omitempty"`
+
+	// ExperimentThreads is the number of worker threads used to reconcile Experiments (--experiment-threads).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ExperimentThreads *int32 `json:"experimentThreads,omitempty"`
+
+	// AnalysisThreads is the number of worker threads used to reconcile AnalysisRuns (--analysis-threads).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	AnalysisThreads *int32 `json:"analysisThreads,omitempty"`
+
+	// QPS is the maximum queries per second from the controller to the Kubernetes API server (--qps).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	QPS *int32 `json:"qps,omitempty"`
+
+	// Burst is the maximum burst of queries from the controller to the Kubernetes API server (--burst).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+
+	// RolloutResync is the period, in seconds, after which all Rollouts are resynced (--rollout-resync).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RolloutResync *int32 `json:"rolloutResync,omitempty"`
+
+	// LogLevel is the log level of the controller (--loglevel).
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// LogFormat is the log format of the controller (--logformat).
+	// +kubebuilder:validation:Enum=text;json
+	// +optional
+	LogFormat string `json:"logFormat,omitempty"`
+
+	// KLogLevel is the log level of the Kubernetes client library (--kloglevel).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KLogLevel *int32 `json:"klogLevel,omitempty"`
+}
+
+// RolloutManagerTrafficRoutingSpec declares the traffic routing providers used with Argo Rollouts.
+// A provider is in use when its field is set. For more information, see https://argo-rollouts.readthedocs.io/en/stable/features/traffic-management/
+type RolloutManagerTrafficRoutingSpec struct {
+	// ALB configures the AWS Application Load Balancer provider.
+	// +optional
+	ALB *ALBTrafficRoutingSpec `json:"alb,omitempty"`
+
+	// Ambassador configures the Ambassador provider.
+	// +optional
+	Ambassador *AmbassadorTrafficRoutingSpec `json:"ambassador,omitempty"`
+
+	// APISIX configures the Apache APISIX provider.
+	// +optional
+	APISIX *APISIXTrafficRoutingSpec `json:"apisix,omitempty"`
+
+	// AppMesh configures the AWS App Mesh provider.
+	// +optional
+	AppMesh *AppMeshTrafficRoutingSpec `json:"appMesh,omitempty"`
+
+	// Istio configures the Istio provider.
+	// +optional
+	Istio *IstioTrafficRoutingSpec `json:"istio,omitempty"`
+
+	// Nginx configures the NGINX Ingress Controller provider.
+	// +optional
+	Nginx *NginxTrafficRoutingSpec `json:"nginx,omitempty"`
+
+	// SMI configures the Service Mesh Interface provider.
+	// +optional
+	SMI *SMITrafficRoutingSpec `json:"smi,omitempty"`
+
+	// Traefik configures the Traefik provider.
+	// +optional
+	Traefik *TraefikTrafficRoutingSpec `json:"traefik,omitempty"`
+
+	// OpenShiftRoute configures the OpenShift Route traffic router plugin, which is enabled by default.
+	// +optional
+	OpenShiftRoute *OpenShiftRouteTrafficRoutingSpec `json:"openShiftRoute,omitempty"`
+}
+
+// ALBTrafficRoutingSpec configures the AWS Application Load Balancer traffic routing provider.
+type ALBTrafficRoutingSpec struct {
+	// IngressClasses are the ingress classes handled by the AWS Load Balancer Controller (--alb-ingress-classes).
+	// +optional
+	IngressClasses []string `json:"ingressClasses,omitempty"`
+
+	// VerifyTargetGroup enables verification that the target group weights have been applied (--aws-verify-target-group).
+	// +optional
+	VerifyTargetGroup bool `json:"verifyTargetGroup,omitempty"`
+
+	// TargetGroupBindingAPIVersion is the API version of the TargetGroupBinding resource (--aws-target-group-binding-api-version).
+	// +optional
+	TargetGroupBindingAPIVersion string `json:"targetGroupBindingAPIVersion,omitempty"`
+
+	// TagKeyResourceID is the AWS tag key used to identify the resource ID of the load balancer (--alb-tag-key-resource-id).
+	// +optional
+	TagKeyResourceID string `json:"tagKeyResourceID,omitempty"`
+}
+
+// AmbassadorTrafficRoutingSpec configures the Ambassador traffic routing provider.
+type AmbassadorTrafficRoutingSpec struct {
+	// APIVersion is the API version of the Ambassador Mapping resource (--ambassador-api-version).
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// APISIXTrafficRoutingSpec configures the Apache APISIX traffic routing provider.
+type APISIXTrafficRoutingSpec struct {
+}
+
+// AppMeshTrafficRoutingSpec configures the AWS App Mesh traffic routing provider.
+type AppMeshTrafficRoutingSpec struct {
+	// CRDVersion is the version of the App Mesh CRDs (--appmesh-crd-version).
+	// +optional
+	CRDVersion string `json:"crdVersion,omitempty"`
+}
+
+// IstioTrafficRoutingSpec configures the Istio traffic routing provider.
+type IstioTrafficRoutingSpec struct {
+	// APIVersion is the API version of the Istio VirtualService and DestinationRule resources (--istio-api-version).
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// NginxTrafficRoutingSpec configures the NGINX Ingress Controller traffic routing provider.
+type NginxTrafficRoutingSpec struct {
+	// IngressClasses are the ingress classes handled by the NGINX Ingress Controller (--nginx-ingress-classes).
+	// +optional
+	IngressClasses []string `json:"ingressClasses,omitempty"`
+}
+
+// SMITrafficRoutingSpec configures the Service Mesh Interface traffic routing provider.
+type SMITrafficRoutingSpec struct {
+}
+
+// TraefikTrafficRoutingSpec configures the Traefik traffic routing provider.
+type TraefikTrafficRoutingSpec struct {
+	// APIGroup is the API group of the Traefik resources (--traefik-api-group).
+	// +optional
+	APIGroup string `json:"apiGroup,omitempty"`
+
+	// Version is the API version of the Traefik TraefikService resource (--traefik-version).
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// OpenShiftRouteTrafficRoutingSpec configures the OpenShift Route traffic router plugin.
+type OpenShiftRouteTrafficRoutingSpec struct {
+	// Enabled defines whether the OpenShift Route traffic router plugin is added to the Rollouts ConfigMap. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// IsOpenShiftRouteEnabled returns true if the OpenShift Route traffic router plugin is in use, which is the default.
+func (a *RolloutManagerTrafficRoutingSpec) IsOpenShiftRouteEnabled() bool {
+	return a == nil || a.OpenShiftRoute == nil || a.OpenShiftRoute.Enabled == nil || *a.OpenShiftRoute.Enabled
+}
+
+// RolloutManagerRBACSpec defines how the operator computes the rules of the Role/ClusterRole of the Rollouts controller.
+type RolloutManagerRBACSpec struct {
+	// LeastPrivilege defines whether the Role/ClusterRole should only contain a core set of rules, plus the rules required
+	// by the traffic routing providers declared in .spec.trafficRouting. When false (the default), the Role/ClusterRole
+	// contains the rules required by every traffic routing provider supported by Argo Rollouts.
+	// +optional
+	LeastPrivilege bool `json:"leastPrivilege,omitempty"`
+}
+
+// IsLeastPrivilege returns true if the Role/ClusterRole rules should be computed from the declared traffic routing providers.
+func (a *RolloutManagerRBACSpec) IsLeastPrivilege() bool {
+	return a != nil && a.LeastPrivilege
+}
+
+// RolloutManagerDashboardSpec defines the options for the Argo Rollouts Dashboard.
+type RolloutManagerDashboardSpec struct {
+	// Enabled defines whether the Argo Rollouts Dashboard should be deployed.
+	// When disabled, the operator will remove any previously-created Dashboard resources.
+	Enabled bool `json:"enabled"`
+
+	// Image defines Argo Rollouts Dashboard image (optional)
+	Image string `json:"image,omitempty"`
+
+	// Version defines Argo Rollouts Dashboard tag (optional)
+	Version string `json:"version,omitempty"`
+
+	// Resources requests/limits for the Argo Rollouts Dashboard
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodePlacement defines NodeSelectors and Taints for the Argo Rollouts Dashboard
+	NodePlacement *RolloutsNodePlacementSpec `json:"nodePlacement,omitempty"`
+
+	// Ingress defines an Ingress that exposes the Argo Rollouts Dashboard Service
+	Ingress *RolloutManagerDashboardIngressSpec `json:"ingress,omitempty"`
+
+	// Route defines an OpenShift Route that exposes the Argo Rollouts Dashboard Service
+	Route *RolloutManagerDashboardRouteSpec `json:"route,omitempty"`
+}
+
+func (a *RolloutManagerDashboardSpec) IsEnabled() bool {
+	return a != nil && a.Enabled
+}
+
+// RolloutManagerDashboardIngressSpec defines the Ingress for the Argo Rollouts Dashboard.
+type RolloutManagerDashboardIngressSpec struct {
+	// Enabled defines whether an Ingress should be created for the Argo Rollouts Dashboard.
+	Enabled bool `json:"enabled"`
+
+	// Annotations to add to the Ingress.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// IngressClassName is the name of the IngressClass used by the Ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Host is the fully qualified domain name on which the Argo Rollouts Dashboard is exposed.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Path on which the Argo Rollouts Dashboard is exposed. Defaults to '/'.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// TLS configuration for the Ingress.
+	// +optional
+	TLS []networkingv1.IngressTLS `json:"tls,omitempty"`
+}
+
+// RolloutManagerDashboardRouteSpec defines the OpenShift Route for the Argo Rollouts Dashboard.
+type RolloutManagerDashboardRouteSpec struct {
+	// Enabled defines whether an OpenShift Route should be created for the Argo Rollouts Dashboard.
+	Enabled bool `json:"enabled"`
+
+	// Annotations to add to the Route.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Host is the fully qualified domain name on which the Argo Rollouts Dashboard is exposed.
+	// If empty, a host is generated by OpenShift.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// TLSTermination is the TLS termination type of the Route.
+	// If empty, the Route is not secured.
+	// +kubebuilder:validation:Enum=edge;passthrough;reencrypt
+	// +optional
+	TLSTermination string `json:"tlsTermination,omitempty"`
+
+	// InsecureEdgeTerminationPolicy indicates the desired behavior for insecure connections to a secured Route.
+	// +kubebuilder:validation:Enum=Allow;Disable;Redirect
+	// +optional
+	InsecureEdgeTerminationPolicy string `json:"insecureEdgeTerminationPolicy,omitempty"`
+}
+
+// RolloutManagerNotificationsSpec defines the notifications configuration of Argo Rollouts.
+// For more information, see https://argo-rollouts.readthedocs.io/en/stable/features/notifications/
+type RolloutManagerNotificationsSpec struct {
+	// Services defines the notification services (Slack, webhook, email, ...) that notifications are sent to.
+	// +optional
+	Services []NotificationService `json:"services,omitempty"`
+
+	// Templates defines the notification templates, which describe how notifications are rendered.
+	// +optional
+	Templates []NotificationTemplate `json:"templates,omitempty"`
+
+	// Triggers defines the notification triggers, which describe when notifications are sent.
+	// +optional
+	Triggers []NotificationTrigger `json:"triggers,omitempty"`
+
+	// DefaultTriggers is the list of triggers that are used when a subscription does not specify any triggers.
+	// +optional
+	DefaultTriggers []string `json:"defaultTriggers,omitempty"`
+
+	// Subscriptions defines the default subscriptions, which are applied to all Rollouts.
+	// +optional
+	Subscriptions []NotificationSubscription `json:"subscriptions,omitempty"`
+
+	// Context defines user-defined key/value pairs that are available in all notification templates.
+	// +optional
+	Context map[string]string `json:"context,omitempty"`
+}
+
+// NotificationService defines a single notification service. Exactly one of the service types must be set.
+type NotificationService struct {
+	// Name of the service, used to reference the service from subscriptions. Defaults to the type of the service (e.g. 'slack').
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Slack configures a Slack notification service.
+	// +optional
+	Slack *SlackNotificationService `json:"slack,omitempty"`
+
+	// Webhook configures a generic webhook notification service.
+	// +optional
+	Webhook *WebhookNotificationService `json:"webhook,omitempty"`
+
+	// Email configures an email notification service.
+	// +optional
+	Email *EmailNotificationService `json:"email,omitempty"`
+
+	// Custom configures a notification service that is not otherwise supported by this API, using its raw configuration.
+	// +optional
+	Custom *CustomNotificationService `json:"custom,omitempty"`
+}
+
+// SlackNotificationService configures a Slack notification service.
+type SlackNotificationService struct {
+	// TokenSecretRef references the key of a Secret, in the namespace of the RolloutManager, that contains the Slack app token.
+	TokenSecretRef corev1.SecretKeySelector `json:"tokenSecretRef"`
+
+	// Username is the name that notifications are sent as.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Icon is the URL or emoji used as the icon of notifications.
+	// +optional
+	Icon string `json:"icon,omitempty"`
+
+	// APIURL overrides the URL of the Slack API.
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+}
+
+// WebhookNotificationService configures a generic webhook notification service.
+type WebhookNotificationService struct {
+	// URL of the webhook.
+	URL string `json:"url"`
+
+	// Headers are the HTTP headers sent with each request.
+	// +optional
+	Headers []NotificationHeader `json:"headers,omitempty"`
+
+	// BasicAuth configures HTTP basic authentication for the webhook.
+	// +optional
+	BasicAuth *NotificationBasicAuth `json:"basicAuth,omitempty"`
+
+	// InsecureSkipVerify disables TLS certificate verification of the webhook.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// NotificationHeader defines an HTTP header of a webhook notification service. Exactly one of Value or ValueFrom must be set.
+type NotificationHeader struct {
+	// Name of the header.
+	Name string `json:"name"`
+
+	// Value of the header.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom references the key of a Secret, in the namespace of the RolloutManager, that contains the value of the header.
+	// +optional
+	ValueFrom *corev1.SecretKeySelector `json:"valueFrom,omitempty"`
+}
+
+// NotificationBasicAuth configures HTTP basic authentication.
+type NotificationBasicAuth struct {
+	// Username used to authenticate.
+	Username string `json:"username"`
+
+	// PasswordSecretRef references the key of a Secret, in the namespace of the RolloutManager, that contains the password.
+	PasswordSecretRef corev1.SecretKeySelector `json:"passwordSecretRef"`
+}
+
+// EmailNotificationService configures an email notification service.
+type EmailNotificationService struct {
+	// Host of the SMTP server.
+	Host string `json:"host"`
+
+	// Port of the SMTP server.
+	Port int32 `json:"port"`
+
+	// From is the address that notifications are sent from.
+	From string `json:"from"`
+
+	// Username used to authenticate with the SMTP server.
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// PasswordSecretRef references the key of a Secret, in the namespace of the RolloutManager, that contains the SMTP password.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// InsecureSkipVerify disables TLS certificate verification of the SMTP server.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// CustomNotificationService configures a notification service using its raw configuration.
+type CustomNotificationService struct {
+	// Type of the notification service, for example 'teams' or 'opsgenie'.
+	Type string `json:"type"`
+
+	// Config is the raw YAML configuration of the service. Secret values may be referenced as '$<key>', where <key> is the key of an entry in SecretRefs.
+	Config string `json:"config"`
+
+	// SecretRefs maps keys of the notification Secret to the keys of user-owned Secrets, in the namespace of the RolloutManager, that contain their values.
+	// +optional
+	SecretRefs map[string]corev1.SecretKeySelector `json:"secretRefs,omitempty"`
+}
+
+// NotificationTemplate defines how a notification is rendered.
+type NotificationTemplate struct {
+	// Name of the template.
+	Name string `json:"name"`
+
+	// Message is the body of the notification.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Email defines email-specific fields of the notification.
+	// +optional
+	Email *EmailNotificationTemplate `json:"email,omitempty"`
+
+	// Slack defines Slack-specific fields of the notification.
+	// +optional
+	Slack *SlackNotificationTemplate `json:"slack,omitempty"`
+
+	// Webhook defines the requests sent to webhook services, keyed by the name of the webhook service.
+	// +optional
+	Webhook map[string]WebhookNotificationTemplate `json:"webhook,omitempty"`
+}
+
+// EmailNotificationTemplate defines email-specific fields of a notification template.
+type EmailNotificationTemplate struct {
+	// Subject of the email.
+	Subject string `json:"subject"`
+}
+
+// SlackNotificationTemplate defines Slack-specific fields of a notification template.
+type SlackNotificationTemplate struct {
+	// Attachments is the JSON-encoded list of Slack message attachments.
+	// +optional
+	Attachments string `json:"attachments,omitempty"`
+
+	// Blocks is the JSON-encoded list of Slack message blocks.
+	// +optional
+	Blocks string `json:"blocks,omitempty"`
+}
+
+// WebhookNotificationTemplate defines the request sent to a webhook service.
+type WebhookNotificationTemplate struct {
+	// Method is the HTTP method of the request. Defaults to GET.
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Path is appended to the URL of the webhook service.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Body of the request.
+	// +optional
+	Body string `json:"body,omitempty"`
+}
+
+// NotificationTrigger defines when notifications are sent.
+type NotificationTrigger struct {
+	// Name of the trigger.
+	Name string `json:"name"`
+
+	// Conditions of the trigger. A notification is sent for each condition that evaluates to true.
+	Conditions []NotificationTriggerCondition `json:"conditions"`
+}
+
+// NotificationTriggerCondition defines a single condition of a notification trigger.
+type NotificationTriggerCondition struct {
+	// When is the expression that is evaluated against the Rollout.
+	When string `json:"when"`
+
+	// Send is the list of templates used to render the notification.
+	Send []string `json:"send"`
+
+	// Description of the condition.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// OncePer is an expression which ensures that the notification is only sent once per distinct value.
+	// +optional
+	OncePer string `json:"oncePer,omitempty"`
+}
+
+// NotificationSubscription defines a default subscription, which is applied to all Rollouts.
+type NotificationSubscription struct {
+	// Recipients of the notifications, in the form '<service>:<recipient>', for example 'slack:my-channel'.
+	Recipients []string `json:"recipients"`
+
+	// Triggers of the subscription. If empty, the default triggers are used.
+	// +optional
+	Triggers []string `json:"triggers,omitempty"`
+
+	// Selector is a label selector, which limits the subscription to the matching Rollouts.
+	// +optional
+	Selector string `json:"selector,omitempty"`
 }
 
 // RolloutManagerNetworkPolicySpec defines whether the operator should create NetworkPolicies.
@@ -85,10 +713,48 @@ func (a *RolloutManagerNetworkPolicySpec) IsEnabled() bool {
 type Plugin struct {
 	// Name of the plugin, it must match the name required by the plugin so it can find its configuration
 	Name string `json:"name"`
-	// Location supports http(s):// urls and file://, though file:// requires the plugin be available on the filesystem
-	Location string `json:"location"`
+	// Location supports http(s):// urls and file://, though file:// requires the plugin be available on the filesystem.
+	// When empty, the location is resolved from the plugin catalog of the operator, using Name and Version.
+	// +optional
+	Location string `json:"location,omitempty"`
+	// Version of the plugin in the plugin catalog of the operator. Only used when Location is empty.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Image defines a container image that contains the plugin executable, as an alternative to Location.
+	// The operator copies the executable into the Rollouts controller Pod with an init container, and sets the location of the plugin to the copied file.
+	// +optional
+	Image *PluginImageSource `json:"image,omitempty"`
+	// Architectures defines a location and checksum of the plugin executable for each CPU architecture, as an alternative to Location.
+	// The operator schedules the Rollouts controller on nodes of an architecture supported by every plugin, and uses the location of that architecture.
+	// +optional
+	Architectures []PluginArchitecture `json:"architectures,omitempty"`
 	// SHA256 is an optional sha256 checksum of the plugin executable
 	SHA256 string `json:"sha256,omitempty"`
+	// Args are optional command-line arguments passed to the plugin executable. These are primarily used by step plugins.
+	Args []string `json:"args,omitempty"`
+	// Rules are additional RBAC policy rules required by the plugin. They are added to the Role/ClusterRole of the Rollouts controller.
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+// PluginArchitecture defines the location of the plugin executable for a CPU architecture.
+type PluginArchitecture struct {
+	// Architecture of the plugin executable, as reported by the 'kubernetes.io/arch' label of nodes, for example 'amd64' or 'arm64'.
+	Architecture string `json:"architecture"`
+	// Location of the plugin executable for this architecture. Supports http(s):// urls and file://.
+	Location string `json:"location"`
+	// SHA256 is an optional sha256 checksum of the plugin executable for this architecture
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// PluginImageSource defines a container image that contains a plugin executable.
+type PluginImageSource struct {
+	// Reference of the container image, for example 'quay.io/example/plugin:v1.0.0'.
+	// The executable is copied by running the 'cp' command of the image: distroless and 'scratch' images, which do not provide 'cp', are not supported.
+	Reference string `json:"reference"`
+	// Path of the plugin executable within the container image. It must be an absolute path, without '..' elements.
+	Path string `json:"path"`
 }
 
 type Plugins struct {
@@ -96,41 +762,242 @@ type Plugins struct {
 	TrafficManagement []Plugin `json:"trafficManagement,omitempty"`
 	// Metric holds a list of metric plugins used to gather and report metrics during rollouts.
 	Metric []Plugin `json:"metric,omitempty"`
+	// Step holds a list of step plugins, which may be used as steps of a canary rollout.
+	Step []Plugin `json:"step,omitempty"`
+	// Preflight defines whether the operator should verify that each plugin can be downloaded, and that its SHA256 checksum matches, before the plugin is added to the Rollouts ConfigMap.
+	// The result of the verification of each plugin is reported in .status.plugins. Plugins with a file:// location are not verified, as the file only exists within the Rollouts controller Pod.
+	// +optional
+	Preflight bool `json:"preflight,omitempty"`
 }
 
 // RolloutManagerHASpec specifies HA options for High Availability support for Rollouts.
 type RolloutManagerHASpec struct {
 	// Enabled will toggle HA support globally for RolloutManager.
 	Enabled bool `json:"enabled"`
+
+	// Replicas is the number of Rollouts controller pods while HA is enabled. It must be at least 2, and defaults to 2.
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// LeaderElection defines the leader election timing of the Rollouts controller pods while HA is enabled.
+	// +optional
+	LeaderElection *RolloutManagerLeaderElectionSpec `json:"leaderElection,omitempty"`
+
+	// PodDisruptionBudget configures the PodDisruptionBudget of the Rollouts controller, which is created while HA is enabled.
+	// +optional
+	PodDisruptionBudget *RolloutManagerPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+}
+
+// RolloutManagerLeaderElectionSpec defines the leader election timing of the Rollouts controller. Durations that are not set use the default value of the Rollouts controller.
+type RolloutManagerLeaderElectionSpec struct {
+	// LeaseDuration is the duration that non-leader candidates wait before attempting to acquire leadership (--leader-election-lease-duration, default 15s).
+	// +optional
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+
+	// RenewDeadline is the duration that the leader retries refreshing leadership before giving it up (--leader-election-renew-deadline, default 10s).
+	// +optional
+	RenewDeadline *metav1.Duration `json:"renewDeadline,omitempty"`
+
+	// RetryPeriod is the duration that candidates wait between attempts to acquire or renew leadership (--leader-election-retry-period, default 2s).
+	// +optional
+	RetryPeriod *metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// RolloutManagerDeploymentSpec defines the rollout options of the Rollouts controller Deployment. Options that are not set use the Kubernetes defaults.
+type RolloutManagerDeploymentSpec struct {
+	// Strategy is the strategy used to replace the Rollouts controller pods. Defaults to RollingUpdate.
+	// +optional
+	Strategy *appsv1.DeploymentStrategy `json:"strategy,omitempty"`
+
+	// MinReadySeconds is the minimum number of seconds for which a new Rollouts controller pod must be ready to be considered available.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+
+	// RevisionHistoryLimit is the number of old ReplicaSets of the Rollouts controller Deployment to retain.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum number of seconds for the Rollouts controller Deployment to make progress, before it is considered failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// RolloutManagerPodDisruptionBudgetSpec configures the PodDisruptionBudget of the Rollouts controller.
+// At most one of MinAvailable and MaxUnavailable may be set. If neither is set, MinAvailable defaults to 1.
+type RolloutManagerPodDisruptionBudgetSpec struct {
+	// MinAvailable is the number or percentage of Rollouts controller pods that must remain available during a voluntary disruption, such as a node drain.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of Rollouts controller pods that may be unavailable during a voluntary disruption, such as a node drain.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
-// ArgoRolloutsNodePlacementSpec is used to specify NodeSelector and Tolerations for Rollouts workloads
+// ArgoRolloutsNodePlacementSpec is used to specify NodeSelector, Tolerations and other scheduling constraints for Rollouts workloads
 type RolloutsNodePlacementSpec struct {
 	// NodeSelector is a field of PodSpec, it is a map of key value pairs used for node selection
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	// Tolerations allow the pods to schedule onto nodes with matching taints
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity is a field of PodSpec, it defines the node affinity, pod affinity and pod anti-affinity of the pods.
+	// When HA is enabled, a pod anti-affinity set here replaces the pod anti-affinity that the operator adds by default.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// TopologySpreadConstraints is a field of PodSpec, it describes how the pods are spread across topology domains, such as zones
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName is a field of PodSpec, it is the name of the PriorityClass of the pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// RuntimeClassName is a field of PodSpec, it is the name of the RuntimeClass used to run the pods
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+	// SchedulerName is a field of PodSpec, it is the name of the scheduler of the pods. The default scheduler is used if it is empty.
+	// +optional
+	SchedulerName string `json:"schedulerName,omitempty"`
 }
 
 // RolloutManagerStatus defines the observed state of RolloutManager
 type RolloutManagerStatus struct {
 	// RolloutController is a simple, high-level summary of where the RolloutController component is in its lifecycle.
-	// There are three possible RolloutController values:
+	// There are four possible RolloutController values:
 	// Pending: The RolloutController component has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
 	// Running: All of the required Pods for the RolloutController component are in a Ready state.
 	// Unknown: The state of the RolloutController component could not be obtained.
+	// Suspended: The RolloutController component is scaled to zero by .spec.suspend.
 	RolloutController RolloutControllerPhase `json:"rolloutController,omitempty"`
 	// Phase is a simple, high-level summary of where the RolloutManager is in its lifecycle.
-	// There are three possible phase values:
+	// There are four possible phase values:
 	// Pending: The RolloutManager has been accepted by the Kubernetes system, but one or more of the required resources have not been created.
 	// Available: All of the resources for the RolloutManager are ready.
 	// Unknown: The state of the RolloutManager phase could not be obtained.
+	// Suspended: The Rollouts controller is scaled to zero by .spec.suspend.
 	Phase RolloutControllerPhase `json:"phase,omitempty"`
 
+	// Dashboard is a simple, high-level summary of where the Argo Rollouts Dashboard component is in its lifecycle.
+	// It has the same possible values as RolloutController, and is empty when the Dashboard is not enabled.
+	Dashboard RolloutControllerPhase `json:"dashboard,omitempty"`
+
+	// ObservedGeneration is the .metadata.generation of the RolloutManager that the status was last computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	RolloutManagerWorkloadStatus `json:",inline"`
+
+	// Controller reports the effective tuning options of the Argo Rollouts controller, whether set via .spec.controller, via .spec.extraCommandArgs, or by default.
+	// +optional
+	Controller *RolloutManagerControllerSpec `json:"controller,omitempty"`
+
+	// Plugins reports the result of the preflight verification of each plugin, when enabled via .spec.plugins.preflight.
+	// +optional
+	Plugins []RolloutManagerPluginStatus `json:"plugins,omitempty"`
+
+	// PolicyRules reports the effective rules of the Role/ClusterRole of the Rollouts controller.
+	// +optional
+	PolicyRules []rbacv1.PolicyRule `json:"policyRules,omitempty"`
+
+	// Leader reports the Rollouts controller pod that currently holds the leader election Lease, while HA is enabled.
+	// +optional
+	Leader *RolloutManagerLeaderStatus `json:"leader,omitempty"`
+
+	// PlannedChanges reports the changes that the operator would apply to the resources of the Rollouts controller, while .spec.reconcileMode is Observe.
+	// +optional
+	PlannedChanges []RolloutManagerPlannedChange `json:"plannedChanges,omitempty"`
+
 	// Conditions is an array of the RolloutManager's status conditions
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// RolloutManagerWorkloadStatus reports the image, scope and replicas of the Rollouts controller.
+type RolloutManagerWorkloadStatus struct {
+	// Image is the container image of the Rollouts controller, as resolved from .spec.image, .spec.version and the defaults of the operator.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// Version is the tag, or digest, of the container image of the Rollouts controller.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Scope is Cluster when the Rollouts controller watches the whole cluster, and Namespace when it only watches the namespace of the RolloutManager.
+	// +optional
+	Scope RolloutManagerScope `json:"scope,omitempty"`
+	// Replicas is the desired number of Rollouts controller pods.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// ReadyReplicas is the number of Rollouts controller pods that are ready.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+	// UpdatedReplicas is the number of Rollouts controller pods that run the latest revision of the Deployment.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+	// AvailableReplicas is the number of Rollouts controller pods that are available.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+}
+
+// RolloutManagerPluginStatus reports the result of the preflight verification of a plugin.
+type RolloutManagerPluginStatus struct {
+	// Name of the plugin
+	Name string `json:"name"`
+	// Type of the plugin: trafficManagement, metric or step
+	Type string `json:"type"`
+	// Location from which the plugin is downloaded by the Rollouts controller
+	Location string `json:"location"`
+	// SHA256 is the checksum of the plugin executable, as computed by the operator
+	// +optional
+	SHA256 string `json:"sha256,omitempty"`
+	// Conditions of the plugin. The Verified condition reports whether the plugin could be downloaded, and whether its checksum matches.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RolloutManagerLeaderStatus reports the state of the leader election Lease of the Rollouts controller.
+type RolloutManagerLeaderStatus struct {
+	// Pod is the name of the Rollouts controller pod that holds the Lease
+	// +optional
+	Pod string `json:"pod,omitempty"`
+	// HolderIdentity is the identity of the holder of the Lease, as recorded by the Rollouts controller
+	// +optional
+	HolderIdentity string `json:"holderIdentity,omitempty"`
+	// RenewTime is the last time the Lease was renewed by its holder, as observed by the operator. It is refreshed at most once per minute.
+	// +optional
+	RenewTime *metav1.Time `json:"renewTime,omitempty"`
+	// LeaseDurationSeconds is the duration after which the Lease is considered expired, if it is not renewed
+	// +optional
+	LeaseDurationSeconds *int32 `json:"leaseDurationSeconds,omitempty"`
+	// LeaderTransitions is the number of times the leadership has changed hands
+	// +optional
+	LeaderTransitions *int32 `json:"leaderTransitions,omitempty"`
+}
+
+// RolloutManagerPlannedChangeAction is the action that the operator would take on a resource of the Rollouts controller.
+type RolloutManagerPlannedChangeAction string
+
+const (
+	PlannedChangeActionCreate RolloutManagerPlannedChangeAction = "Create"
+	PlannedChangeActionUpdate RolloutManagerPlannedChangeAction = "Update"
+	PlannedChangeActionDelete RolloutManagerPlannedChangeAction = "Delete"
+)
+
+// RolloutManagerPlannedChange reports a change that the operator would apply to a resource of the Rollouts controller.
+type RolloutManagerPlannedChange struct {
+	// Action is Create, Update or Delete
+	Action RolloutManagerPlannedChangeAction `json:"action"`
+	// Kind of the resource
+	Kind string `json:"kind"`
+	// Name of the resource
+	Name string `json:"name"`
+	// Namespace of the resource, empty for cluster-scoped resources
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Fields that would be added, modified or removed by an Update, as field paths (for example, .spec.template.spec.containers[name="argo-rollouts"].image)
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+}
+
 type RolloutControllerPhase string
 
 const (
@@ -138,10 +1005,49 @@ const (
 	PhasePending   RolloutControllerPhase = "Pending"
 	PhaseUnknown   RolloutControllerPhase = "Unknown"
 	PhaseFailure   RolloutControllerPhase = "Failure"
+	PhaseSuspended RolloutControllerPhase = "Suspended"
+)
+
+// +kubebuilder:validation:Enum=Cluster;Namespace
+type RolloutManagerScope string
+
+const (
+	ScopeCluster   RolloutManagerScope = "Cluster"
+	ScopeNamespace RolloutManagerScope = "Namespace"
 )
 
 const (
 	RolloutManagerConditionType = "Reconciled"
+
+	// RolloutManagerConditionTypeAvailable is True while the Rollouts controller (and the Dashboard, when enabled) has the minimum number of available pods.
+	RolloutManagerConditionTypeAvailable = "Available"
+
+	// RolloutManagerConditionTypeProgressing is True while a new revision of the Rollouts controller Deployment is being rolled out.
+	RolloutManagerConditionTypeProgressing = "Progressing"
+
+	// RolloutManagerConditionTypeDegraded is True when the RolloutManager could not be reconciled, the rollout of the Rollouts controller
+	// Deployment exceeded its progress deadline, or the pods of the Rollouts controller are failing.
+	RolloutManagerConditionTypeDegraded = "Degraded"
+
+	// RolloutManagerConditionTypeLeaderLeaseStale is True while HA is enabled and the leader election Lease of the Rollouts controller has no holder,
+	// or has not been renewed within its duration.
+	RolloutManagerConditionTypeLeaderLeaseStale = "LeaderLeaseStale"
+
+	// RolloutManagerConditionTypeControllerHealthy is True while all pods of the Rollouts controller are ready. Otherwise, its reason reports the
+	// failure of the Rollouts controller pods, when one was found.
+	RolloutManagerConditionTypeControllerHealthy = "ControllerHealthy"
+
+	// RolloutManagerConditionTypeFieldManagerConflict is True when fields of the resources of the Rollouts controller, which are set by the operator,
+	// were modified by another field manager. The operator takes these fields over, and restores their desired values, except for the replicas of a Deployment and annotations,
+	// which are left to the other field manager.
+	RolloutManagerConditionTypeFieldManagerConflict = "FieldManagerConflict"
+
+	// RolloutManagerConditionTypeDriftDetected is True while .spec.reconcileMode is Observe, and the resources of the Rollouts controller
+	// differ from their desired state. The changes that would be applied are reported in .status.plannedChanges.
+	RolloutManagerConditionTypeDriftDetected = "DriftDetected"
+
+	// RolloutManagerConditionTypeUnmanaged is True while the reconciliation of the RolloutManager is paused, or some of its components are unmanaged.
+	RolloutManagerConditionTypeUnmanaged = "Unmanaged"
 )
 
 const (
@@ -150,6 +1056,47 @@ const (
 	RolloutManagerReasonMultipleClusterScopedRolloutManager = "MultipleClusterScopedRolloutManager"
 	RolloutManagerReasonInvalidScoped                       = "InvalidRolloutManagerScope"
 	RolloutManagerReasonInvalidNamespace                    = "InvalidRolloutManagerNamespace"
+	RolloutManagerReasonUnknownPluginVersion                = "UnknownPluginVersion"
+	RolloutManagerReasonPluginVerificationFailed            = "PluginVerificationFailed"
+	RolloutManagerReasonUnsupportedPluginArchitecture       = "UnsupportedPluginArchitecture"
+	RolloutManagerReasonInvalidOverride                     = "InvalidOverride"
+	RolloutManagerReasonLeaseRenewed                        = "LeaseRenewed"
+	RolloutManagerReasonLeaseNotRenewed                     = "LeaseNotRenewed"
+	RolloutManagerReasonNoLeader                            = "NoLeader"
+	RolloutManagerReasonControllerAvailable                 = "ControllerAvailable"
+	RolloutManagerReasonSuspended                           = "Suspended"
+	RolloutManagerReasonControllerNotReady                  = "ControllerNotReady"
+	RolloutManagerReasonImagePullFailed                     = "ImagePullFailed"
+	RolloutManagerReasonControllerCrashLooping              = "ControllerCrashLooping"
+	RolloutManagerReasonControllerOOMKilled                 = "ControllerOOMKilled"
+	RolloutManagerReasonControllerUnschedulable             = "ControllerUnschedulable"
+	RolloutManagerReasonQuotaExceeded                       = "QuotaExceeded"
+	RolloutManagerReasonPodCreationFailed                   = "PodCreationFailed"
+	RolloutManagerReasonPluginInstallFailed                 = "PluginInstallFailed"
+	RolloutManagerReasonMinimumReplicasAvailable            = "MinimumReplicasAvailable"
+	RolloutManagerReasonMinimumReplicasUnavailable          = "MinimumReplicasUnavailable"
+	RolloutManagerReasonDeploymentNotFound                  = "DeploymentNotFound"
+	RolloutManagerReasonDashboardUnavailable                = "DashboardUnavailable"
+	RolloutManagerReasonRolloutInProgress                   = "RolloutInProgress"
+	RolloutManagerReasonRolloutComplete                     = "RolloutComplete"
+	RolloutManagerReasonProgressDeadlineExceeded            = "ProgressDeadlineExceeded"
+	RolloutManagerReasonAsExpected                          = "AsExpected"
+	RolloutManagerReasonConflictingFieldManagers            = "ConflictingFieldManagers"
+	RolloutManagerReasonResourcesOutOfSync                  = "ResourcesOutOfSync"
+	RolloutManagerReasonReconciliationPaused                = "ReconciliationPaused"
+	RolloutManagerReasonComponentsUnmanaged                 = "ComponentsUnmanaged"
+)
+
+const (
+	// PluginConditionTypeVerified is the type of the condition of a plugin that reports the result of its preflight verification.
+	PluginConditionTypeVerified = "Verified"
+)
+
+const (
+	PluginReasonVerified         = "Verified"
+	PluginReasonChecksumMismatch = "ChecksumMismatch"
+	PluginReasonDownloadFailed   = "DownloadFailed"
+	PluginReasonNotVerifiable    = "NotVerifiable"
 )
 
 type ResourceMetadata struct {
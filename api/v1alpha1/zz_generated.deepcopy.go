@@ -21,63 +21,876 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Plugin) DeepCopyInto(out *Plugin) {
+func (in *ALBTrafficRoutingSpec) DeepCopyInto(out *ALBTrafficRoutingSpec) {
 	*out = *in
+	if in.IngressClasses != nil {
+		in, out := &in.IngressClasses, &out.IngressClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
-func (in *Plugin) DeepCopy() *Plugin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ALBTrafficRoutingSpec.
+func (in *ALBTrafficRoutingSpec) DeepCopy() *ALBTrafficRoutingSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Plugin)
+	out := new(ALBTrafficRoutingSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Plugins) DeepCopyInto(out *Plugins) {
+func (in *APISIXTrafficRoutingSpec) DeepCopyInto(out *APISIXTrafficRoutingSpec) {
 	*out = *in
-	if in.TrafficManagement != nil {
-		in, out := &in.TrafficManagement, &out.TrafficManagement
-		*out = make([]Plugin, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APISIXTrafficRoutingSpec.
+func (in *APISIXTrafficRoutingSpec) DeepCopy() *APISIXTrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APISIXTrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmbassadorTrafficRoutingSpec) DeepCopyInto(out *AmbassadorTrafficRoutingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmbassadorTrafficRoutingSpec.
+func (in *AmbassadorTrafficRoutingSpec) DeepCopy() *AmbassadorTrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AmbassadorTrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppMeshTrafficRoutingSpec) DeepCopyInto(out *AppMeshTrafficRoutingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppMeshTrafficRoutingSpec.
+func (in *AppMeshTrafficRoutingSpec) DeepCopy() *AppMeshTrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppMeshTrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomNotificationService) DeepCopyInto(out *CustomNotificationService) {
+	*out = *in
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make(map[string]v1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomNotificationService.
+func (in *CustomNotificationService) DeepCopy() *CustomNotificationService {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomNotificationService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailNotificationService) DeepCopyInto(out *EmailNotificationService) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailNotificationService.
+func (in *EmailNotificationService) DeepCopy() *EmailNotificationService {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailNotificationService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailNotificationTemplate) DeepCopyInto(out *EmailNotificationTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailNotificationTemplate.
+func (in *EmailNotificationTemplate) DeepCopy() *EmailNotificationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailNotificationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioTrafficRoutingSpec) DeepCopyInto(out *IstioTrafficRoutingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioTrafficRoutingSpec.
+func (in *IstioTrafficRoutingSpec) DeepCopy() *IstioTrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioTrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NginxTrafficRoutingSpec) DeepCopyInto(out *NginxTrafficRoutingSpec) {
+	*out = *in
+	if in.IngressClasses != nil {
+		in, out := &in.IngressClasses, &out.IngressClasses
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Metric != nil {
-		in, out := &in.Metric, &out.Metric
-		*out = make([]Plugin, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NginxTrafficRoutingSpec.
+func (in *NginxTrafficRoutingSpec) DeepCopy() *NginxTrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NginxTrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationBasicAuth) DeepCopyInto(out *NotificationBasicAuth) {
+	*out = *in
+	in.PasswordSecretRef.DeepCopyInto(&out.PasswordSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationBasicAuth.
+func (in *NotificationBasicAuth) DeepCopy() *NotificationBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationHeader) DeepCopyInto(out *NotificationHeader) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationHeader.
+func (in *NotificationHeader) DeepCopy() *NotificationHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationService) DeepCopyInto(out *NotificationService) {
+	*out = *in
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackNotificationService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookNotificationService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(EmailNotificationService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(CustomNotificationService)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationService.
+func (in *NotificationService) DeepCopy() *NotificationService {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSubscription) DeepCopyInto(out *NotificationSubscription) {
+	*out = *in
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugins.
-func (in *Plugins) DeepCopy() *Plugins {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSubscription.
+func (in *NotificationSubscription) DeepCopy() *NotificationSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTemplate) DeepCopyInto(out *NotificationTemplate) {
+	*out = *in
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(EmailNotificationTemplate)
+		**out = **in
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(SlackNotificationTemplate)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = make(map[string]WebhookNotificationTemplate, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTemplate.
+func (in *NotificationTemplate) DeepCopy() *NotificationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTrigger) DeepCopyInto(out *NotificationTrigger) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]NotificationTriggerCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTrigger.
+func (in *NotificationTrigger) DeepCopy() *NotificationTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTriggerCondition) DeepCopyInto(out *NotificationTriggerCondition) {
+	*out = *in
+	if in.Send != nil {
+		in, out := &in.Send, &out.Send
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTriggerCondition.
+func (in *NotificationTriggerCondition) DeepCopy() *NotificationTriggerCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTriggerCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenShiftRouteTrafficRoutingSpec) DeepCopyInto(out *OpenShiftRouteTrafficRoutingSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenShiftRouteTrafficRoutingSpec.
+func (in *OpenShiftRouteTrafficRoutingSpec) DeepCopy() *OpenShiftRouteTrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenShiftRouteTrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Plugin) DeepCopyInto(out *Plugin) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(PluginImageSource)
+		**out = **in
+	}
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]PluginArchitecture, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
+func (in *Plugin) DeepCopy() *Plugin {
 	if in == nil {
 		return nil
 	}
-	out := new(Plugins)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceMetadata) DeepCopyInto(out *ResourceMetadata) {
-	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out := new(Plugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginArchitecture) DeepCopyInto(out *PluginArchitecture) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginArchitecture.
+func (in *PluginArchitecture) DeepCopy() *PluginArchitecture {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginArchitecture)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginImageSource) DeepCopyInto(out *PluginImageSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginImageSource.
+func (in *PluginImageSource) DeepCopy() *PluginImageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginImageSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Plugins) DeepCopyInto(out *Plugins) {
+	*out = *in
+	if in.TrafficManagement != nil {
+		in, out := &in.TrafficManagement, &out.TrafficManagement
+		*out = make([]Plugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = make([]Plugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Step != nil {
+		in, out := &in.Step, &out.Step
+		*out = make([]Plugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugins.
+func (in *Plugins) DeepCopy() *Plugins {
+	if in == nil {
+		return nil
+	}
+	out := new(Plugins)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceMetadata) DeepCopyInto(out *ResourceMetadata) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceMetadata.
+func (in *ResourceMetadata) DeepCopy() *ResourceMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManager) DeepCopyInto(out *RolloutManager) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManager.
+func (in *RolloutManager) DeepCopy() *RolloutManager {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutManager) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerControllerSpec) DeepCopyInto(out *RolloutManagerControllerSpec) {
+	*out = *in
+	if in.RolloutThreads != nil {
+		in, out := &in.RolloutThreads, &out.RolloutThreads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExperimentThreads != nil {
+		in, out := &in.ExperimentThreads, &out.ExperimentThreads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AnalysisThreads != nil {
+		in, out := &in.AnalysisThreads, &out.AnalysisThreads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QPS != nil {
+		in, out := &in.QPS, &out.QPS
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RolloutResync != nil {
+		in, out := &in.RolloutResync, &out.RolloutResync
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KLogLevel != nil {
+		in, out := &in.KLogLevel, &out.KLogLevel
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerControllerSpec.
+func (in *RolloutManagerControllerSpec) DeepCopy() *RolloutManagerControllerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerControllerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerDashboardIngressSpec) DeepCopyInto(out *RolloutManagerDashboardIngressSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]networkingv1.IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerDashboardIngressSpec.
+func (in *RolloutManagerDashboardIngressSpec) DeepCopy() *RolloutManagerDashboardIngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerDashboardIngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerDashboardRouteSpec) DeepCopyInto(out *RolloutManagerDashboardRouteSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerDashboardRouteSpec.
+func (in *RolloutManagerDashboardRouteSpec) DeepCopy() *RolloutManagerDashboardRouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerDashboardRouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerDashboardSpec) DeepCopyInto(out *RolloutManagerDashboardSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodePlacement != nil {
+		in, out := &in.NodePlacement, &out.NodePlacement
+		*out = new(RolloutsNodePlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(RolloutManagerDashboardIngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(RolloutManagerDashboardRouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerDashboardSpec.
+func (in *RolloutManagerDashboardSpec) DeepCopy() *RolloutManagerDashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerDashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerDeploymentSpec) DeepCopyInto(out *RolloutManagerDeploymentSpec) {
+	*out = *in
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinReadySeconds != nil {
+		in, out := &in.MinReadySeconds, &out.MinReadySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerDeploymentSpec.
+func (in *RolloutManagerDeploymentSpec) DeepCopy() *RolloutManagerDeploymentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerDeploymentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerHASpec) DeepCopyInto(out *RolloutManagerHASpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LeaderElection != nil {
+		in, out := &in.LeaderElection, &out.LeaderElection
+		*out = new(RolloutManagerLeaderElectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(RolloutManagerPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerHASpec.
+func (in *RolloutManagerHASpec) DeepCopy() *RolloutManagerHASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerHASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerLeaderElectionSpec) DeepCopyInto(out *RolloutManagerLeaderElectionSpec) {
+	*out = *in
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewDeadline != nil {
+		in, out := &in.RenewDeadline, &out.RenewDeadline
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RetryPeriod != nil {
+		in, out := &in.RetryPeriod, &out.RetryPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerLeaderElectionSpec.
+func (in *RolloutManagerLeaderElectionSpec) DeepCopy() *RolloutManagerLeaderElectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerLeaderElectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerLeaderStatus) DeepCopyInto(out *RolloutManagerLeaderStatus) {
+	*out = *in
+	if in.RenewTime != nil {
+		in, out := &in.RenewTime, &out.RenewTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LeaseDurationSeconds != nil {
+		in, out := &in.LeaseDurationSeconds, &out.LeaseDurationSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LeaderTransitions != nil {
+		in, out := &in.LeaderTransitions, &out.LeaderTransitions
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerLeaderStatus.
+func (in *RolloutManagerLeaderStatus) DeepCopy() *RolloutManagerLeaderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerLeaderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerList) DeepCopyInto(out *RolloutManagerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RolloutManager, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerList.
+func (in *RolloutManagerList) DeepCopy() *RolloutManagerList {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutManagerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerNetworkPolicySpec) DeepCopyInto(out *RolloutManagerNetworkPolicySpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerNetworkPolicySpec.
+func (in *RolloutManagerNetworkPolicySpec) DeepCopy() *RolloutManagerNetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerNetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerNotificationsSpec) DeepCopyInto(out *RolloutManagerNotificationsSpec) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]NotificationService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]NotificationTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]NotificationTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultTriggers != nil {
+		in, out := &in.DefaultTriggers, &out.DefaultTriggers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subscriptions != nil {
+		in, out := &in.Subscriptions, &out.Subscriptions
+		*out = make([]NotificationSubscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
+	if in.Context != nil {
+		in, out := &in.Context, &out.Context
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
@@ -85,106 +898,109 @@ func (in *ResourceMetadata) DeepCopyInto(out *ResourceMetadata) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceMetadata.
-func (in *ResourceMetadata) DeepCopy() *ResourceMetadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerNotificationsSpec.
+func (in *RolloutManagerNotificationsSpec) DeepCopy() *RolloutManagerNotificationsSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceMetadata)
+	out := new(RolloutManagerNotificationsSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutManager) DeepCopyInto(out *RolloutManager) {
+func (in *RolloutManagerOverride) DeepCopyInto(out *RolloutManagerOverride) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManager.
-func (in *RolloutManager) DeepCopy() *RolloutManager {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerOverride.
+func (in *RolloutManagerOverride) DeepCopy() *RolloutManagerOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutManager)
+	out := new(RolloutManagerOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutManager) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutManagerHASpec) DeepCopyInto(out *RolloutManagerHASpec) {
+func (in *RolloutManagerPlannedChange) DeepCopyInto(out *RolloutManagerPlannedChange) {
 	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerHASpec.
-func (in *RolloutManagerHASpec) DeepCopy() *RolloutManagerHASpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerPlannedChange.
+func (in *RolloutManagerPlannedChange) DeepCopy() *RolloutManagerPlannedChange {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutManagerHASpec)
+	out := new(RolloutManagerPlannedChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutManagerList) DeepCopyInto(out *RolloutManagerList) {
+func (in *RolloutManagerPluginStatus) DeepCopyInto(out *RolloutManagerPluginStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]RolloutManager, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerList.
-func (in *RolloutManagerList) DeepCopy() *RolloutManagerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerPluginStatus.
+func (in *RolloutManagerPluginStatus) DeepCopy() *RolloutManagerPluginStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutManagerList)
+	out := new(RolloutManagerPluginStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutManagerList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerPodDisruptionBudgetSpec) DeepCopyInto(out *RolloutManagerPodDisruptionBudgetSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
-	return nil
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerPodDisruptionBudgetSpec.
+func (in *RolloutManagerPodDisruptionBudgetSpec) DeepCopy() *RolloutManagerPodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerPodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutManagerNetworkPolicySpec) DeepCopyInto(out *RolloutManagerNetworkPolicySpec) {
+func (in *RolloutManagerRBACSpec) DeepCopyInto(out *RolloutManagerRBACSpec) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerNetworkPolicySpec.
-func (in *RolloutManagerNetworkPolicySpec) DeepCopy() *RolloutManagerNetworkPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerRBACSpec.
+func (in *RolloutManagerRBACSpec) DeepCopy() *RolloutManagerRBACSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutManagerNetworkPolicySpec)
+	out := new(RolloutManagerRBACSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -209,6 +1025,21 @@ func (in *RolloutManagerSpec) DeepCopyInto(out *RolloutManagerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Controller != nil {
+		in, out := &in.Controller, &out.Controller
+		*out = new(RolloutManagerControllerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrafficRouting != nil {
+		in, out := &in.TrafficRouting, &out.TrafficRouting
+		*out = new(RolloutManagerTrafficRoutingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RBAC != nil {
+		in, out := &in.RBAC, &out.RBAC
+		*out = new(RolloutManagerRBACSpec)
+		**out = **in
+	}
 	if in.NodePlacement != nil {
 		in, out := &in.NodePlacement, &out.NodePlacement
 		*out = new(RolloutsNodePlacementSpec)
@@ -224,11 +1055,64 @@ func (in *RolloutManagerSpec) DeepCopyInto(out *RolloutManagerSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Plugins.DeepCopyInto(&out.Plugins)
 	if in.HA != nil {
 		in, out := &in.HA, &out.HA
 		*out = new(RolloutManagerHASpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(RolloutManagerDeploymentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dashboard != nil {
+		in, out := &in.Dashboard, &out.Dashboard
+		*out = new(RolloutManagerDashboardSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(RolloutManagerNotificationsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]RolloutManagerOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnmanagedComponents != nil {
+		in, out := &in.UnmanagedComponents, &out.UnmanagedComponents
+		*out = make([]RolloutManagerComponent, len(*in))
+		copy(*out, *in)
 	}
 }
 
@@ -245,6 +1129,38 @@ func (in *RolloutManagerSpec) DeepCopy() *RolloutManagerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RolloutManagerStatus) DeepCopyInto(out *RolloutManagerStatus) {
 	*out = *in
+	out.RolloutManagerWorkloadStatus = in.RolloutManagerWorkloadStatus
+	if in.Controller != nil {
+		in, out := &in.Controller, &out.Controller
+		*out = new(RolloutManagerControllerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]RolloutManagerPluginStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PolicyRules != nil {
+		in, out := &in.PolicyRules, &out.PolicyRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Leader != nil {
+		in, out := &in.Leader, &out.Leader
+		*out = new(RolloutManagerLeaderStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]RolloutManagerPlannedChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -264,6 +1180,81 @@ func (in *RolloutManagerStatus) DeepCopy() *RolloutManagerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerTrafficRoutingSpec) DeepCopyInto(out *RolloutManagerTrafficRoutingSpec) {
+	*out = *in
+	if in.ALB != nil {
+		in, out := &in.ALB, &out.ALB
+		*out = new(ALBTrafficRoutingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ambassador != nil {
+		in, out := &in.Ambassador, &out.Ambassador
+		*out = new(AmbassadorTrafficRoutingSpec)
+		**out = **in
+	}
+	if in.APISIX != nil {
+		in, out := &in.APISIX, &out.APISIX
+		*out = new(APISIXTrafficRoutingSpec)
+		**out = **in
+	}
+	if in.AppMesh != nil {
+		in, out := &in.AppMesh, &out.AppMesh
+		*out = new(AppMeshTrafficRoutingSpec)
+		**out = **in
+	}
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(IstioTrafficRoutingSpec)
+		**out = **in
+	}
+	if in.Nginx != nil {
+		in, out := &in.Nginx, &out.Nginx
+		*out = new(NginxTrafficRoutingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SMI != nil {
+		in, out := &in.SMI, &out.SMI
+		*out = new(SMITrafficRoutingSpec)
+		**out = **in
+	}
+	if in.Traefik != nil {
+		in, out := &in.Traefik, &out.Traefik
+		*out = new(TraefikTrafficRoutingSpec)
+		**out = **in
+	}
+	if in.OpenShiftRoute != nil {
+		in, out := &in.OpenShiftRoute, &out.OpenShiftRoute
+		*out = new(OpenShiftRouteTrafficRoutingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerTrafficRoutingSpec.
+func (in *RolloutManagerTrafficRoutingSpec) DeepCopy() *RolloutManagerTrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerTrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutManagerWorkloadStatus) DeepCopyInto(out *RolloutManagerWorkloadStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutManagerWorkloadStatus.
+func (in *RolloutManagerWorkloadStatus) DeepCopy() *RolloutManagerWorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutManagerWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RolloutsNodePlacementSpec) DeepCopyInto(out *RolloutsNodePlacementSpec) {
 	*out = *in
@@ -281,6 +1272,23 @@ func (in *RolloutsNodePlacementSpec) DeepCopyInto(out *RolloutsNodePlacementSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutsNodePlacementSpec.
@@ -292,3 +1300,106 @@ func (in *RolloutsNodePlacementSpec) DeepCopy() *RolloutsNodePlacementSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SMITrafficRoutingSpec) DeepCopyInto(out *SMITrafficRoutingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMITrafficRoutingSpec.
+func (in *SMITrafficRoutingSpec) DeepCopy() *SMITrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SMITrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackNotificationService) DeepCopyInto(out *SlackNotificationService) {
+	*out = *in
+	in.TokenSecretRef.DeepCopyInto(&out.TokenSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackNotificationService.
+func (in *SlackNotificationService) DeepCopy() *SlackNotificationService {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackNotificationService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlackNotificationTemplate) DeepCopyInto(out *SlackNotificationTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackNotificationTemplate.
+func (in *SlackNotificationTemplate) DeepCopy() *SlackNotificationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SlackNotificationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TraefikTrafficRoutingSpec) DeepCopyInto(out *TraefikTrafficRoutingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraefikTrafficRoutingSpec.
+func (in *TraefikTrafficRoutingSpec) DeepCopy() *TraefikTrafficRoutingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TraefikTrafficRoutingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookNotificationService) DeepCopyInto(out *WebhookNotificationService) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]NotificationHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(NotificationBasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookNotificationService.
+func (in *WebhookNotificationService) DeepCopy() *WebhookNotificationService {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookNotificationService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookNotificationTemplate) DeepCopyInto(out *WebhookNotificationTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookNotificationTemplate.
+func (in *WebhookNotificationTemplate) DeepCopy() *WebhookNotificationTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookNotificationTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
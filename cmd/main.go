@@ -18,6 +18,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"strings"
 
@@ -25,10 +26,18 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -72,6 +81,8 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	rolloutsControllerSelector := labels.SelectorFromSet(labels.Set{controllers.DefaultRolloutsSelectorKey: controllers.DefaultArgoRolloutsResourceName})
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: server.Options{
@@ -95,16 +106,40 @@ func main() {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
+
+		// Only the pods and ReplicaSets of the Rollouts controller are read by the operator, so the other pods and ReplicaSets of the cluster are not cached.
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.Pod{}:        {Label: rolloutsControllerSelector},
+				&appsv1.ReplicaSet{}: {Label: rolloutsControllerSelector},
+			},
+		},
+
+		// The leader election Lease of the Rollouts controller is renewed every few seconds, and is only read when a RolloutManager is reconciled, so it is not cached.
+		// The ServiceCIDRs are only listed hourly by the plugin preflight verification, which is only granted the list verb, so they are not cached either.
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&coordinationv1.Lease{}, &networkingv1.ServiceCIDR{}},
+			},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// When not set, the OpenShift Route plugin is resolved from the plugin catalog
 	openShiftRoutePluginLocation := os.Getenv("OPENSHIFT_ROUTE_PLUGIN_LOCATION")
 
-	if openShiftRoutePluginLocation == "" {
-		openShiftRoutePluginLocation = controllers.DefaultOpenShiftRoutePluginURL
+	// The plugin catalog ConfigMap is referenced as '<namespace>/<name>'
+	pluginCatalogConfigMap := types.NamespacedName{}
+	if value := os.Getenv(controllers.PluginCatalogConfigMap); value != "" {
+		namespace, name, found := strings.Cut(value, "/")
+		if !found || namespace == "" || name == "" {
+			setupLog.Error(fmt.Errorf("invalid value '%s', expected '<namespace>/<name>'", value), "unable to parse "+controllers.PluginCatalogConfigMap)
+			os.Exit(1)
+		}
+		pluginCatalogConfigMap = types.NamespacedName{Namespace: namespace, Name: name}
 	}
 
 	isNamespaceScoped := strings.ToLower(os.Getenv(controllers.NamespaceScopedArgoRolloutsController)) == "true"
@@ -130,10 +165,23 @@ func main() {
 		Scheme:                                mgr.GetScheme(),
 		OpenShiftRoutePluginLocation:          openShiftRoutePluginLocation,
 		NamespaceScopedArgoRolloutsController: isNamespaceScoped,
+		PluginCatalogConfigMap:                pluginCatalogConfigMap,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RolloutManager")
 		os.Exit(1)
 	}
+
+	if strings.ToLower(os.Getenv(controllers.EnableWebhooks)) == "true" {
+		if err = (&controllers.RolloutManagerValidator{
+			Client:                                mgr.GetClient(),
+			NamespaceScopedArgoRolloutsController: isNamespaceScoped,
+			PluginCatalogConfigMap:                pluginCatalogConfigMap,
+			OpenShiftRoutePluginLocation:          openShiftRoutePluginLocation,
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RolloutManager")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,64 @@
+package rollouts
+
+import (
+	"fmt"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// rolloutsContainerName is the name of the Rollouts controller container.
+	rolloutsContainerName = "argo-rollouts"
+
+	// tmpVolumeName is the name of the volume mounted at /tmp in the Rollouts controller container.
+	tmpVolumeName = "tmp"
+
+	// tmpMountPath is the path at which the tmp volume is mounted.
+	tmpMountPath = "/tmp"
+)
+
+// validateAdditionalVolumesAndContainers returns an error if the volumes, volume mounts, init containers or sidecar containers of the RolloutManager
+// collide with each other, or with those generated by the operator.
+func validateAdditionalVolumesAndContainers(cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	volumes := map[string]bool{pluginBinVolumeName: true, tmpVolumeName: true}
+	for _, volume := range cr.Spec.Volumes {
+		if volume.Name == "" {
+			return fmt.Errorf("the name of a volume in .spec.volumes must not be empty")
+		}
+		if volumes[volume.Name] {
+			return fmt.Errorf("volume %s of .spec.volumes is already defined", volume.Name)
+		}
+		volumes[volume.Name] = true
+	}
+
+	mountPaths := map[string]bool{pluginBinMountPath: true, tmpMountPath: true}
+	for _, volumeMount := range cr.Spec.VolumeMounts {
+		if !volumes[volumeMount.Name] {
+			return fmt.Errorf("volume mount %s of .spec.volumeMounts does not reference a volume", volumeMount.Name)
+		}
+		if mountPaths[volumeMount.MountPath] {
+			return fmt.Errorf("mount path %s of .spec.volumeMounts is already used", volumeMount.MountPath)
+		}
+		mountPaths[volumeMount.MountPath] = true
+	}
+
+	// Init containers and containers share the same namespace of names
+	containers := map[string]bool{rolloutsContainerName: true}
+	for _, container := range append(append([]corev1.Container{}, cr.Spec.InitContainers...), cr.Spec.SidecarContainers...) {
+		if container.Name == "" {
+			return fmt.Errorf("the name of a container in .spec.initContainers or .spec.sidecarContainers must not be empty")
+		}
+		if strings.HasPrefix(container.Name, pluginImageInitContainerPrefix) {
+			return fmt.Errorf("container name %s is reserved for the init containers of plugins, as it starts with '%s'", container.Name, pluginImageInitContainerPrefix)
+		}
+		if containers[container.Name] {
+			return fmt.Errorf("container %s of .spec.initContainers or .spec.sidecarContainers is already defined", container.Name)
+		}
+		containers[container.Name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package rollouts
+
+import (
+	"context"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Additional volumes and containers tests", func() {
+
+	withAdditionalVolumesAndContainers := func(rm *v1alpha1.RolloutManager) {
+		rm.Spec.Volumes = []corev1.Volume{{
+			Name:         "ca-bundle",
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "ca-bundle"}}},
+		}}
+		rm.Spec.VolumeMounts = []corev1.VolumeMount{{Name: "ca-bundle", MountPath: "/etc/ssl/certs/ca-bundle", ReadOnly: true}}
+		rm.Spec.InitContainers = []corev1.Container{{Name: "wait-for-proxy", Image: "quay.io/test/wait:v1"}}
+		rm.Spec.SidecarContainers = []corev1.Container{{Name: "log-shipper", Image: "quay.io/test/log-shipper:v1"}}
+	}
+
+	DescribeTable("should reject volumes and containers that collide", func(opt func(*v1alpha1.RolloutManager), expectedError string) {
+		cr := *makeTestRolloutManager(withAdditionalVolumesAndContainers, opt)
+		Expect(validateAdditionalVolumesAndContainers(cr)).To(MatchError(expectedError))
+	},
+		Entry("with a volume named like a volume of the operator", func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Volumes = append(rm.Spec.Volumes, corev1.Volume{Name: "tmp"})
+		}, "volume tmp of .spec.volumes is already defined"),
+		Entry("with a volume mount that does not reference a volume", func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.VolumeMounts = append(rm.Spec.VolumeMounts, corev1.VolumeMount{Name: "missing", MountPath: "/missing"})
+		}, "volume mount missing of .spec.volumeMounts does not reference a volume"),
+		Entry("with a volume mount at the path of a volume mount of the operator", func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.VolumeMounts[0].MountPath = "/tmp"
+		}, "mount path /tmp of .spec.volumeMounts is already used"),
+		Entry("with a sidecar container named like the Rollouts controller container", func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.SidecarContainers[0].Name = "argo-rollouts"
+		}, "container argo-rollouts of .spec.initContainers or .spec.sidecarContainers is already defined"),
+		Entry("with a sidecar container named like an init container", func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.SidecarContainers[0].Name = "wait-for-proxy"
+		}, "container wait-for-proxy of .spec.initContainers or .spec.sidecarContainers is already defined"),
+		Entry("with an init container named like a plugin init container", func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.InitContainers[0].Name = "install-plugin-test"
+		}, "container name install-plugin-test is reserved for the init containers of plugins, as it starts with 'install-plugin-'"),
+	)
+
+	It("should add the volumes and containers after those of the operator", func() {
+		cr := *makeTestRolloutManager(withAdditionalVolumesAndContainers)
+
+		deployment, err := generateDesiredRolloutsDeployment(cr, corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}})
+		Expect(err).ToNot(HaveOccurred())
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(getVolumeNames(podSpec.Volumes)).To(Equal([]string{"plugin-bin", "tmp", "ca-bundle"}))
+		Expect(getContainerNames(podSpec.Containers)).To(Equal([]string{"argo-rollouts", "log-shipper"}))
+		Expect(getContainerNames(podSpec.InitContainers)).To(Equal([]string{"wait-for-proxy"}))
+		Expect(podSpec.Containers[0].VolumeMounts).To(HaveLen(3))
+		Expect(podSpec.Containers[0].VolumeMounts[2]).To(Equal(cr.Spec.VolumeMounts[0]))
+	})
+
+	Context("reconciling the Deployment", func() {
+
+		var (
+			ctx context.Context
+			a   v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+			sa  *corev1.ServiceAccount
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			a = *makeTestRolloutManager(withAdditionalVolumesAndContainers)
+			r = makeTestReconciler(&a)
+			Expect(createNamespace(r, a.Namespace)).To(Succeed())
+
+			sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace}}
+			Expect(r.Client.Create(ctx, sa)).To(Succeed())
+		})
+
+		It("should not revert the defaults set by the API server on the declared volumes and containers", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+
+			By("simulating the defaults set by the API server")
+			defaultMode := corev1.ConfigMapVolumeSourceDefaultMode
+			podSpec := &deployment.Spec.Template.Spec
+			podSpec.Volumes[2].ConfigMap.DefaultMode = &defaultMode
+			podSpec.Containers[1].TerminationMessagePath = corev1.TerminationMessagePathDefault
+			podSpec.Containers[1].ImagePullPolicy = corev1.PullIfNotPresent
+			podSpec.InitContainers[0].TerminationMessagePolicy = corev1.TerminationMessageReadFile
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+			resourceVersion := deployment.ResourceVersion
+
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.ResourceVersion).To(Equal(resourceVersion))
+		})
+
+		It("should revert changes to the declared volumes and containers, and remove them once they are no longer declared", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			deployment.Spec.Template.Spec.Containers[1].Image = "quay.io/test/log-shipper:v2"
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[1].Image).To(Equal("quay.io/test/log-shipper:v1"))
+
+			a.Spec.Volumes, a.Spec.VolumeMounts, a.Spec.InitContainers, a.Spec.SidecarContainers = nil, nil, nil, nil
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+
+			podSpec := deployment.Spec.Template.Spec
+			Expect(getVolumeNames(podSpec.Volumes)).To(Equal([]string{"plugin-bin", "tmp"}))
+			Expect(getContainerNames(podSpec.Containers)).To(Equal([]string{"argo-rollouts"}))
+			Expect(podSpec.InitContainers).To(BeEmpty())
+			Expect(podSpec.Containers[0].VolumeMounts).To(HaveLen(2))
+		})
+	})
+})
+
+// getContainerNames returns the names of the containers, in order.
+func getContainerNames(containers []corev1.Container) []string {
+	names := []string{}
+	for _, container := range containers {
+		names = append(names, container.Name)
+	}
+	return names
+}
+
+// getVolumeNames returns the names of the volumes, in order.
+func getVolumeNames(volumes []corev1.Volume) []string {
+	names := []string{}
+	for _, volume := range volumes {
+		names = append(names, volume.Name)
+	}
+	return names
+}
@@ -0,0 +1,291 @@
+package rollouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// maxFieldManagerConflicts is the maximum number of conflicts listed in the message of the FieldManagerConflict condition.
+	maxFieldManagerConflicts = 10
+)
+
+// legacyFieldManagers are the field managers of the previous versions of the operator, which updated the resources instead of applying them.
+// The field manager of an update defaults to the name of the executable: 'manager' in the image of the operator, and 'main' when run via 'go run'.
+var legacyFieldManagers = []string{"manager", "main"}
+
+// unforcedFieldPaths are the fields set by the operator that other field managers are expected to own, for example the replicas of a Deployment,
+// which are scaled by autoscalers, and annotations, which are set by other tools. A conflict on these fields is reported, but the fields are left to the other field manager.
+var unforcedFieldPaths = [][]string{
+	{"spec", "replicas"},
+	{"metadata", "annotations"},
+	{"spec", "template", "metadata", "annotations"},
+}
+
+// applyConflictsContextKey is the key of the applyConflicts of a reconciliation, in its context.
+type applyConflictsContextKey struct{}
+
+// applyConflicts collects the conflicts with other field managers that were found while applying the resources of a RolloutManager.
+type applyConflicts struct {
+	// conflicts are the conflicts on fields which were taken over by the operator, to restore their desired values
+	conflicts []string
+	// unforcedConflicts are the conflicts on fields which other field managers are expected to own, and which were left to them
+	unforcedConflicts []string
+}
+
+// withApplyConflicts returns a context in which the conflicts found by applyObject are collected into the returned applyConflicts.
+func withApplyConflicts(ctx context.Context) (context.Context, *applyConflicts) {
+	conflicts := &applyConflicts{}
+	return context.WithValue(ctx, applyConflictsContextKey{}, conflicts), conflicts
+}
+
+// getFieldManagerConflictCondition returns the FieldManagerConflict condition of the RolloutManager, based on the collected conflicts.
+func (c *applyConflicts) getFieldManagerConflictCondition() metav1.Condition {
+
+	if len(c.conflicts) == 0 && len(c.unforcedConflicts) == 0 {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeFieldManagerConflict, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonAsExpected, "")
+	}
+
+	messages := []string{}
+	if len(c.conflicts) > 0 {
+		messages = append(messages, "fields set by the operator were modified by another field manager, and have been restored: "+formatFieldManagerConflicts(c.conflicts))
+	}
+	if len(c.unforcedConflicts) > 0 {
+		messages = append(messages, "fields set by the operator were modified by another field manager which is expected to own them, and have been left as is: "+formatFieldManagerConflicts(c.unforcedConflicts))
+	}
+
+	return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeFieldManagerConflict, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonConflictingFieldManagers,
+		strings.Join(messages, ". "))
+}
+
+// formatFieldManagerConflicts returns the sorted conflicts, separated by semicolons, listing at most maxFieldManagerConflicts of them.
+func formatFieldManagerConflicts(conflicts []string) string {
+
+	conflicts = append([]string{}, conflicts...)
+	sort.Strings(conflicts)
+	if len(conflicts) > maxFieldManagerConflicts {
+		conflicts = append(conflicts[:maxFieldManagerConflicts], fmt.Sprintf("and %d more", len(conflicts)-maxFieldManagerConflicts))
+	}
+	return strings.Join(conflicts, "; ")
+}
+
+// applyObject server-side applies obj, which contains the desired state of a resource, as the FieldManager of the operator.
+// The operator only owns the fields that are set in obj: fields set by other field managers (for example, labels added by users, or sidecars injected by a service mesh) are left untouched,
+// while fields that the operator previously set, but which are no longer set in obj, are removed.
+// If another field manager modified a field owned by the operator, the conflict is recorded in the applyConflicts of the context, and the operator takes the field over to restore its desired value,
+// unless the field is one of unforcedFieldPaths, which is left to the other field manager.
+// On success, obj contains the live state of the resource.
+func (r *RolloutManagerReconciler) applyObject(ctx context.Context, obj client.Object) error {
+
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	// desired is applied instead of obj, as obj is replaced with the live state of the resource by each apply request
+	desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	err = r.apply(ctx, gvk, desired, obj)
+	if err == nil {
+		upgraded, err := r.upgradeManagedFields(ctx, gvk, obj)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade the managed fields of %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+		if !upgraded {
+			return nil
+		}
+
+		// Apply again, so that the fields which were set by the previous versions of the operator, but are no longer set, are removed
+		if err := r.apply(ctx, gvk, desired, obj); err != nil {
+			return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+		return nil
+	}
+
+	conflicts := getFieldManagerConflicts(err)
+	if len(conflicts) == 0 {
+		return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+
+	// A conflict with the fields that a previous version of the operator set with a different value is resolved by upgrading the managed fields of the resource
+	upgraded, err := r.upgradeLiveManagedFields(ctx, gvk, obj)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade the managed fields of %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+	if upgraded {
+		if err = r.apply(ctx, gvk, desired, obj); err == nil {
+			return nil
+		}
+		if conflicts = getFieldManagerConflicts(err); len(conflicts) == 0 {
+			return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, obj.GetName(), err)
+		}
+	}
+
+	forced, unforced := []string{}, []string{}
+	for _, conflict := range conflicts {
+		description := fmt.Sprintf("%s (%s)", conflict.Field, conflict.Message)
+		if path := getUnforcedFieldPath(conflict.Field); path != nil {
+			unstructured.RemoveNestedField(desired, path...)
+			unforced = append(unforced, description)
+		} else {
+			forced = append(forced, description)
+		}
+	}
+
+	collected, _ := ctx.Value(applyConflictsContextKey{}).(*applyConflicts)
+
+	if len(unforced) > 0 {
+		log.Info(fmt.Sprintf("Leaving the fields of %s %s which were modified by another field manager to it: %s", gvk.Kind, obj.GetName(), strings.Join(unforced, ", ")))
+		if collected != nil {
+			collected.unforcedConflicts = append(collected.unforcedConflicts, fmt.Sprintf("%s %s: %s", gvk.Kind, obj.GetName(), strings.Join(unforced, ", ")))
+		}
+	}
+
+	opts := []client.ApplyOption{}
+	if len(forced) > 0 {
+		log.Info(fmt.Sprintf("Taking over the fields of %s %s which were modified by another field manager: %s", gvk.Kind, obj.GetName(), strings.Join(forced, ", ")))
+		if collected != nil {
+			collected.conflicts = append(collected.conflicts, fmt.Sprintf("%s %s: %s", gvk.Kind, obj.GetName(), strings.Join(forced, ", ")))
+		}
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	if err := r.apply(ctx, gvk, desired, obj, opts...); err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+
+	return nil
+}
+
+// getUnforcedFieldPath returns the path of a field of unforcedFieldPaths, or of a key of one of them, in the format of the field of a conflict, or nil if the field is not one of them.
+func getUnforcedFieldPath(field string) []string {
+
+	for _, path := range unforcedFieldPaths {
+		prefix := "." + strings.Join(path, ".")
+		if field == prefix {
+			return path
+		}
+		// Annotations are a map, whose keys are listed as fields of the map
+		if key, found := strings.CutPrefix(field, prefix+"."); found {
+			return append(append([]string{}, path...), key)
+		}
+	}
+	return nil
+}
+
+// apply sends desired, the unstructured content of a resource, as an apply configuration, as the FieldManager of the operator, and replaces the contents of obj with the live state of the resource.
+func (r *RolloutManagerReconciler) apply(ctx context.Context, gvk schema.GroupVersionKind, desired map[string]interface{}, obj client.Object, opts ...client.ApplyOption) error {
+
+	// An apply configuration must contain the kind of the resource, and must not contain the fields which are managed by the API server
+	u := &unstructured.Unstructured{Object: runtime.DeepCopyJSON(desired)}
+	u.SetGroupVersionKind(gvk)
+	u.SetResourceVersion("")
+	u.SetManagedFields(nil)
+
+	if err := r.Client.Apply(ctx, client.ApplyConfigurationFromUnstructured(u), append([]client.ApplyOption{client.FieldOwner(FieldManager)}, opts...)...); err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj)
+}
+
+// upgradeManagedFields moves the ownership of the fields of live, the live state of a resource, that were set by the previous versions of the operator, which updated
+// the resources instead of applying them, to the FieldManager of the operator. Otherwise, these fields would remain owned by the previous field managers,
+// and would not be removed once the operator stops setting them. It returns whether the managed fields were upgraded, which is only the case when a previous
+// field manager of the operator is present.
+func (r *RolloutManagerReconciler) upgradeManagedFields(ctx context.Context, gvk schema.GroupVersionKind, live client.Object) (bool, error) {
+
+	// In Observe mode, the writes of the operator are only planned, and the managed fields of the resource are left as is
+	if _, observing := r.Client.(*observingClient); observing {
+		return false, nil
+	}
+
+	if !hasLegacyFieldManager(live) {
+		return false, nil
+	}
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(live, sets.New(legacyFieldManagers...), FieldManager)
+	if err != nil || patch == nil {
+		return false, err
+	}
+
+	log.Info(fmt.Sprintf("Upgrading the managed fields of %s %s to server-side apply", gvk.Kind, live.GetName()))
+	if err := r.Client.Patch(ctx, live, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// upgradeLiveManagedFields fetches the live state of the resource of obj, and upgrades its managed fields with upgradeManagedFields. It is used when the live state
+// of the resource is not known, as an apply request failed.
+func (r *RolloutManagerReconciler) upgradeLiveManagedFields(ctx context.Context, gvk schema.GroupVersionKind, obj client.Object) (bool, error) {
+
+	var live client.Object
+	if _, isUnstructured := obj.(*unstructured.Unstructured); isUnstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		live = u
+	} else {
+		newObj, err := r.Scheme.New(gvk)
+		if err != nil {
+			return false, err
+		}
+		live = newObj.(client.Object)
+	}
+
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return r.upgradeManagedFields(ctx, gvk, live)
+}
+
+// hasLegacyFieldManager returns whether a field of the resource is owned by a previous version of the operator, which updated the resources instead of applying them.
+func hasLegacyFieldManager(obj client.Object) bool {
+
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Operation == metav1.ManagedFieldsOperationUpdate && slices.Contains(legacyFieldManagers, entry.Manager) {
+			return true
+		}
+	}
+	return false
+}
+
+// getFieldManagerConflicts returns the causes of the conflicts with other field managers that caused an apply request to fail, or nil if the error is not such a conflict.
+func getFieldManagerConflicts(err error) []metav1.StatusCause {
+
+	var statusErr apierrors.APIStatus
+	if !apierrors.IsConflict(err) || !errors.As(err, &statusErr) || statusErr.Status().Details == nil {
+		return nil
+	}
+
+	var res []metav1.StatusCause
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			res = append(res, cause)
+		}
+	}
+	return res
+}
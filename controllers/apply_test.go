@@ -0,0 +1,191 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Server-side apply tests", func() {
+
+	var (
+		ctx context.Context
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		r = makeTestReconciler()
+		Expect(createNamespace(r, testNamespace)).To(Succeed())
+	})
+
+	newServiceAccount := func(labels map[string]string) *corev1.ServiceAccount {
+		return &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: testNamespace, Labels: labels},
+		}
+	}
+
+	It("should keep the fields of other field managers, restore the modified fields of the operator, and remove the fields which are no longer applied", func() {
+		Expect(r.applyObject(ctx, newServiceAccount(map[string]string{"app": "rollouts", "team": "a"}))).To(Succeed())
+
+		sa := &corev1.ServiceAccount{}
+		Expect(fetchObject(ctx, r.Client, testNamespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		Expect(sa.Labels).To(Equal(map[string]string{"app": "rollouts", "team": "a"}))
+		resourceVersion := sa.ResourceVersion
+
+		By("not updating the resource when it is up to date")
+		Expect(r.applyObject(ctx, newServiceAccount(map[string]string{"app": "rollouts", "team": "a"}))).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, testNamespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		Expect(sa.ResourceVersion).To(Equal(resourceVersion))
+
+		By("adding a label, and modifying a label of the operator, as another field manager")
+		sa.Labels["my-label"] = "my-value"
+		sa.Labels["team"] = "b"
+		Expect(r.Client.Update(ctx, sa)).To(Succeed())
+
+		By("applying the resource without the label of the team")
+		conflictsCtx, conflicts := withApplyConflicts(ctx)
+		Expect(r.applyObject(conflictsCtx, newServiceAccount(map[string]string{"app": "rollouts"}))).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, testNamespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		Expect(sa.Labels).To(Equal(map[string]string{"app": "rollouts", "team": "b", "my-label": "my-value"}),
+			"the label of the team is now owned by the other field manager, and so should not be removed")
+		Expect(conflicts.getFieldManagerConflictCondition().Status).To(Equal(metav1.ConditionFalse))
+
+		By("applying the label of the team again, which conflicts with the other field manager")
+		Expect(r.applyObject(conflictsCtx, newServiceAccount(map[string]string{"app": "rollouts", "team": "a"}))).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, testNamespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		Expect(sa.Labels).To(Equal(map[string]string{"app": "rollouts", "team": "a", "my-label": "my-value"}))
+
+		condition := conflicts.getFieldManagerConflictCondition()
+		Expect(condition.Type).To(Equal(v1alpha1.RolloutManagerConditionTypeFieldManagerConflict))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonConflictingFieldManagers))
+		Expect(condition.Message).To(ContainSubstring("ServiceAccount argo-rollouts: .metadata.labels.team"))
+	})
+
+	It("should limit the number of conflicts listed in the FieldManagerConflict condition", func() {
+		conflicts := &applyConflicts{}
+		for i := 0; i < maxFieldManagerConflicts+2; i++ {
+			conflicts.conflicts = append(conflicts.conflicts, fmt.Sprintf("ConfigMap cm-%02d: .data.key", i))
+		}
+
+		condition := conflicts.getFieldManagerConflictCondition()
+		Expect(condition.Message).To(ContainSubstring("ConfigMap cm-09: .data.key; and 2 more"))
+		Expect(condition.Message).ToNot(ContainSubstring("cm-10"))
+	})
+
+	It("should take over the fields set by the previous versions of the operator, which updated the resources instead of applying them", func() {
+		By("creating the resource as a previous version of the operator, and as a user")
+		sa := newServiceAccount(map[string]string{"app": "rollouts", "team": "a"})
+		Expect(r.Client.Create(ctx, sa, client.FieldOwner(legacyFieldManagers[0]))).To(Succeed())
+		sa.Labels["my-label"] = "my-value"
+		Expect(r.Client.Update(ctx, sa, client.FieldOwner("kubectl-edit"))).To(Succeed())
+
+		By("applying the resource without the label of the team")
+		conflictsCtx, conflicts := withApplyConflicts(ctx)
+		Expect(r.applyObject(conflictsCtx, newServiceAccount(map[string]string{"app": "rollouts"}))).To(Succeed())
+		Expect(conflicts.conflicts).To(BeEmpty())
+
+		Expect(fetchObject(ctx, r.Client, testNamespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		Expect(sa.Labels).To(Equal(map[string]string{"app": "rollouts", "my-label": "my-value"}))
+
+		managers := []string{}
+		for _, entry := range sa.ManagedFields {
+			managers = append(managers, entry.Manager)
+		}
+		Expect(managers).To(ConsistOf(FieldManager, "kubectl-edit"))
+	})
+
+	It("should take over the fields that the previous versions of the operator set to another value, without reporting a conflict", func() {
+		Expect(r.Client.Create(ctx, newServiceAccount(map[string]string{"app": "rollouts", "team": "a"}), client.FieldOwner(legacyFieldManagers[1]))).To(Succeed())
+
+		conflictsCtx, conflicts := withApplyConflicts(ctx)
+		Expect(r.applyObject(conflictsCtx, newServiceAccount(map[string]string{"app": "rollouts", "team": "b"}))).To(Succeed())
+		Expect(conflicts.conflicts).To(BeEmpty())
+
+		sa := &corev1.ServiceAccount{}
+		Expect(fetchObject(ctx, r.Client, testNamespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		Expect(sa.Labels).To(Equal(map[string]string{"app": "rollouts", "team": "b"}))
+		Expect(hasLegacyFieldManager(sa)).To(BeFalse())
+	})
+
+	It("should only send the apply request, once no field is owned by the previous versions of the operator", func() {
+		Expect(r.applyObject(ctx, newServiceAccount(map[string]string{"app": "rollouts"}))).To(Succeed())
+
+		requests := []string{}
+		r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				requests = append(requests, "get")
+				return c.Get(ctx, key, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				requests = append(requests, "patch")
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+				requests = append(requests, "apply")
+				return c.Apply(ctx, obj, opts...)
+			},
+		})
+
+		Expect(r.applyObject(ctx, newServiceAccount(map[string]string{"app": "rollouts", "team": "a"}))).To(Succeed())
+		Expect(requests).To(Equal([]string{"apply"}))
+	})
+
+	It("should set the FieldManagerConflict condition when reconciling the RolloutManager", func() {
+		rm := makeTestRolloutManager()
+		r = makeTestReconciler(rm)
+		Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+		os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		defer os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: rm.Name, Namespace: rm.Namespace}}
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+		condition := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeFieldManagerConflict)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+		By("modifying the metrics Service of the operator, as another field manager")
+		service := &corev1.Service{}
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsMetricsServiceName, service)).To(Succeed())
+		service.Spec.Ports[0].Name = "my-metrics"
+		Expect(r.Client.Update(ctx, service)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+		condition = meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeFieldManagerConflict)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("Service " + DefaultArgoRolloutsMetricsServiceName))
+
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsMetricsServiceName, service)).To(Succeed())
+		Expect(service.Spec.Ports[0].Name).To(Equal("metrics"))
+
+		By("reconciling again, without conflicts, which should clear the condition")
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+		condition = meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeFieldManagerConflict)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonAsExpected))
+	})
+})
@@ -18,16 +18,19 @@ package rollouts
 
 import (
 	"context"
+	"sync"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -56,6 +59,13 @@ type RolloutManagerReconciler struct {
 	// NamespaceScopedArgoRolloutsController is used to configure scope of Argo Rollouts controller
 	// If value is true then deploy namespace-scoped Argo Rollouts controller else cluster-scoped
 	NamespaceScopedArgoRolloutsController bool
+
+	// PluginCatalogConfigMap references the ConfigMap that extends or overrides the embedded plugin catalog. Ignored when the name is empty.
+	PluginCatalogConfigMap types.NamespacedName
+
+	// pluginVerifier verifies the plugins of RolloutManagers with .spec.plugins.preflight enabled, and caches the results. Created on first use.
+	pluginVerifier     *pluginVerifier
+	pluginVerifierOnce sync.Once
 }
 
 var log = logr.Log.WithName("rollouts-controller")
@@ -94,9 +104,14 @@ const (
 //+kubebuilder:rbac:groups="x.getambassador.io",resources=ambassadormappings;mappings,verbs=create;watch;get;update;list;delete
 //+kubebuilder:rbac:groups="apisix.apache.org",resources=apisixroutes,verbs=watch;get;update
 //+kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=create;watch;get;update;patch;list
+//+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes;grpcroutes;tcproutes;tlsroutes;udproutes,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=create;watch;get;update;patch;list
 //+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;get;list;patch;update;watch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=servicecidrs,verbs=list
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;patch;update;watch
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=delete
+//+kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -148,7 +163,33 @@ func (r *RolloutManagerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return reconcile.Result{}, err
 	}
 
-	res, reconcileErr := r.reconcileRolloutsManager(ctx, *rolloutManager)
+	var res reconcileStatusResult
+	var reconcileErr error
+	switch {
+	case rolloutManager.Spec.Paused:
+		// Leave the resources untouched, for example while they are hot-patched during an incident, and only report their status
+		reqLogger.Info("Skipping reconciliation of the resources of the RolloutManager, as it is paused")
+		res = getPausedStatusResult(*rolloutManager)
+	case isObserveModeEnabled(*rolloutManager):
+		// Only report the changes that would be applied to the resources, in .status.plannedChanges
+		res, reconcileErr = r.observeRolloutsManager(ctx, *rolloutManager)
+	default:
+		res, reconcileErr = r.reconcileRolloutsManager(ctx, *rolloutManager)
+		res.plannedChanges = []rolloutsmanagerv1alpha1.RolloutManagerPlannedChange{}
+		res.removedConditions = append(res.removedConditions, rolloutsmanagerv1alpha1.RolloutManagerConditionTypeDriftDetected)
+	}
+
+	if condition := getUnmanagedCondition(*rolloutManager); condition != nil {
+		res.conditions = append(res.conditions, *condition)
+	} else {
+		res.removedConditions = append(res.removedConditions, rolloutsmanagerv1alpha1.RolloutManagerConditionTypeUnmanaged)
+	}
+
+	// Report the state of the Rollouts controller workload, whether or not the reconciliation succeeded
+	workloadStatusErr := r.setWorkloadStatus(ctx, *rolloutManager, &res)
+	if workloadStatusErr != nil {
+		log.Error(workloadStatusErr, "unable to determine the state of the Rollouts controller")
+	}
 
 	// Set the condition/phase on the RolloutManager status  (before we check the error from reconcileRolloutManager, below)
 	if err := updateStatusConditionOfRolloutManager(ctx, res, rolloutManager, r.Client, log); err != nil {
@@ -161,6 +202,15 @@ func (r *RolloutManagerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return reconcile.Result{}, reconcileErr
 	}
 
+	if workloadStatusErr != nil {
+		return reconcile.Result{}, workloadStatusErr
+	}
+
+	// The leader election Lease is not watched, so periodically check whether it is still renewed
+	if isHAEnabled(*rolloutManager) && !isSuspended(*rolloutManager) {
+		return reconcile.Result{RequeueAfter: leaderStatusRefreshInterval}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -185,28 +235,52 @@ func (r *RolloutManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Watch for changes to Secret sub-resources owned by RolloutManager.
 	bld.Owns(&corev1.Secret{})
 
+	// Watch for changes to user-owned Secrets that are referenced by .spec.notifications of a RolloutManager.
+	bld.Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRolloutManagersReferencingSecret))
+
+	// Watch for changes to user-owned ConfigMaps that are mounted into the Rollouts controller via .spec.volumes, which are part of its config hash.
+	bld.Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRolloutManagersReferencingConfigMap))
+
 	// Watch for changes to Service sub-resources owned by RolloutManager.
 	bld.Owns(&corev1.Service{})
 
 	// Watch for changes to Deployment sub-resources owned by RolloutManager.
 	bld.Owns(&appsv1.Deployment{})
 
+	// Watch for changes to the pods of the Rollouts controller, which are owned by its ReplicaSets, to promptly report their failures in the ControllerHealthy condition.
+	bld.Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRolloutManagersInNamespace), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetLabels()[DefaultRolloutsSelectorKey] == DefaultArgoRolloutsResourceName
+	})))
+
+	// Watch for changes to PodDisruptionBudget sub-resources owned by RolloutManager.
+	bld.Owns(&policyv1.PodDisruptionBudget{})
+
 	// Watch for changes to Role sub-resources owned by RolloutManager.
 	bld.Owns(&rbacv1.Role{})
 
 	// Watch for changes to RoleBinding sub-resources owned by RolloutManager.
 	bld.Owns(&rbacv1.RoleBinding{})
 
+	// Watch for changes to Ingress sub-resources (used by the Dashboard) owned by RolloutManager.
+	bld.Owns(&networkingv1.Ingress{})
+
 	// We can't use Owns for ClusterRole/ClusterRoleBinding, because namespace-scoped resources like RolloutManager cannot own cluster-scoped resources like ClusterRole/ClusterRoleBinding.
-	// Instead, we watch all ClusterRoles/ClusterRoleBindings with the name DefaultArgoRolloutsResourceName, and when they change, we inform all RolloutManagers
+	// Instead, we watch all ClusterRoles/ClusterRoleBindings with the name DefaultArgoRolloutsResourceName (or DefaultArgoRolloutsDashboardResourceName), and when they change, we inform all RolloutManagers
 	bld.Watches(&rbacv1.ClusterRole{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRolloutManagers), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
-		return object.GetName() == DefaultArgoRolloutsResourceName
+		return object.GetName() == DefaultArgoRolloutsResourceName || object.GetName() == DefaultArgoRolloutsDashboardResourceName
 	})))
 
 	bld.Watches(&rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRolloutManagers), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
-		return object.GetName() == DefaultArgoRolloutsResourceName
+		return object.GetName() == DefaultArgoRolloutsResourceName || object.GetName() == DefaultArgoRolloutsDashboardResourceName
 	})))
 
+	// Watch for changes to the plugin catalog ConfigMap, which may change the plugins of every RolloutManager
+	if r.PluginCatalogConfigMap.Name != "" {
+		bld.Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRolloutManagers), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return object.GetName() == r.PluginCatalogConfigMap.Name && object.GetNamespace() == r.PluginCatalogConfigMap.Namespace
+		})))
+	}
+
 	if crdExists, err := r.doesCRDExist(mgr.GetConfig(), serviceMonitorsCRDName); err != nil {
 		return err
 	} else if crdExists {
@@ -286,6 +360,26 @@ func (r *RolloutManagerReconciler) enqueueAllRolloutManagers(ctx context.Context
 
 }
 
+// enqueueRolloutManagersInNamespace lists the RolloutManagers in the namespace of the object, and adds them to the list of resources to be reconciled.
+func (r *RolloutManagerReconciler) enqueueRolloutManagersInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+
+	var rolloutManagerList rolloutsmanagerv1alpha1.RolloutManagerList
+
+	if err := r.Client.List(ctx, &rolloutManagerList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Unable to list RolloutManagers in enqueueRolloutManagersInNamespace")
+		return []reconcile.Request{}
+	}
+
+	var res []reconcile.Request
+
+	for idx := range rolloutManagerList.Items {
+		rm := rolloutManagerList.Items[idx]
+		res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+	}
+
+	return res
+}
+
 // doesCRDExist checks if a CRD is present in the cluster, by using the discovery client.
 //
 // NOTE: this function should only be called from SetupWithManager. There are more efficient methods to determine this, elsewhere.
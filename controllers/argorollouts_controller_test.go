@@ -478,6 +478,42 @@ var _ = Describe("RolloutManagerReconciler tests", func() {
 		})
 	})
 
+	When(".spec.controller and .spec.extraCommandArgs tune the Rollouts controller", func() {
+
+		It("should report the effective tuning options in .status.controller", func() {
+
+			rolloutThreads := int32(20)
+			rm.Spec.Controller = &rolloutsmanagerv1alpha1.RolloutManagerControllerSpec{
+				RolloutThreads: &rolloutThreads,
+				LogFormat:      "json",
+			}
+			rm.Spec.ExtraCommandArgs = []string{"--qps=60"}
+
+			r := makeTestReconciler(rm)
+			Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      rm.Name,
+					Namespace: rm.Namespace,
+				},
+			}
+
+			_, err := r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(rm), rm)).To(Succeed())
+			Expect(rm.Status.Controller).ToNot(BeNil())
+			Expect(*rm.Status.Controller.RolloutThreads).To(Equal(int32(20)))
+			Expect(rm.Status.Controller.LogFormat).To(Equal("json"))
+			Expect(*rm.Status.Controller.QPS).To(Equal(int32(60)))
+
+			By("verifying that options which are not set report the controller default")
+			Expect(*rm.Status.Controller.AnalysisThreads).To(Equal(int32(defaultControllerAnalysisThreads)))
+			Expect(rm.Status.Controller.LogLevel).To(Equal(defaultControllerLogLevel))
+		})
+	})
+
 	When("a RolloutManager is deleted in a namespace", func() {
 
 		DescribeTable("we should delete the ClusterRoles/ClusterRoleBindings that exist, both when the rolloutmanager no longer exists and when the namespace of the rolloutmanager no longer exists", func(namespaceofRolloutManagerStillExists bool) {
@@ -0,0 +1,161 @@
+package rollouts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// getRolloutsConfigHash returns a hash of the configuration that the Rollouts controller reads on startup: the plugins of the argo-rollouts-config ConfigMap,
+// the notification ConfigMap and Secret, and the ConfigMaps and Secrets mounted via .spec.volumes.
+// The hash is set as an annotation of the pod template of the Rollouts controller Deployment, so that a change of the configuration rolls out new pods via the strategy of the Deployment.
+func (r *RolloutManagerReconciler) getRolloutsConfigHash(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (string, error) {
+
+	configHash := sha256.New()
+
+	configMapNames, secretNames := getRolloutsConfigInputNames(cr)
+
+	for _, name := range configMapNames {
+		configMap := &corev1.ConfigMap{}
+		if err := fetchObject(ctx, r.Client, cr.Namespace, name, configMap); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return "", fmt.Errorf("failed to get the ConfigMap %s: %w", name, err)
+			}
+			writeConfigHashEntry(configHash, "ConfigMap", name, nil)
+			continue
+		}
+
+		values := map[string][]byte{}
+		for key, value := range configMap.Data {
+			values[key] = []byte(value)
+		}
+		for key, value := range configMap.BinaryData {
+			values[key] = value
+		}
+
+		// Only the plugins are read from the argo-rollouts-config ConfigMap by the Rollouts controller on startup
+		if name == DefaultRolloutsConfigMapName {
+			for key := range values {
+				if key != TrafficRouterPluginConfigMapKey && key != MetricPluginConfigMapKey && key != StepPluginConfigMapKey {
+					delete(values, key)
+				}
+			}
+		}
+
+		writeConfigHashEntry(configHash, "ConfigMap", name, values)
+	}
+
+	for _, name := range secretNames {
+		secret := &corev1.Secret{}
+		if err := fetchObject(ctx, r.Client, cr.Namespace, name, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return "", fmt.Errorf("failed to get the Secret %s: %w", name, err)
+			}
+			writeConfigHashEntry(configHash, "Secret", name, nil)
+			continue
+		}
+
+		writeConfigHashEntry(configHash, "Secret", name, secret.Data)
+	}
+
+	return hex.EncodeToString(configHash.Sum(nil)), nil
+}
+
+// getRolloutsConfigInputNames returns the names of the ConfigMaps and Secrets whose contents are included in the config hash of the Rollouts controller, sorted by name.
+func getRolloutsConfigInputNames(cr rolloutsmanagerv1alpha1.RolloutManager) ([]string, []string) {
+
+	configMapNames := map[string]bool{
+		DefaultRolloutsConfigMapName:             true,
+		DefaultRolloutsNotificationConfigMapName: true,
+	}
+	secretNames := map[string]bool{
+		DefaultRolloutsNotificationSecretName: true,
+	}
+
+	for _, volume := range cr.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			configMapNames[volume.ConfigMap.Name] = true
+		}
+		if volume.Secret != nil {
+			secretNames[volume.Secret.SecretName] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMapNames[source.ConfigMap.Name] = true
+				}
+				if source.Secret != nil {
+					secretNames[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+
+	return getSortedKeys(configMapNames), getSortedKeys(secretNames)
+}
+
+// writeConfigHashEntry writes the kind and name of a ConfigMap or Secret, and its values sorted by key, to the config hash. A nil map is written for a resource that does not exist.
+func writeConfigHashEntry(configHash hash.Hash, kind string, name string, values map[string][]byte) {
+
+	fmt.Fprintf(configHash, "%s/%s\x00", kind, name)
+	if values == nil {
+		fmt.Fprint(configHash, "absent\x00")
+		return
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(configHash, "%s\x00%d\x00", key, len(values[key]))
+		configHash.Write(values[key])
+	}
+}
+
+// getSortedKeys returns the keys of the map, sorted.
+func getSortedKeys(set map[string]bool) []string {
+	res := make([]string, 0, len(set))
+	for key := range set {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// enqueueRolloutManagersReferencingConfigMap returns a reconcile request for each RolloutManager, in the namespace of the given ConfigMap, whose config hash includes the ConfigMap.
+func (r *RolloutManagerReconciler) enqueueRolloutManagersReferencingConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+
+	var rolloutManagerList rolloutsmanagerv1alpha1.RolloutManagerList
+
+	if err := r.Client.List(ctx, &rolloutManagerList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Unable to list RolloutManagers in enqueueRolloutManagersReferencingConfigMap")
+		return []reconcile.Request{}
+	}
+
+	var res []reconcile.Request
+
+	for idx := range rolloutManagerList.Items {
+		rm := rolloutManagerList.Items[idx]
+		configMapNames, _ := getRolloutsConfigInputNames(rm)
+		for _, name := range configMapNames {
+			if name == obj.GetName() {
+				res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+				break
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,136 @@
+package rollouts
+
+import (
+	"context"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Config hash tests", func() {
+
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+		sa  *corev1.ServiceAccount
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Volumes = []corev1.Volume{
+				{Name: "ca-bundle", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "my-ca-bundle"}}}},
+				{Name: "credentials", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+					{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "my-credentials"}}},
+				}}}},
+			}
+			rm.Spec.VolumeMounts = []corev1.VolumeMount{
+				{Name: "ca-bundle", MountPath: "/etc/ssl/my-ca-bundle"},
+				{Name: "credentials", MountPath: "/etc/credentials"},
+			}
+		})
+		r = makeTestReconciler(&a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+
+		sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace}}
+		Expect(r.Client.Create(ctx, sa)).To(Succeed())
+	})
+
+	It("should return the ConfigMaps and Secrets of the configuration, including the mounted volumes", func() {
+		configMapNames, secretNames := getRolloutsConfigInputNames(a)
+		Expect(configMapNames).To(Equal([]string{DefaultRolloutsConfigMapName, DefaultRolloutsNotificationConfigMapName, "my-ca-bundle"}))
+		Expect(secretNames).To(Equal([]string{DefaultRolloutsNotificationSecretName, "my-credentials"}))
+	})
+
+	It("should only change the config hash when the configuration of the Rollouts controller changes", func() {
+		configHash, err := r.getRolloutsConfigHash(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("creating the argo-rollouts-config ConfigMap")
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+		Expect(r.getRolloutsConfigHash(ctx, a)).ToNot(Equal(configHash))
+		configHash, err = r.getRolloutsConfigHash(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("adding a key which is not read by the Rollouts controller to the argo-rollouts-config ConfigMap")
+		configMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+		configMap.Data["my-key"] = "my-value"
+		Expect(r.Client.Update(ctx, configMap)).To(Succeed())
+		Expect(r.getRolloutsConfigHash(ctx, a)).To(Equal(configHash))
+
+		By("creating a mounted ConfigMap")
+		caBundle := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-ca-bundle", Namespace: a.Namespace},
+			Data:       map[string]string{"ca.crt": "my-certificate"},
+		}
+		Expect(r.Client.Create(ctx, caBundle)).To(Succeed())
+		Expect(r.getRolloutsConfigHash(ctx, a)).ToNot(Equal(configHash))
+		configHash, err = r.getRolloutsConfigHash(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("updating a mounted Secret of a projected volume")
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-credentials", Namespace: a.Namespace},
+			Data:       map[string][]byte{"token": []byte("my-token")},
+		}
+		Expect(r.Client.Create(ctx, credentials)).To(Succeed())
+		Expect(r.getRolloutsConfigHash(ctx, a)).ToNot(Equal(configHash))
+		configHash, err = r.getRolloutsConfigHash(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+
+		credentials.Data["token"] = []byte("my-new-token")
+		Expect(r.Client.Update(ctx, credentials)).To(Succeed())
+		Expect(r.getRolloutsConfigHash(ctx, a)).ToNot(Equal(configHash))
+	})
+
+	It("should set the config hash on the pod template of the Deployment, and update it without deleting the pods of the Rollouts controller", func() {
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+		Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		configHash, err := r.getRolloutsConfigHash(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(RolloutsConfigHashAnnotation, configHash))
+		Expect(deployment.Annotations).ToNot(HaveKey(RolloutsConfigHashAnnotation))
+
+		addTestPodToFakeClient(r, a.Namespace, deployment)
+
+		By("reconciling again without changes, which should not update the Deployment")
+		resourceVersion := deployment.ResourceVersion
+		Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.ResourceVersion).To(Equal(resourceVersion))
+
+		By("changing the plugins, which should update the config hash of the pod template")
+		a.Spec.Plugins.Metric = []v1alpha1.Plugin{{Name: "custom-metric-plugin", Location: "https://custom-metric-plugin-location"}}
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+		Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Annotations[RolloutsConfigHashAnnotation]).ToNot(Equal(configHash))
+
+		By("verifying that the pod of the Rollouts controller was not deleted, as the Deployment rolls out new pods instead")
+		podList := &corev1.PodList{}
+		Expect(r.Client.List(ctx, podList, client.InNamespace(a.Namespace), client.MatchingLabels(deployment.Spec.Selector.MatchLabels))).To(Succeed())
+		Expect(podList.Items).To(HaveLen(1))
+	})
+
+	It("should enqueue the RolloutManagers that mount a ConfigMap or Secret", func() {
+		requests := r.enqueueRolloutManagersReferencingConfigMap(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "my-ca-bundle", Namespace: a.Namespace}})
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].NamespacedName).To(Equal(client.ObjectKeyFromObject(&a)))
+
+		requests = r.enqueueRolloutManagersReferencingSecret(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "my-credentials", Namespace: a.Namespace}})
+		Expect(requests).To(HaveLen(1))
+
+		Expect(r.enqueueRolloutManagersReferencingConfigMap(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other-configmap", Namespace: a.Namespace}})).To(BeEmpty())
+	})
+})
@@ -5,15 +5,11 @@ import (
 	"sort"
 
 	"fmt"
-	"reflect"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	"gopkg.in/yaml.v2"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
-	"k8s.io/apimachinery/pkg/api/errors"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,13 +24,12 @@ const MetricPluginConfigMapKey_PreviousInvalidKey = "metricPlugins"
 // From https://argo-rollouts.readthedocs.io/en/stable/analysis/plugins/
 const MetricPluginConfigMapKey = "metricProviderPlugins"
 
+// From https://argo-rollouts.readthedocs.io/en/stable/features/canary/plugins/
+const StepPluginConfigMapKey = "stepPlugins"
+
 // Reconcile the Rollouts Default Config Map.
 func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
 
-	if r.OpenShiftRoutePluginLocation == "" { // sanity test the plugin value
-		return fmt.Errorf("OpenShift Route Plugin location is not set")
-	}
-
 	desiredConfigMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      DefaultRolloutsConfigMapName,
@@ -50,68 +45,35 @@ func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr ro
 		setCustomLabels(&desiredConfigMap.ObjectMeta, r.ResourceLabels)
 	}
 
-	trafficRouterPluginsMap := map[string]pluginItem{
-		OpenShiftRolloutPluginName: {
-			Name:     OpenShiftRolloutPluginName,
-			Location: r.OpenShiftRoutePluginLocation,
-		},
-	}
-
-	// Append traffic management plugins specified in RolloutManager CR
-	for _, plugin := range cr.Spec.Plugins.TrafficManagement {
-		// Prevent adding or modifying the OpenShiftRoutePluginName through the CR
-		if plugin.Name == OpenShiftRolloutPluginName {
-			return fmt.Errorf("the plugin %s cannot be modified or added through the RolloutManager CR", OpenShiftRolloutPluginName)
-		}
-		// Check for duplicate traffic plugins
-		if _, exists := trafficRouterPluginsMap[plugin.Name]; !exists {
-			trafficRouterPluginsMap[plugin.Name] = pluginItem{
-				Name:     plugin.Name,
-				Location: plugin.Location,
-				Sha256:   plugin.SHA256,
-			}
-		}
-	}
-
-	// Sort trafficRouterPluginsMap keys for deterministic ordering
-	trafficRouterPluginKeys := make([]string, 0, len(trafficRouterPluginsMap))
-	for key := range trafficRouterPluginsMap {
-		trafficRouterPluginKeys = append(trafficRouterPluginKeys, key)
-	}
-	sort.Strings(trafficRouterPluginKeys)
+	trafficRouterPluginsMap := map[string]pluginItem{}
 
-	// Convert trafficRouterPluginsMap to sorted slice
-	trafficRouterPlugins := make([]pluginItem, 0, len(trafficRouterPluginsMap))
-	for _, key := range trafficRouterPluginKeys {
-		trafficRouterPlugins = append(trafficRouterPlugins, trafficRouterPluginsMap[key])
+	// The OpenShift Route plugin is included by default, unless disabled via .spec.trafficRouting.openShiftRoute
+	openShiftRoutePlugin, err := r.getOpenShiftRoutePlugin(ctx, cr)
+	if err != nil {
+		return err
 	}
-
-	// Append metric plugins specified in RolloutManager CR
-	metricPluginsMap := map[string]pluginItem{}
-	for _, plugin := range cr.Spec.Plugins.Metric {
-		// Check for duplicate metric plugins
-		if _, exists := metricPluginsMap[plugin.Name]; !exists {
-			metricPluginsMap[plugin.Name] = pluginItem{
-				Name:     plugin.Name,
-				Location: plugin.Location,
-				Sha256:   plugin.SHA256,
-			}
+	if openShiftRoutePlugin != nil {
+		trafficRouterPluginsMap[OpenShiftRolloutPluginName] = pluginItem{
+			Name:     OpenShiftRolloutPluginName,
+			Location: openShiftRoutePlugin.Location,
+			Sha256:   openShiftRoutePlugin.SHA256,
 		}
 	}
 
-	// Sort metricPluginsMap keys for deterministic ordering
-	metricPluginKeys := make([]string, 0, len(metricPluginsMap))
-	for key := range metricPluginsMap {
-		metricPluginKeys = append(metricPluginKeys, key)
+	if err := validatePlugins(cr); err != nil {
+		return err
 	}
-	sort.Strings(metricPluginKeys)
 
-	// Convert metricPluginsMap to sorted slice
-	metricPlugins := make([]pluginItem, 0, len(metricPluginsMap))
-	for _, key := range metricPluginKeys {
-		metricPlugins = append(metricPlugins, metricPluginsMap[key])
+	// Refuse to update the ConfigMap (and thus to restart the Rollouts controller) with a plugin that is known to be broken
+	if _, err := r.verifyPlugins(ctx, cr); err != nil {
+		return err
 	}
 
+	// Append the plugins specified in RolloutManager CR, sorted for deterministic ordering
+	trafficRouterPlugins := getSortedPluginItems(trafficRouterPluginsMap, cr.Spec.Plugins.TrafficManagement)
+	metricPlugins := getSortedPluginItems(map[string]pluginItem{}, cr.Spec.Plugins.Metric)
+	stepPlugins := getSortedPluginItems(map[string]pluginItem{}, cr.Spec.Plugins.Step)
+
 	desiredTrafficRouterPluginString, err := yaml.Marshal(trafficRouterPlugins)
 	if err != nil {
 		return fmt.Errorf("error marshalling trafficRouterPlugin to string %s", err)
@@ -122,96 +84,92 @@ func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr ro
 		return fmt.Errorf("error marshalling metricPlugins to string %s", err)
 	}
 
+	desiredStepPluginString, err := yaml.Marshal(stepPlugins)
+	if err != nil {
+		return fmt.Errorf("error marshalling stepPlugins to string %s", err)
+	}
+
 	desiredConfigMap.Data = map[string]string{
 		TrafficRouterPluginConfigMapKey: string(desiredTrafficRouterPluginString),
 		MetricPluginConfigMapKey:        string(desiredMetricPluginString),
+		StepPluginConfigMapKey:          string(desiredStepPluginString),
+	}
+
+	// The Rollouts controller is restarted on a change of the ConfigMap, via the config hash of its Deployment: see getRolloutsConfigHash
+	if err := r.applyObject(ctx, desiredConfigMap); err != nil {
+		return err
 	}
 
-	actualConfigMap := &corev1.ConfigMap{}
+	// Remove the previous invalid metric plugin key if it exists, as it was replaced by MetricPluginConfigMapKey.
+	// A key set by a previous version of the operator is owned by the operator once its managed fields are upgraded, so it is removed by applying the ConfigMap.
+	// A key set by another field manager is removed without taking over the other fields of the ConfigMap.
+	if _, hasOldKey := desiredConfigMap.Data[MetricPluginConfigMapKey_PreviousInvalidKey]; hasOldKey {
+		log.Info(fmt.Sprintf("Removing key %s from ConfigMap %s", MetricPluginConfigMapKey_PreviousInvalidKey, desiredConfigMap.Name))
 
-	if err := fetchObject(ctx, r.Client, cr.Namespace, desiredConfigMap.Name, actualConfigMap); err != nil {
-		if errors.IsNotFound(err) {
-			// ConfigMap is not present, create default config map
-			log.Info("configMap not found, creating default configmap with openshift route plugin information")
-			return r.Client.Create(ctx, desiredConfigMap)
+		patch := fmt.Sprintf(`[{"op": "remove", "path": "/data/%s"}]`, MetricPluginConfigMapKey_PreviousInvalidKey)
+		if err := r.Client.Patch(ctx, desiredConfigMap, client.RawPatch(types.JSONPatchType, []byte(patch)), client.FieldOwner(FieldManager)); err != nil {
+			return fmt.Errorf("failed to remove key %s from ConfigMap %s: %w", MetricPluginConfigMapKey_PreviousInvalidKey, desiredConfigMap.Name, err)
 		}
-		return fmt.Errorf("failed to get the ConfigMap associated with %s: %w", desiredConfigMap.Name, err)
 	}
 
-	// Unmarshal the existing plugin data from the actual ConfigMap
-	var actualTrafficRouterPlugins, actualMetricPlugins []pluginItem
-	if err = yaml.Unmarshal([]byte(actualConfigMap.Data[TrafficRouterPluginConfigMapKey]), &actualTrafficRouterPlugins); err != nil {
-		return fmt.Errorf("failed to unmarshal traffic router plugins: %s", err)
-	}
-	if err = yaml.Unmarshal([]byte(actualConfigMap.Data[MetricPluginConfigMapKey]), &actualMetricPlugins); err != nil {
-		return fmt.Errorf("failed to unmarshal metric plugins: %s", err)
-	}
+	return nil
+}
 
-	// Check if an update is needed by comparing desired and actual plugin configurations
-	updateNeeded := !reflect.DeepEqual(actualTrafficRouterPlugins, trafficRouterPlugins) || !reflect.DeepEqual(actualMetricPlugins, metricPlugins)
+// getSortedPluginItems appends the given plugins to pluginsMap, and returns the contents of pluginsMap as a slice sorted by plugin name.
+// If a plugin with the same name is already present in pluginsMap, the duplicate is ignored.
+func getSortedPluginItems(pluginsMap map[string]pluginItem, plugins []rolloutsmanagerv1alpha1.Plugin) []pluginItem {
 
-	// Remove the previous invalid metric plugin key if it exists, as it was replaced by MetricPluginConfigMapKey
-	if _, hasOldKey := actualConfigMap.Data[MetricPluginConfigMapKey_PreviousInvalidKey]; hasOldKey {
-		delete(actualConfigMap.Data, MetricPluginConfigMapKey_PreviousInvalidKey)
-		updateNeeded = true
+	for _, plugin := range plugins {
+		// Check for duplicate plugins
+		if _, exists := pluginsMap[plugin.Name]; !exists {
+			item := pluginItem{
+				Name:     plugin.Name,
+				Location: getPluginLocation(plugin),
+				Sha256:   plugin.SHA256,
+			}
+			// Leave Args nil when empty, so that it matches the value unmarshalled from the ConfigMap
+			if len(plugin.Args) > 0 {
+				item.Args = append([]string{}, plugin.Args...)
+			}
+			pluginsMap[plugin.Name] = item
+		}
 	}
 
-	if updateNeeded {
-		// Update the ConfigMap's plugin data with the new values
-		actualConfigMap.Data[TrafficRouterPluginConfigMapKey] = string(desiredTrafficRouterPluginString)
-		actualConfigMap.Data[MetricPluginConfigMapKey] = string(desiredMetricPluginString)
-
-		log.Info("Updating Rollouts ConfigMap due to detected difference")
+	// Sort pluginsMap keys for deterministic ordering
+	pluginKeys := make([]string, 0, len(pluginsMap))
+	for key := range pluginsMap {
+		pluginKeys = append(pluginKeys, key)
+	}
+	sort.Strings(pluginKeys)
 
-		// Update the ConfigMap in the cluster
-		if err := r.Client.Update(ctx, actualConfigMap); err != nil {
-			return fmt.Errorf("failed to update ConfigMap: %v", err)
-		}
-		// Restarting rollouts pod only if configMap is updated
-		if err := r.restartRolloutsPod(ctx, cr.Namespace); err != nil {
-			return err
-		}
-	} else {
-		log.Info("No changes detected in ConfigMap, skipping update and pod restart")
+	// Convert pluginsMap to sorted slice
+	res := make([]pluginItem, 0, len(pluginsMap))
+	for _, key := range pluginKeys {
+		res = append(res, pluginsMap[key])
 	}
 
-	return nil
+	return res
 }
 
-// restartRolloutsPod deletes the Rollouts Pod to trigger a restart
-func (r *RolloutManagerReconciler) restartRolloutsPod(ctx context.Context, namespace string) error {
-	deployment := &appsv1.Deployment{}
-	if err := r.Client.Get(ctx, types.NamespacedName{Name: DefaultArgoRolloutsResourceName, Namespace: namespace}, deployment); err != nil {
-		if errors.IsNotFound(err) {
-			// If Deployment isn't found, return nil as there is no child pod to restart
-			return nil
+// validatePlugins returns an error if the plugins specified in the RolloutManager CR cannot be applied to the Rollouts ConfigMap.
+func validatePlugins(cr rolloutsmanagerv1alpha1.RolloutManager) error {
+	for _, plugin := range cr.Spec.Plugins.TrafficManagement {
+		// Prevent adding or modifying the OpenShiftRoutePluginName through the CR
+		if plugin.Name == OpenShiftRolloutPluginName {
+			return fmt.Errorf("the plugin %s cannot be modified or added through the RolloutManager CR", OpenShiftRolloutPluginName)
 		}
-		return fmt.Errorf("failed to get deployment: %w", err)
-	}
-
-	podList := &corev1.PodList{}
-	listOpts := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels(deployment.Spec.Selector.MatchLabels),
-	}
-	if err := r.Client.List(ctx, podList, listOpts...); err != nil {
-		return fmt.Errorf("failed to list Rollouts Pods: %w", err)
 	}
 
-	for i := range podList.Items {
-		pod := podList.Items[i]
-		if pod.ObjectMeta.DeletionTimestamp == nil {
-			log.Info("Deleting Rollouts Pod", "podName", pod.Name)
-			if err := r.Client.Delete(ctx, &pod); err != nil {
-				if errors.IsNotFound(err) {
-					log.Info(fmt.Sprintf("Pod %s already deleted", pod.Name))
-					continue
-				}
-				return fmt.Errorf("failed to delete Rollouts Pod %s: %w", pod.Name, err)
+	for _, pluginList := range [][]rolloutsmanagerv1alpha1.Plugin{cr.Spec.Plugins.TrafficManagement, cr.Spec.Plugins.Metric, cr.Spec.Plugins.Step} {
+		for _, plugin := range pluginList {
+			// A plugin without a location, an image or architectures is resolved from the plugin catalog, using its version
+			if plugin.Location == "" && plugin.Version == "" && plugin.Image == nil && len(plugin.Architectures) == 0 {
+				return fmt.Errorf("plugin %s must set either a location, a version, an image or architectures", plugin.Name)
 			}
-			log.Info("Rollouts Pod deleted successfully", "podName", pod.Name)
 		}
 	}
-
-	return nil
+	if err := validatePluginArchitectures(cr.Spec.Plugins); err != nil {
+		return err
+	}
+	return validatePluginImages(cr.Spec.Plugins)
 }
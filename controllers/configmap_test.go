@@ -75,6 +75,21 @@ var _ = Describe("ConfigMap Test", func() {
 
 	})
 
+	It("verifies that the OpenShift Route plugin is resolved from the plugin catalog when no location is configured", func() {
+		r.OpenShiftRoutePluginLocation = ""
+
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		fetchedConfigMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+
+		catalog, err := r.getPluginCatalog(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		version, exists := catalog.lookup(OpenShiftRolloutPluginName, DefaultOpenShiftRoutePluginVersion)
+		Expect(exists).To(BeTrue())
+		Expect(fetchedConfigMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring(version.Location))
+	})
+
 	It("verifies that the custom labels are added to the ConfigMap", func() {
 		r = makeTestReconcilerWithCustomLabels(map[string]string{
 			"custom1": "value",
@@ -132,6 +147,9 @@ var _ = Describe("ConfigMap Test", func() {
 		By("Call reconcileConfigMap")
 		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
 
+		initialConfigHash, err := r.getRolloutsConfigHash(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+
 		By("Fetched ConfigMap")
 		fetchedConfigMap := &corev1.ConfigMap{}
 		Expect(fetchObject(ctx, r.Client, a.Namespace, expectedConfigMap.Name, fetchedConfigMap)).To(Succeed())
@@ -223,20 +241,22 @@ var _ = Describe("ConfigMap Test", func() {
 		Expect(fetchedConfigMap.Data[TrafficRouterPluginConfigMapKey]).NotTo(ContainSubstring("custom-traffic-plugin"))
 		Expect(fetchedConfigMap.Data[MetricPluginConfigMapKey]).NotTo(ContainSubstring("custom-metric-plugin"))
 
-		By("Verify that the pod has been deleted after the above update.")
+		By("Verify that the config hash changed after the above update, and that the pod was not deleted: the Deployment rolls out new pods instead")
+		Expect(r.getRolloutsConfigHash(ctx, a)).ToNot(Equal(initialConfigHash))
+
 		rolloutsPodList := &corev1.PodList{}
-		err := r.Client.List(ctx, rolloutsPodList, client.InNamespace(a.Namespace), client.MatchingLabels(existingDeployment.Spec.Selector.MatchLabels))
+		err = r.Client.List(ctx, rolloutsPodList, client.InNamespace(a.Namespace), client.MatchingLabels(existingDeployment.Spec.Selector.MatchLabels))
 		Expect(err).NotTo(HaveOccurred())
-		Expect(len(rolloutsPodList.Items)).To(BeNumerically("==", 0))
+		Expect(rolloutsPodList.Items).To(HaveLen(1))
 	})
 
 	It("creates a ConfigMap that uses the old key invalid configmap key 'metricPlugins' for configuring metric plugin, and then calls reconcileConfigMap. The old key should be removed and replaced with the new key", func() {
-		By("Adding a pod that matches the deployment's selector, this lets us verify the deployment has been restarted to pick up the change to the ConfigMap")
-		addTestPodToFakeClient(r, a.Namespace, existingDeployment)
-
 		By("Calling reconcileConfigMap to create the initial ConfigMap")
 		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
 
+		initialConfigHash, err := r.getRolloutsConfigHash(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+
 		By("Fetching the created ConfigMap and add metric plugin data under the old invalid key")
 		fetchedConfigMap := &corev1.ConfigMap{}
 		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
@@ -268,11 +288,89 @@ var _ = Describe("ConfigMap Test", func() {
 		Expect(updatedConfigMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring(OpenShiftRolloutPluginName))
 		Expect(updatedConfigMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring(r.OpenShiftRoutePluginLocation))
 
-		By("Verifying that the pod has been deleted after the ConfigMap update")
-		rolloutsPodList := &corev1.PodList{}
-		err := r.Client.List(ctx, rolloutsPodList, client.InNamespace(a.Namespace), client.MatchingLabels(existingDeployment.Spec.Selector.MatchLabels))
-		Expect(err).NotTo(HaveOccurred())
-		Expect(len(rolloutsPodList.Items)).To(BeNumerically("==", 0))
+		By("Verifying that the config hash changed after the ConfigMap update, so that the Rollouts controller is restarted to pick up the change")
+		Expect(r.getRolloutsConfigHash(ctx, a)).ToNot(Equal(initialConfigHash))
+	})
+
+	It("removes the old invalid configmap key 'metricPlugins' set by a previous version of the operator, by applying the ConfigMap", func() {
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		By("adding metric plugin data under the old invalid key, as a previous version of the operator")
+		fetchedConfigMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+		fetchedConfigMap.Data[MetricPluginConfigMapKey_PreviousInvalidKey] = "- name: custom-metric-plugin\n  location: " + metricPluginLocation + "\n  sha256: \"\"\n"
+		Expect(r.Client.Update(ctx, fetchedConfigMap, client.FieldOwner(legacyFieldManagers[0]))).To(Succeed())
+
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+		Expect(fetchedConfigMap.Data).ToNot(HaveKey(MetricPluginConfigMapKey_PreviousInvalidKey))
+		for _, entry := range fetchedConfigMap.ManagedFields {
+			Expect(entry.Manager).To(Equal(FieldManager))
+			Expect(entry.Operation).To(Equal(metav1.ManagedFieldsOperationApply))
+		}
+	})
+
+	It("verifies step plugin creation/modification, including plugin args, and that the config hash only changes when the step plugins change", func() {
+
+		By("Adding step plugins through the CR, including a duplicate")
+		a.Spec.Plugins.Step = []v1alpha1.Plugin{
+			{Name: "custom-step-plugin-b", Location: "https://custom-step-plugin-b-location", Args: []string{"--log-level", "debug"}},
+			{Name: "custom-step-plugin-a", Location: "https://custom-step-plugin-a-location", SHA256: "sha256-step-test"},
+			{Name: "custom-step-plugin-b", Location: "https://duplicate-location"},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		By("Calling reconcileConfigMap")
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		By("Verifying that the step plugins are sorted by name, the duplicate is ignored, and args are included")
+		fetchedConfigMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+		Expect(fetchedConfigMap.Data[StepPluginConfigMapKey]).To(Equal(`- name: custom-step-plugin-a
+  location: https://custom-step-plugin-a-location
+  sha256: sha256-step-test
+- name: custom-step-plugin-b
+  location: https://custom-step-plugin-b-location
+  sha256: ""
+  args:
+  - --log-level
+  - debug
+`))
+
+		By("Verifying that the traffic router and metric plugin keys are unaffected")
+		Expect(fetchedConfigMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring(OpenShiftRolloutPluginName))
+		Expect(fetchedConfigMap.Data[TrafficRouterPluginConfigMapKey]).NotTo(ContainSubstring("custom-step-plugin"))
+		Expect(fetchedConfigMap.Data[MetricPluginConfigMapKey]).To(Equal("[]\n"))
+
+		configHash, err := r.getRolloutsConfigHash(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Calling reconcileConfigMap again with no changes")
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		By("Verifying that the config hash has not changed, as the ConfigMap did not change")
+		Expect(r.getRolloutsConfigHash(ctx, a)).To(Equal(configHash))
+
+		By("Modifying the args of a step plugin")
+		a.Spec.Plugins.Step[0].Args = []string{"--log-level", "info"}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+		Expect(fetchedConfigMap.Data[StepPluginConfigMapKey]).To(ContainSubstring("- info"))
+		Expect(fetchedConfigMap.Data[StepPluginConfigMapKey]).NotTo(ContainSubstring("- debug"))
+
+		By("Verifying that the config hash changed after the step plugin update")
+		Expect(r.getRolloutsConfigHash(ctx, a)).ToNot(Equal(configHash))
+
+		By("Removing step plugins from RolloutManager spec should remove them from the ConfigMap")
+		a.Spec.Plugins.Step = nil
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+		Expect(fetchedConfigMap.Data[StepPluginConfigMapKey]).NotTo(ContainSubstring("custom-step-plugin"))
 	})
 })
 
@@ -0,0 +1,185 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxControllerHealthMessageLength is the maximum length of the messages of the pods and containers reported in the ControllerHealthy condition.
+	maxControllerHealthMessageLength = 256
+)
+
+// imagePullFailureReasons are the reasons of a waiting container that cannot pull its image.
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":        true,
+	"ImagePullBackOff":    true,
+	"InvalidImageName":    true,
+	"ErrImageNeverPull":   true,
+	"RegistryUnavailable": true,
+}
+
+// getControllerHealthyCondition returns the ControllerHealthy condition of the RolloutManager, based on the pods and ReplicaSets of the Rollouts controller Deployment.
+// When a failure of the Rollouts controller pods is found, the condition reports it as its reason, even if the Deployment is otherwise available, for example when a new revision of the pods fails to start.
+func (r *RolloutManagerReconciler) getControllerHealthyCondition(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, deploy *appsv1.Deployment) (metav1.Condition, error) {
+
+	if deploy == nil {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonControllerNotReady,
+			fmt.Sprintf("the Deployment %s does not exist", DefaultArgoRolloutsResourceName)), nil
+	}
+
+	// The pods that are still terminating after the Deployment was scaled to zero are not diagnosed
+	if isDeploymentSuspended(cr, deploy) {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonSuspended, "the Rollouts controller is suspended by .spec.suspend"), nil
+	}
+
+	listOpts := []client.ListOption{
+		client.InNamespace(cr.Namespace),
+		client.MatchingLabels{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName},
+	}
+
+	// Pods which cannot be created, for example because of a ResourceQuota, are only reported on the ReplicaSet
+	replicaSetList := &appsv1.ReplicaSetList{}
+	if err := r.Client.List(ctx, replicaSetList, listOpts...); err != nil {
+		return metav1.Condition{}, fmt.Errorf("failed to list the ReplicaSets of the Rollouts controller: %w", err)
+	}
+	for _, replicaSet := range replicaSetList.Items {
+		if !metav1.IsControlledBy(&replicaSet, deploy) || replicaSet.Spec.Replicas == nil || *replicaSet.Spec.Replicas == 0 {
+			continue
+		}
+		if condition := diagnoseRolloutsReplicaSet(replicaSet); condition != nil {
+			return *condition, nil
+		}
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, listOpts...); err != nil {
+		return metav1.Condition{}, fmt.Errorf("failed to list the pods of the Rollouts controller: %w", err)
+	}
+	sort.Slice(podList.Items, func(i, j int) bool {
+		return podList.Items[i].Name < podList.Items[j].Name
+	})
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if condition := diagnoseRolloutsPod(pod); condition != nil {
+			return *condition, nil
+		}
+	}
+
+	replicas := int32(0)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+
+	if deploy.Spec.Replicas != nil && deploy.Status.ReadyReplicas == replicas {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonControllerAvailable, ""), nil
+	}
+
+	return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonControllerNotReady,
+		fmt.Sprintf("%d of %d pods of the Rollouts controller are ready", deploy.Status.ReadyReplicas, replicas)), nil
+}
+
+// diagnoseRolloutsReplicaSet returns a ControllerHealthy condition if the ReplicaSet failed to create its pods, or nil otherwise.
+func diagnoseRolloutsReplicaSet(replicaSet appsv1.ReplicaSet) *metav1.Condition {
+
+	for _, condition := range replicaSet.Status.Conditions {
+		if condition.Type != appsv1.ReplicaSetReplicaFailure || condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		reason := rolloutsmanagerv1alpha1.RolloutManagerReasonPodCreationFailed
+		if strings.Contains(condition.Message, "exceeded quota") {
+			reason = rolloutsmanagerv1alpha1.RolloutManagerReasonQuotaExceeded
+		}
+
+		res := createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionFalse, reason,
+			fmt.Sprintf("ReplicaSet %s cannot create pods: %s", replicaSet.Name, truncateControllerHealthMessage(condition.Message)))
+		return &res
+	}
+
+	return nil
+}
+
+// diagnoseRolloutsPod returns a ControllerHealthy condition if the pod cannot be scheduled, or one of its containers is failing, or nil otherwise.
+func diagnoseRolloutsPod(pod corev1.Pod) *metav1.Condition {
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable {
+			res := createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonControllerUnschedulable,
+				fmt.Sprintf("pod %s cannot be scheduled: %s", pod.Name, truncateControllerHealthMessage(condition.Message)))
+			return &res
+		}
+	}
+
+	containerStatuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	for i, status := range containerStatuses {
+
+		reason, message := "", ""
+
+		switch waiting, lastTerminated := status.State.Waiting, status.LastTerminationState.Terminated; {
+
+		case waiting != nil && imagePullFailureReasons[waiting.Reason]:
+			reason = rolloutsmanagerv1alpha1.RolloutManagerReasonImagePullFailed
+			message = fmt.Sprintf("cannot pull image %s: %s", status.Image, waiting.Message)
+
+		case (status.State.Terminated != nil && status.State.Terminated.Reason == "OOMKilled") ||
+			(waiting != nil && waiting.Reason == "CrashLoopBackOff" && lastTerminated != nil && lastTerminated.Reason == "OOMKilled"):
+			reason = rolloutsmanagerv1alpha1.RolloutManagerReasonControllerOOMKilled
+			message = "was killed for exceeding its memory limit"
+
+		case waiting != nil && waiting.Reason == "CrashLoopBackOff":
+			reason = rolloutsmanagerv1alpha1.RolloutManagerReasonControllerCrashLooping
+			message = "is crash looping"
+			if lastTerminated != nil {
+				message = fmt.Sprintf("is crash looping, last terminated with exit code %d", lastTerminated.ExitCode)
+				if lastTerminated.Message != "" {
+					message += ": " + strings.TrimSpace(lastTerminated.Message)
+				}
+			}
+
+		case waiting != nil && waiting.Message != "" && waiting.Reason != "ContainerCreating" && waiting.Reason != "PodInitializing":
+			// Any other reason which prevents the container from starting, for example a missing ConfigMap or Secret
+			reason = rolloutsmanagerv1alpha1.RolloutManagerReasonControllerNotReady
+			message = fmt.Sprintf("is waiting (%s): %s", waiting.Reason, waiting.Message)
+
+		default:
+			continue
+		}
+
+		// The init containers of plugin images fail with a generic error if the image does not provide 'cp', or does not contain the executable
+		if i < len(pod.Status.InitContainerStatuses) && strings.HasPrefix(status.Name, pluginImageInitContainerPrefix) &&
+			reason != rolloutsmanagerv1alpha1.RolloutManagerReasonImagePullFailed {
+			reason = rolloutsmanagerv1alpha1.RolloutManagerReasonPluginInstallFailed
+			message = fmt.Sprintf("cannot copy the plugin executable (the image must provide 'cp'): %s", message)
+		}
+
+		res := createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionFalse, reason,
+			truncateControllerHealthMessage(fmt.Sprintf("container %s of pod %s %s", status.Name, pod.Name, message)))
+		return &res
+	}
+
+	return nil
+}
+
+// truncateControllerHealthMessage shortens a message, such as the termination message of a container, to the first line and at most maxControllerHealthMessageLength characters.
+func truncateControllerHealthMessage(message string) string {
+
+	message, _, _ = strings.Cut(strings.TrimSpace(message), "\n")
+
+	if runes := []rune(message); len(runes) > maxControllerHealthMessageLength {
+		message = string(runes[:maxControllerHealthMessageLength-3]) + "..."
+	}
+
+	return message
+}
@@ -0,0 +1,197 @@
+package rollouts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Controller health tests", func() {
+
+	int32Ptr := func(val int32) *int32 { return &val }
+
+	makePod := func(containerStatus corev1.ContainerStatus) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "argo-rollouts-abc"},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{containerStatus}},
+		}
+	}
+
+	DescribeTable("should diagnose the failures of a Rollouts controller pod", func(pod corev1.Pod, expectedReason string, expectedMessage string) {
+		condition := diagnoseRolloutsPod(pod)
+		if expectedReason == "" {
+			Expect(condition).To(BeNil())
+			return
+		}
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Type).To(Equal(v1alpha1.RolloutManagerConditionTypeControllerHealthy))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(expectedReason))
+		Expect(condition.Message).To(Equal(expectedMessage))
+	},
+		Entry("with a ready container", makePod(corev1.ContainerStatus{
+			Name:  "argo-rollouts",
+			Ready: true,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		}), "", ""),
+		Entry("with a container that is being created", makePod(corev1.ContainerStatus{
+			Name:  "argo-rollouts",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating", Message: "pulling image"}},
+		}), "", ""),
+		Entry("with an image that cannot be pulled", makePod(corev1.ContainerStatus{
+			Name:  "argo-rollouts",
+			Image: "quay.io/test/rollouts:missing",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+		}), v1alpha1.RolloutManagerReasonImagePullFailed, "container argo-rollouts of pod argo-rollouts-abc cannot pull image quay.io/test/rollouts:missing: Back-off pulling image"),
+		Entry("with a crash looping container", makePod(corev1.ContainerStatus{
+			Name:                 "argo-rollouts",
+			State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2, Reason: "Error", Message: "panic: invalid argument\ngoroutine 1 [running]:"}},
+		}), v1alpha1.RolloutManagerReasonControllerCrashLooping, "container argo-rollouts of pod argo-rollouts-abc is crash looping, last terminated with exit code 2: panic: invalid argument"),
+		Entry("with a container killed for exceeding its memory limit", makePod(corev1.ContainerStatus{
+			Name:                 "argo-rollouts",
+			State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+		}), v1alpha1.RolloutManagerReasonControllerOOMKilled, "container argo-rollouts of pod argo-rollouts-abc was killed for exceeding its memory limit"),
+		Entry("with a container referencing a missing Secret", makePod(corev1.ContainerStatus{
+			Name:  "argo-rollouts",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CreateContainerConfigError", Message: `secret "missing" not found`}},
+		}), v1alpha1.RolloutManagerReasonControllerNotReady, `container argo-rollouts of pod argo-rollouts-abc is waiting (CreateContainerConfigError): secret "missing" not found`),
+		Entry("with a plugin image without the cp command", corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "argo-rollouts-abc"},
+			Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:                 "install-plugin-test-plugin",
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 128, Reason: "StartError", Message: `exec: "cp": executable file not found in $PATH`}},
+			}}},
+		}, v1alpha1.RolloutManagerReasonPluginInstallFailed, "container install-plugin-test-plugin of pod argo-rollouts-abc cannot copy the plugin executable (the image must provide 'cp'): is crash looping, last terminated with exit code 128: exec: \"cp\": executable file not found in $PATH"),
+		Entry("with a pod that cannot be scheduled", corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "argo-rollouts-abc"},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available: 3 Insufficient memory.",
+			}}},
+		}, v1alpha1.RolloutManagerReasonControllerUnschedulable, "pod argo-rollouts-abc cannot be scheduled: 0/3 nodes are available: 3 Insufficient memory."),
+	)
+
+	It("should truncate long messages", func() {
+		message := truncateControllerHealthMessage(strings.Repeat("a", 300))
+		Expect(message).To(HaveLen(maxControllerHealthMessageLength))
+		Expect(message).To(HaveSuffix("..."))
+	})
+
+	Context("reading the pods and ReplicaSets of the Rollouts controller", func() {
+
+		var (
+			ctx    context.Context
+			a      v1alpha1.RolloutManager
+			r      *RolloutManagerReconciler
+			deploy *appsv1.Deployment
+		)
+
+		makeReplicaSet := func(name string, replicas int32, conditions ...appsv1.ReplicaSetCondition) *appsv1.ReplicaSet {
+			return &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            name,
+					Namespace:       a.Namespace,
+					Labels:          map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName},
+					OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: deploy.Name, UID: deploy.UID, Controller: boolPtr(true)}},
+				},
+				Spec:   appsv1.ReplicaSetSpec{Replicas: int32Ptr(replicas)},
+				Status: appsv1.ReplicaSetStatus{Conditions: conditions},
+			}
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			a = *makeTestRolloutManager()
+			r = makeTestReconciler(&a)
+			Expect(createNamespace(r, a.Namespace)).To(Succeed())
+
+			deploy = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace, UID: types.UID("deployment-uid")},
+				Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(1)},
+			}
+		})
+
+		It("should report a missing Deployment", func() {
+			condition, err := r.getControllerHealthyCondition(ctx, a, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonControllerNotReady))
+			Expect(condition.Message).To(Equal("the Deployment argo-rollouts does not exist"))
+		})
+
+		It("should report the number of ready pods, and the Rollouts controller as healthy once all pods are ready", func() {
+			condition, err := r.getControllerHealthyCondition(ctx, a, deploy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonControllerNotReady))
+			Expect(condition.Message).To(Equal("0 of 1 pods of the Rollouts controller are ready"))
+
+			deploy.Status.ReadyReplicas = 1
+			condition, err = r.getControllerHealthyCondition(ctx, a, deploy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonControllerAvailable))
+		})
+
+		It("should report a ReplicaSet that exceeds a quota, but not a ReplicaSet of a previous revision", func() {
+			quotaFailure := appsv1.ReplicaSetCondition{
+				Type:    appsv1.ReplicaSetReplicaFailure,
+				Status:  corev1.ConditionTrue,
+				Reason:  "FailedCreate",
+				Message: `pods "argo-rollouts-abc" is forbidden: exceeded quota: compute, requested: memory=512Mi, used: memory=1Gi, limited: memory=1Gi`,
+			}
+
+			Expect(r.Client.Create(ctx, makeReplicaSet("argo-rollouts-old", 0, quotaFailure))).To(Succeed())
+
+			condition, err := r.getControllerHealthyCondition(ctx, a, deploy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonControllerNotReady))
+
+			Expect(r.Client.Create(ctx, makeReplicaSet("argo-rollouts-new", 1, quotaFailure))).To(Succeed())
+
+			condition, err = r.getControllerHealthyCondition(ctx, a, deploy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonQuotaExceeded))
+			Expect(condition.Message).To(Equal("ReplicaSet argo-rollouts-new cannot create pods: " + quotaFailure.Message))
+		})
+
+		It("should report a failing pod, even if the Deployment is available", func() {
+			deploy.Status.ReadyReplicas = 1
+
+			pod := makePod(corev1.ContainerStatus{
+				Name:  "argo-rollouts",
+				Image: "quay.io/test/rollouts:missing",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull", Message: "manifest unknown"}},
+			})
+			pod.Namespace = a.Namespace
+			pod.Labels = map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName}
+			Expect(r.Client.Create(ctx, &pod)).To(Succeed())
+
+			By("ignoring the pods of other workloads")
+			otherPod := makePod(corev1.ContainerStatus{
+				Name:  "other",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			})
+			otherPod.Name, otherPod.Namespace = "other", a.Namespace
+			Expect(r.Client.Create(ctx, &otherPod)).To(Succeed())
+
+			condition, err := r.getControllerHealthyCondition(ctx, a, deploy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonImagePullFailed))
+			Expect(condition.Message).To(Equal("container argo-rollouts of pod argo-rollouts-abc cannot pull image quay.io/test/rollouts:missing: manifest unknown"))
+		})
+	})
+})
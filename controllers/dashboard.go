@@ -0,0 +1,740 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// routeGVK is the GroupVersionKind of OpenShift Routes. Routes are handled as unstructured objects, so that the operator does not depend on the OpenShift API.
+var routeGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// reconcileRolloutsDashboard reconciles all the resources of the Argo Rollouts Dashboard. If the Dashboard is not enabled, any previously-created Dashboard resources are removed.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboard(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	if !cr.Spec.Dashboard.IsEnabled() {
+		return r.deleteRolloutsDashboardResources(ctx, cr)
+	}
+
+	var sa *corev1.ServiceAccount
+	if isComponentUnmanaged(cr, rolloutsmanagerv1alpha1.ComponentRBAC) {
+		// The ServiceAccount and RBAC of the Dashboard are part of the RBAC component: the Deployment only references the ServiceAccount by its name
+		sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsDashboardResourceName, Namespace: cr.Namespace}}
+	} else {
+		var err error
+		if sa, err = r.reconcileRolloutsDashboardServiceAccount(ctx, cr); err != nil {
+			return fmt.Errorf("unable to reconcile Dashboard ServiceAccount: %w", err)
+		}
+
+		if cr.Spec.NamespaceScoped {
+			if err := r.reconcileRolloutsDashboardRoleAndRoleBinding(ctx, cr, sa); err != nil {
+				return fmt.Errorf("unable to reconcile Dashboard Role/RoleBinding: %w", err)
+			}
+		} else {
+			if err := r.reconcileRolloutsDashboardClusterRoleAndClusterRoleBinding(ctx, cr, sa); err != nil {
+				return fmt.Errorf("unable to reconcile Dashboard ClusterRole/ClusterRoleBinding: %w", err)
+			}
+		}
+	}
+
+	if err := r.reconcileRolloutsDashboardDeployment(ctx, cr, *sa); err != nil {
+		return fmt.Errorf("unable to reconcile Dashboard Deployment: %w", err)
+	}
+
+	if err := r.reconcileRolloutsDashboardService(ctx, cr); err != nil {
+		return fmt.Errorf("unable to reconcile Dashboard Service: %w", err)
+	}
+
+	if err := r.reconcileRolloutsDashboardIngress(ctx, cr); err != nil {
+		return fmt.Errorf("unable to reconcile Dashboard Ingress: %w", err)
+	}
+
+	if err := r.reconcileRolloutsDashboardRoute(ctx, cr); err != nil {
+		return fmt.Errorf("unable to reconcile Dashboard Route: %w", err)
+	}
+
+	if err := r.reconcileRolloutsDashboardNetworkPolicy(ctx, cr); err != nil {
+		return fmt.Errorf("unable to reconcile Dashboard NetworkPolicy: %w", err)
+	}
+
+	return nil
+}
+
+// setRolloutsDashboardLabelsAndAnnotationsToObject sets the standard labels/annotations of Rollouts resources, but identifies the resource as part of the Dashboard component.
+func setRolloutsDashboardLabelsAndAnnotationsToObject(obj *metav1.ObjectMeta, cr rolloutsmanagerv1alpha1.RolloutManager) {
+	setRolloutsLabelsAndAnnotationsToObject(obj, cr)
+	obj.Labels["app.kubernetes.io/name"] = DefaultArgoRolloutsDashboardResourceName
+	obj.Labels["app.kubernetes.io/component"] = "dashboard"
+}
+
+// Reconciles Rollouts Dashboard ServiceAccount.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardServiceAccount(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (*corev1.ServiceAccount, error) {
+	expectedServiceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedServiceAccount.ObjectMeta, cr)
+
+	if err := controllerutil.SetControllerReference(&cr, expectedServiceAccount, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	if err := r.applyObject(ctx, expectedServiceAccount); err != nil {
+		return nil, err
+	}
+
+	return expectedServiceAccount, nil
+}
+
+// Reconciles the Rollouts Dashboard Role and RoleBinding, used when the RolloutManager is namespace-scoped.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardRoleAndRoleBinding(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, sa *corev1.ServiceAccount) error {
+
+	// Delete existing ClusterRole/ClusterRoleBinding
+	if err := r.deleteRolloutsDashboardClusterRoleAndClusterRoleBinding(ctx); err != nil {
+		return err
+	}
+
+	expectedRole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+		Rules: GetDashboardPolicyRules(),
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedRole.ObjectMeta, cr)
+
+	if err := controllerutil.SetControllerReference(&cr, expectedRole, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.applyObject(ctx, expectedRole); err != nil {
+		return err
+	}
+
+	expectedRoleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     expectedRole.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedRoleBinding.ObjectMeta, cr)
+
+	if err := controllerutil.SetControllerReference(&cr, expectedRoleBinding, r.Scheme); err != nil {
+		return err
+	}
+
+	return r.applyObject(ctx, expectedRoleBinding)
+}
+
+// Reconciles the Rollouts Dashboard ClusterRole and ClusterRoleBinding, used when the RolloutManager is cluster-scoped.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardClusterRoleAndClusterRoleBinding(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, sa *corev1.ServiceAccount) error {
+
+	// Delete existing Role/RoleBinding
+	if err := r.deleteRolloutsDashboardRoleAndRoleBinding(ctx, cr); err != nil {
+		return err
+	}
+
+	expectedClusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultArgoRolloutsDashboardResourceName,
+		},
+		Rules: GetDashboardPolicyRules(),
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedClusterRole.ObjectMeta, cr)
+
+	if err := r.applyObject(ctx, expectedClusterRole); err != nil {
+		return err
+	}
+
+	expectedClusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultArgoRolloutsDashboardResourceName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     expectedClusterRole.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedClusterRoleBinding.ObjectMeta, cr)
+
+	return r.applyObject(ctx, expectedClusterRoleBinding)
+}
+
+func generateDesiredRolloutsDashboardDeployment(cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) (appsv1.Deployment, error) {
+
+	desiredDeployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&desiredDeployment.ObjectMeta, cr)
+
+	selectorLabels := map[string]string{
+		DefaultRolloutsSelectorKey: DefaultArgoRolloutsDashboardResourceName,
+	}
+
+	// Add labels and annotations as well to the pod template
+	labels := map[string]string{
+		DefaultRolloutsSelectorKey: DefaultArgoRolloutsDashboardResourceName,
+	}
+	annotations := map[string]string{}
+	if cr.Spec.AdditionalMetadata != nil {
+		for k, v := range cr.Spec.AdditionalMetadata.Labels {
+			labels[k] = v
+		}
+		for k, v := range cr.Spec.AdditionalMetadata.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	var replicas int32 = 1
+
+	desiredDeployment.Spec = appsv1.DeploymentSpec{
+		Replicas: &replicas,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: selectorLabels,
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      labels,
+				Annotations: annotations,
+			},
+			Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{
+					"kubernetes.io/os": "linux",
+				},
+				ServiceAccountName: sa.Name,
+				SecurityContext: &corev1.PodSecurityContext{
+					RunAsNonRoot: boolPtr(true),
+				},
+			},
+		},
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+		},
+	}
+
+	if nodePlacement := cr.Spec.Dashboard.NodePlacement; nodePlacement != nil {
+		desiredDeployment.Spec.Template.Spec.NodeSelector = appendStringMap(
+			desiredDeployment.Spec.Template.Spec.NodeSelector, nodePlacement.NodeSelector)
+		desiredDeployment.Spec.Template.Spec.Tolerations = nodePlacement.Tolerations
+		setSchedulingConstraints(&desiredDeployment.Spec.Template.Spec, *nodePlacement)
+	}
+
+	dashboardCont, err := rolloutsDashboardContainer(cr)
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
+	desiredDeployment.Spec.Template.Spec.Containers = []corev1.Container{dashboardCont}
+
+	return desiredDeployment, nil
+}
+
+func rolloutsDashboardContainer(cr rolloutsmanagerv1alpha1.RolloutManager) (corev1.Container, error) {
+
+	image, err := getRolloutsDashboardContainerImage(cr)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+
+	imagePullPolicy, err := getImagePullPolicy(cr)
+	if err != nil {
+		return corev1.Container{}, err
+	}
+
+	containerResources := corev1.ResourceRequirements{}
+	if cr.Spec.Dashboard.Resources != nil {
+		containerResources = *cr.Spec.Dashboard.Resources
+	}
+
+	return corev1.Container{
+		Args:            []string{"dashboard"},
+		Env:             proxyEnvVars(),
+		Image:           image,
+		ImagePullPolicy: imagePullPolicy,
+		Name:            DefaultArgoRolloutsDashboardResourceName,
+		Ports: []corev1.ContainerPort{
+			{
+				ContainerPort: DefaultArgoRolloutsDashboardPort,
+				Name:          "dashboard",
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Resources: containerResources,
+		SecurityContext: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{
+					"ALL",
+				},
+			},
+			AllowPrivilegeEscalation: boolPtr(false),
+			RunAsNonRoot:             boolPtr(true),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}, nil
+}
+
+// Returns the container image for the rollouts dashboard.
+func getRolloutsDashboardContainerImage(cr rolloutsmanagerv1alpha1.RolloutManager) (string, error) {
+
+	image := DefaultArgoRolloutsDashboardImage
+	tag := DefaultArgoRolloutsVersion
+
+	if cr.Spec.Dashboard != nil {
+		if cr.Spec.Dashboard.Image != "" {
+			image = cr.Spec.Dashboard.Image
+		}
+		if cr.Spec.Dashboard.Version != "" {
+			tag = cr.Spec.Dashboard.Version
+		}
+	}
+
+	combinedImage := combineImageTag(image, tag)
+
+	// Ensure the combination of .spec.dashboard.image and .spec.dashboard.version is a valid image reference
+	if _, err := extractBaseImageName(combinedImage); err != nil {
+		return "", fmt.Errorf("invalid image '%s' generated from RolloutManager .spec.dashboard.image and .spec.dashboard.version: %w", combinedImage, err)
+	}
+
+	return combinedImage, nil
+}
+
+// Reconcile the Rollouts Dashboard Deployment.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardDeployment(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) error {
+
+	desiredDeployment, err := generateDesiredRolloutsDashboardDeployment(cr, sa)
+	if err != nil {
+		return err
+	}
+
+	actualDeployment := &appsv1.Deployment{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, desiredDeployment.Name, actualDeployment); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the Deployment %s: %w", desiredDeployment.Name, err)
+		}
+
+	} else if !reflect.DeepEqual(actualDeployment.Spec.Selector, desiredDeployment.Spec.Selector) {
+		// .spec.selector is immutable, so the Deployment needs to be recreated
+		log.Info(fmt.Sprintf("deleting and recreating Deployment %s, as the .spec.selector field of the Deployment has changed", actualDeployment.Name))
+
+		if err := r.Client.Delete(ctx, actualDeployment); err != nil {
+			return fmt.Errorf("unable to delete Dashboard Deployment after .spec.selector change: %w", err)
+		}
+	}
+
+	if err := controllerutil.SetControllerReference(&cr, &desiredDeployment, r.Scheme); err != nil {
+		return err
+	}
+
+	return r.applyObject(ctx, &desiredDeployment)
+}
+
+// Reconcile the Service which is used to access the Rollouts Dashboard.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardService(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	expectedSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "dashboard",
+					Port:       DefaultArgoRolloutsDashboardPort,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(DefaultArgoRolloutsDashboardPort),
+				},
+			},
+			Selector: map[string]string{
+				DefaultRolloutsSelectorKey: DefaultArgoRolloutsDashboardResourceName,
+			},
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedSvc.ObjectMeta, cr)
+
+	if err := controllerutil.SetControllerReference(&cr, expectedSvc, r.Scheme); err != nil {
+		return err
+	}
+
+	return r.applyObject(ctx, expectedSvc)
+}
+
+// Reconcile the Ingress which exposes the Rollouts Dashboard Service, if enabled.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardIngress(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	ingressSpec := cr.Spec.Dashboard.Ingress
+
+	if ingressSpec == nil || !ingressSpec.Enabled {
+		return deleteObjectIfExists(ctx, r.Client, &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsDashboardResourceName, Namespace: cr.Namespace}})
+	}
+
+	path := ingressSpec.Path
+	if path == "" {
+		path = "/"
+	}
+	pathType := networkingv1.PathTypePrefix
+
+	expectedIngress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ingressSpec.IngressClassName,
+			TLS:              ingressSpec.TLS,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: ingressSpec.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     path,
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: DefaultArgoRolloutsDashboardResourceName,
+											Port: networkingv1.ServiceBackendPort{
+												Number: DefaultArgoRolloutsDashboardPort,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedIngress.ObjectMeta, cr)
+
+	expectedIngress.Annotations = combineStringMaps(expectedIngress.Annotations, ingressSpec.Annotations)
+
+	if err := controllerutil.SetControllerReference(&cr, expectedIngress, r.Scheme); err != nil {
+		return err
+	}
+
+	return r.applyObject(ctx, expectedIngress)
+}
+
+// Reconcile the OpenShift Route which exposes the Rollouts Dashboard Service, if enabled.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardRoute(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	routeSpec := cr.Spec.Dashboard.Route
+
+	if routeSpec == nil || !routeSpec.Enabled {
+		return deleteObjectIfExists(ctx, r.Client, newRolloutsDashboardRoute(cr))
+	}
+
+	expectedRoute := newRolloutsDashboardRoute(cr)
+
+	expectedMetadata := metav1.ObjectMeta{}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedMetadata, cr)
+
+	expectedRoute.SetLabels(expectedMetadata.Labels)
+	expectedRoute.SetAnnotations(combineStringMaps(expectedMetadata.Annotations, routeSpec.Annotations))
+	if err := setRolloutsDashboardRouteSpec(expectedRoute, routeSpec); err != nil {
+		return err
+	}
+
+	if err := controllerutil.SetControllerReference(&cr, expectedRoute, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.applyObject(ctx, expectedRoute); err != nil {
+		if meta.IsNoMatchError(err) {
+			return fmt.Errorf("unable to create Route %s, the OpenShift Route API is not available on the cluster: %w", expectedRoute.GetName(), err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// newRolloutsDashboardRoute returns an empty unstructured OpenShift Route with the name/namespace of the Rollouts Dashboard Route.
+func newRolloutsDashboardRoute(cr rolloutsmanagerv1alpha1.RolloutManager) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(routeGVK)
+	route.SetName(DefaultArgoRolloutsDashboardResourceName)
+	route.SetNamespace(cr.Namespace)
+	return route
+}
+
+// setRolloutsDashboardRouteSpec sets the fields of the Route .spec that are managed by the operator. Other fields (for example, a host generated by OpenShift) are owned by other field managers.
+func setRolloutsDashboardRouteSpec(route *unstructured.Unstructured, routeSpec *rolloutsmanagerv1alpha1.RolloutManagerDashboardRouteSpec) error {
+
+	if err := unstructured.SetNestedField(route.Object, "Service", "spec", "to", "kind"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(route.Object, DefaultArgoRolloutsDashboardResourceName, "spec", "to", "name"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedField(route.Object, "dashboard", "spec", "port", "targetPort"); err != nil {
+		return err
+	}
+
+	if routeSpec.Host != "" {
+		if err := unstructured.SetNestedField(route.Object, routeSpec.Host, "spec", "host"); err != nil {
+			return err
+		}
+	}
+
+	if routeSpec.TLSTermination == "" {
+		unstructured.RemoveNestedField(route.Object, "spec", "tls")
+		return nil
+	}
+
+	tls := map[string]interface{}{
+		"termination": routeSpec.TLSTermination,
+	}
+	if routeSpec.InsecureEdgeTerminationPolicy != "" {
+		tls["insecureEdgeTerminationPolicy"] = routeSpec.InsecureEdgeTerminationPolicy
+	}
+	return unstructured.SetNestedMap(route.Object, tls, "spec", "tls")
+}
+
+// reconcileRolloutsDashboardNetworkPolicy reconciles the NetworkPolicy for the Rollouts Dashboard
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardNetworkPolicy(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	if !cr.Spec.NetworkPolicy.IsEnabled() {
+		return deleteObjectIfExists(ctx, r.Client, &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: DefaultRolloutsDashboardNetworkPolicy, Namespace: cr.Namespace}})
+	}
+
+	tcpProtocol := corev1.ProtocolTCP
+	dashboardPort := intstr.FromInt(DefaultArgoRolloutsDashboardPort)
+
+	desired := generateNetworkPolicyHeaders(cr, DefaultRolloutsDashboardNetworkPolicy)
+	desired.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				DefaultRolloutsSelectorKey: DefaultArgoRolloutsDashboardResourceName,
+			},
+		},
+		PolicyTypes: []networkingv1.PolicyType{
+			networkingv1.PolicyTypeIngress,
+		},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{
+			{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{
+						Protocol: &tcpProtocol,
+						Port:     &dashboardPort,
+					},
+				},
+			},
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(&cr, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on rollouts dashboard network policy: %w", err)
+	}
+
+	return r.applyObject(ctx, desired)
+}
+
+// deleteRolloutsDashboardResources deletes all the resources of the Rollouts Dashboard, if they exist.
+func (r *RolloutManagerReconciler) deleteRolloutsDashboardResources(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	namespacedObjects := []client.Object{
+		newRolloutsDashboardRoute(cr),
+		&networkingv1.Ingress{},
+		&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: DefaultRolloutsDashboardNetworkPolicy}},
+		&corev1.Service{},
+		&appsv1.Deployment{},
+	}
+
+	// The ServiceAccount and RBAC of the Dashboard are part of the RBAC component, so they are left untouched while it is unmanaged
+	rbacUnmanaged := isComponentUnmanaged(cr, rolloutsmanagerv1alpha1.ComponentRBAC)
+	if !rbacUnmanaged {
+		namespacedObjects = append(namespacedObjects, &corev1.ServiceAccount{})
+	}
+
+	for _, obj := range namespacedObjects {
+		if obj.GetName() == "" {
+			obj.SetName(DefaultArgoRolloutsDashboardResourceName)
+		}
+		obj.SetNamespace(cr.Namespace)
+
+		if err := deleteObjectIfExists(ctx, r.Client, obj); err != nil {
+			return err
+		}
+	}
+
+	if rbacUnmanaged {
+		return nil
+	}
+
+	if err := r.deleteRolloutsDashboardRoleAndRoleBinding(ctx, cr); err != nil {
+		return err
+	}
+
+	// The Dashboard ClusterRole/ClusterRoleBinding can only have been created by a cluster-scoped RolloutManager
+	if !cr.Spec.NamespaceScoped {
+		if err := r.deleteRolloutsDashboardClusterRoleAndClusterRoleBinding(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *RolloutManagerReconciler) deleteRolloutsDashboardRoleAndRoleBinding(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	if err := deleteObjectIfExists(ctx, r.Client, &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsDashboardResourceName, Namespace: cr.Namespace}}); err != nil {
+		return err
+	}
+
+	return deleteObjectIfExists(ctx, r.Client, &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsDashboardResourceName, Namespace: cr.Namespace}})
+}
+
+func (r *RolloutManagerReconciler) deleteRolloutsDashboardClusterRoleAndClusterRoleBinding(ctx context.Context) error {
+
+	if err := deleteObjectIfExists(ctx, r.Client, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsDashboardResourceName}}); err != nil {
+		return err
+	}
+
+	return deleteObjectIfExists(ctx, r.Client, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsDashboardResourceName}})
+}
+
+// deleteObjectIfExists deletes the given object, if it exists on the cluster. If the API of the object is not available on the cluster (for example, OpenShift Routes on a non-OpenShift cluster), the object is treated as not existing.
+func deleteObjectIfExists(ctx context.Context, k8sClient client.Client, obj client.Object) error {
+
+	if err := fetchObject(ctx, k8sClient, obj.GetNamespace(), obj.GetName(), obj); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get %s: %w", obj.GetName(), err)
+	}
+
+	log.Info(fmt.Sprintf("Deleting %s %s", reflect.TypeOf(obj).Elem().Name(), obj.GetName()))
+	if err := k8sClient.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// GetDashboardPolicyRules returns the policy rules for the Argo Rollouts Dashboard Role/ClusterRole.
+func GetDashboardPolicyRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{
+				"argoproj.io",
+			},
+			Resources: []string{
+				"rollouts",
+				"rollouts/status",
+				"rollouts/finalizers",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+				"patch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"argoproj.io",
+			},
+			Resources: []string{
+				"analysisruns",
+				"analysisruns/finalizers",
+				"experiments",
+				"experiments/finalizers",
+			},
+			Verbs: []string{
+				"create",
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"argoproj.io",
+			},
+			Resources: []string{
+				"analysistemplates",
+				"clusteranalysistemplates",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"apps",
+			},
+			Resources: []string{
+				"replicasets",
+				"deployments",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+				"update",
+				"patch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"pods",
+				"events",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+	}
+}
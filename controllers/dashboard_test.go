@@ -0,0 +1,342 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Rollouts Dashboard tests", func() {
+
+	var (
+		ctx context.Context
+		rm  *rolloutsmanagerv1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rm = makeTestRolloutManager(func(rm *rolloutsmanagerv1alpha1.RolloutManager) {
+			rm.Spec.Dashboard = &rolloutsmanagerv1alpha1.RolloutManagerDashboardSpec{Enabled: true}
+		})
+		r = makeTestReconciler(rm)
+		Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+	})
+
+	dashboardObjectKey := func() client.ObjectKey {
+		return client.ObjectKey{Name: DefaultArgoRolloutsDashboardResourceName, Namespace: rm.Namespace}
+	}
+
+	It("should not create any Dashboard resources when the Dashboard is not enabled", func() {
+		rm.Spec.Dashboard = nil
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		err := r.Client.Get(ctx, dashboardObjectKey(), &appsv1.Deployment{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should create the Dashboard resources for a cluster-scoped RolloutManager", func() {
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), &corev1.ServiceAccount{})).To(Succeed())
+
+		clusterRole := &rbacv1.ClusterRole{}
+		Expect(r.Client.Get(ctx, client.ObjectKey{Name: DefaultArgoRolloutsDashboardResourceName}, clusterRole)).To(Succeed())
+		Expect(clusterRole.Rules).To(Equal(GetDashboardPolicyRules()))
+
+		clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+		Expect(r.Client.Get(ctx, client.ObjectKey{Name: DefaultArgoRolloutsDashboardResourceName}, clusterRoleBinding)).To(Succeed())
+		Expect(clusterRoleBinding.Subjects[0].Name).To(Equal(DefaultArgoRolloutsDashboardResourceName))
+		Expect(clusterRoleBinding.Subjects[0].Namespace).To(Equal(rm.Namespace))
+
+		By("verifying that no namespaced Role is created")
+		err := r.Client.Get(ctx, dashboardObjectKey(), &rbacv1.Role{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		deployment := &appsv1.Deployment{}
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(DefaultArgoRolloutsDashboardResourceName))
+		Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal(DefaultArgoRolloutsDashboardImage + ":" + DefaultArgoRolloutsVersion))
+		Expect(container.Args).To(Equal([]string{"dashboard"}))
+		Expect(container.Ports[0].ContainerPort).To(Equal(int32(DefaultArgoRolloutsDashboardPort)))
+
+		service := &corev1.Service{}
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), service)).To(Succeed())
+		Expect(service.Spec.Selector).To(Equal(map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsDashboardResourceName}))
+		Expect(service.Spec.Ports[0].Port).To(Equal(int32(DefaultArgoRolloutsDashboardPort)))
+
+		networkPolicy := &networkingv1.NetworkPolicy{}
+		Expect(r.Client.Get(ctx, client.ObjectKey{Name: DefaultRolloutsDashboardNetworkPolicy, Namespace: rm.Namespace}, networkPolicy)).To(Succeed())
+		Expect(networkPolicy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsDashboardResourceName}))
+
+		By("verifying that no Ingress is created by default")
+		err = r.Client.Get(ctx, dashboardObjectKey(), &networkingv1.Ingress{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should create a Role/RoleBinding for a namespace-scoped RolloutManager", func() {
+		rm.Spec.NamespaceScoped = true
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		role := &rbacv1.Role{}
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), role)).To(Succeed())
+		Expect(role.Rules).To(Equal(GetDashboardPolicyRules()))
+
+		roleBinding := &rbacv1.RoleBinding{}
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), roleBinding)).To(Succeed())
+		Expect(roleBinding.RoleRef.Kind).To(Equal("Role"))
+
+		err := r.Client.Get(ctx, client.ObjectKey{Name: DefaultArgoRolloutsDashboardResourceName}, &rbacv1.ClusterRole{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should use the image, version, resources and node placement from .spec.dashboard", func() {
+		rm.Spec.Dashboard.Image = "quay.io/my-org/kubectl-argo-rollouts"
+		rm.Spec.Dashboard.Version = "v1.2.3"
+		rm.Spec.Dashboard.Resources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		}
+		rm.Spec.Dashboard.NodePlacement = &rolloutsmanagerv1alpha1.RolloutsNodePlacementSpec{
+			NodeSelector: map[string]string{"key1": "value1"},
+			Tolerations: []corev1.Toleration{
+				{Key: "key1", Operator: corev1.TolerationOpEqual, Value: "value1", Effect: corev1.TaintEffectNoSchedule},
+			},
+		}
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		deployment := &appsv1.Deployment{}
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("quay.io/my-org/kubectl-argo-rollouts:v1.2.3"))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Resources).To(Equal(*rm.Spec.Dashboard.Resources))
+		Expect(deployment.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux", "key1": "value1"}))
+		Expect(deployment.Spec.Template.Spec.Tolerations).To(Equal(rm.Spec.Dashboard.NodePlacement.Tolerations))
+	})
+
+	It("should revert modifications made to the Dashboard Deployment", func() {
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		deployment := &appsv1.Deployment{}
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), deployment)).To(Succeed())
+		deployment.Spec.Template.Spec.Containers[0].Image = "quay.io/some-other/image:latest"
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal(DefaultArgoRolloutsDashboardImage + ":" + DefaultArgoRolloutsVersion))
+	})
+
+	It("should create, update and delete the Dashboard Ingress", func() {
+		ingressClassName := "nginx"
+		rm.Spec.Dashboard.Ingress = &rolloutsmanagerv1alpha1.RolloutManagerDashboardIngressSpec{
+			Enabled:          true,
+			Host:             "rollouts.example.com",
+			IngressClassName: &ingressClassName,
+			Annotations:      map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": "true"},
+		}
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/ssl-redirect", "true"))
+		Expect(*ingress.Spec.IngressClassName).To(Equal(ingressClassName))
+		Expect(ingress.Spec.Rules).To(HaveLen(1))
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("rollouts.example.com"))
+		path := ingress.Spec.Rules[0].HTTP.Paths[0]
+		Expect(path.Path).To(Equal("/"))
+		Expect(path.Backend.Service.Name).To(Equal(DefaultArgoRolloutsDashboardResourceName))
+		Expect(path.Backend.Service.Port.Number).To(Equal(int32(DefaultArgoRolloutsDashboardPort)))
+
+		By("updating the host of the Ingress")
+		rm.Spec.Dashboard.Ingress.Host = "dashboard.example.com"
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), ingress)).To(Succeed())
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("dashboard.example.com"))
+
+		By("disabling the Ingress")
+		rm.Spec.Dashboard.Ingress.Enabled = false
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+		err := r.Client.Get(ctx, dashboardObjectKey(), ingress)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should create and delete the Dashboard Route", func() {
+		rm.Spec.Dashboard.Route = &rolloutsmanagerv1alpha1.RolloutManagerDashboardRouteSpec{
+			Enabled:        true,
+			Host:           "rollouts.apps.example.com",
+			TLSTermination: "edge",
+			Annotations:    map[string]string{"haproxy.router.openshift.io/timeout": "60s"},
+		}
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		route := newRolloutsDashboardRoute(*rm)
+		Expect(r.Client.Get(ctx, dashboardObjectKey(), route)).To(Succeed())
+		Expect(route.GetAnnotations()).To(HaveKeyWithValue("haproxy.router.openshift.io/timeout", "60s"))
+		host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+		Expect(host).To(Equal("rollouts.apps.example.com"))
+		termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+		Expect(termination).To(Equal("edge"))
+
+		By("disabling the Route")
+		rm.Spec.Dashboard.Route.Enabled = false
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+		err := r.Client.Get(ctx, dashboardObjectKey(), newRolloutsDashboardRoute(*rm))
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should generate the expected Route .spec", func() {
+		routeSpec := &rolloutsmanagerv1alpha1.RolloutManagerDashboardRouteSpec{
+			Enabled:                       true,
+			Host:                          "rollouts.apps.example.com",
+			TLSTermination:                "edge",
+			InsecureEdgeTerminationPolicy: "Redirect",
+		}
+
+		route := newRolloutsDashboardRoute(*rm)
+		Expect(setRolloutsDashboardRouteSpec(route, routeSpec)).To(Succeed())
+		Expect(route.Object["spec"]).To(Equal(map[string]interface{}{
+			"host": "rollouts.apps.example.com",
+			"to": map[string]interface{}{
+				"kind": "Service",
+				"name": DefaultArgoRolloutsDashboardResourceName,
+			},
+			"port": map[string]interface{}{
+				"targetPort": "dashboard",
+			},
+			"tls": map[string]interface{}{
+				"termination":                   "edge",
+				"insecureEdgeTerminationPolicy": "Redirect",
+			},
+		}))
+
+		By("removing TLS from the Route")
+		routeSpec.TLSTermination = ""
+		routeSpec.InsecureEdgeTerminationPolicy = ""
+		Expect(setRolloutsDashboardRouteSpec(route, routeSpec)).To(Succeed())
+		Expect(route.Object["spec"]).ToNot(HaveKey("tls"))
+	})
+
+	It("should not create the Dashboard NetworkPolicy when NetworkPolicies are disabled", func() {
+		rm.Spec.NetworkPolicy = &rolloutsmanagerv1alpha1.RolloutManagerNetworkPolicySpec{Enabled: boolPtr(false)}
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		err := r.Client.Get(ctx, client.ObjectKey{Name: DefaultRolloutsDashboardNetworkPolicy, Namespace: rm.Namespace}, &networkingv1.NetworkPolicy{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should remove all Dashboard resources when the Dashboard is disabled", func() {
+		rm.Spec.Dashboard.Ingress = &rolloutsmanagerv1alpha1.RolloutManagerDashboardIngressSpec{Enabled: true}
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		rm.Spec.Dashboard.Enabled = false
+		Expect(r.reconcileRolloutsDashboard(ctx, *rm)).To(Succeed())
+
+		for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}, &corev1.ServiceAccount{}, &networkingv1.Ingress{}} {
+			err := r.Client.Get(ctx, dashboardObjectKey(), obj)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "%T should have been deleted", obj)
+		}
+
+		err := r.Client.Get(ctx, client.ObjectKey{Name: DefaultRolloutsDashboardNetworkPolicy, Namespace: rm.Namespace}, &networkingv1.NetworkPolicy{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		err = r.Client.Get(ctx, client.ObjectKey{Name: DefaultArgoRolloutsDashboardResourceName}, &rbacv1.ClusterRole{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		err = r.Client.Get(ctx, client.ObjectKey{Name: DefaultArgoRolloutsDashboardResourceName}, &rbacv1.ClusterRoleBinding{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	When("the RolloutManager is reconciled", func() {
+
+		BeforeEach(func() {
+			os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+		})
+
+		It("should report the status of the Dashboard, and only report the RolloutManager as Available once the Dashboard is Available", func() {
+			res, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updateStatusConditionOfRolloutManager(ctx, res, rm, r.Client, log)).To(Succeed())
+
+			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(rm), rm)).To(Succeed())
+			Expect(rm.Status.Dashboard).To(Equal(rolloutsmanagerv1alpha1.PhasePending))
+			Expect(rm.Status.Phase).To(Equal(rolloutsmanagerv1alpha1.PhasePending))
+
+			By("marking the controller Deployment as ready")
+			controllerDeployment := &appsv1.Deployment{}
+			Expect(r.Client.Get(ctx, client.ObjectKey{Name: DefaultArgoRolloutsResourceName, Namespace: rm.Namespace}, controllerDeployment)).To(Succeed())
+			controllerDeployment.Status.ReadyReplicas = *controllerDeployment.Spec.Replicas
+			Expect(r.Client.Status().Update(ctx, controllerDeployment)).To(Succeed())
+
+			res, err = r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updateStatusConditionOfRolloutManager(ctx, res, rm, r.Client, log)).To(Succeed())
+			Expect(rm.Status.RolloutController).To(Equal(rolloutsmanagerv1alpha1.PhaseAvailable))
+			Expect(rm.Status.Phase).To(Equal(rolloutsmanagerv1alpha1.PhasePending))
+
+			By("marking the Dashboard Deployment as ready")
+			dashboardDeployment := &appsv1.Deployment{}
+			Expect(r.Client.Get(ctx, dashboardObjectKey(), dashboardDeployment)).To(Succeed())
+			dashboardDeployment.Status.ReadyReplicas = *dashboardDeployment.Spec.Replicas
+			Expect(r.Client.Status().Update(ctx, dashboardDeployment)).To(Succeed())
+
+			res, err = r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updateStatusConditionOfRolloutManager(ctx, res, rm, r.Client, log)).To(Succeed())
+			Expect(rm.Status.Dashboard).To(Equal(rolloutsmanagerv1alpha1.PhaseAvailable))
+			Expect(rm.Status.Phase).To(Equal(rolloutsmanagerv1alpha1.PhaseAvailable))
+
+			By("disabling the Dashboard")
+			rm.Spec.Dashboard.Enabled = false
+			res, err = r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updateStatusConditionOfRolloutManager(ctx, res, rm, r.Client, log)).To(Succeed())
+			Expect(rm.Status.Dashboard).To(BeEmpty())
+			Expect(rm.Status.Phase).To(Equal(rolloutsmanagerv1alpha1.PhaseAvailable))
+		})
+	})
+})
+
+var _ = Describe("getRolloutsDashboardContainerImage tests", func() {
+
+	It("should reject an invalid .spec.dashboard.image", func() {
+		rm := makeTestRolloutManager(func(rm *rolloutsmanagerv1alpha1.RolloutManager) {
+			rm.Spec.Dashboard = &rolloutsmanagerv1alpha1.RolloutManagerDashboardSpec{
+				Enabled: true,
+				Image:   "quay.io/Invalid/Image",
+			}
+		})
+		_, err := getRolloutsDashboardContainerImage(*rm)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should support a digest in .spec.dashboard.version", func() {
+		rm := makeTestRolloutManager(func(rm *rolloutsmanagerv1alpha1.RolloutManager) {
+			rm.Spec.Dashboard = &rolloutsmanagerv1alpha1.RolloutManagerDashboardSpec{
+				Enabled: true,
+				Version: "sha256:" + "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+			}
+		})
+		image, err := getRolloutsDashboardContainerImage(*rm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(image).To(Equal(DefaultArgoRolloutsDashboardImage + "@sha256:a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2"))
+	})
+})
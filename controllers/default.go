@@ -21,6 +21,16 @@ const (
 	// DefaultRolloutsNotificationSecretName is the default name for rollout controller secret resource.
 	DefaultRolloutsNotificationSecretName = "argo-rollouts-notification-secret" // #nosec G101
 
+	// DefaultRolloutsNotificationConfigMapName is the default name of the ConfigMap that contains the Rollouts notification configuration.
+	DefaultRolloutsNotificationConfigMapName = "argo-rollouts-notification-configmap"
+
+	// RolloutsConfigHashAnnotation is the annotation of the Rollouts controller pod template that contains a hash of the configuration read by the Rollouts controller on startup.
+	// When the configuration changes, the pods are replaced via the strategy of the Deployment.
+	RolloutsConfigHashAnnotation = "argo-rollouts-manager.argoproj.io/config-hash"
+
+	// FieldManager is the name of the field manager used by the operator to server-side apply the resources of the Rollouts controller and the Dashboard.
+	FieldManager = "argo-rollouts-manager"
+
 	// DefaultRolloutsServiceSelectorKey is key used by selector
 	DefaultRolloutsSelectorKey = "app.kubernetes.io/name"
 
@@ -30,7 +40,8 @@ const (
 	// DefaultRolloutsConfigMapName is the default name of the ConfigMap that contains the Rollouts controller configuration
 	DefaultRolloutsConfigMapName = "argo-rollouts-config"
 
-	DefaultOpenShiftRoutePluginURL = "https://github.com/argoproj-labs/rollouts-plugin-trafficrouter-openshift/releases/download/commit-8d0b3c6c5c18341f9f019cf1015b56b0d0c6085b/rollouts-plugin-trafficrouter-openshift-linux-amd64"
+	// DefaultOpenShiftRoutePluginVersion is the version of the OpenShift Route plugin of the plugin catalog, which is used unless the OPENSHIFT_ROUTE_PLUGIN_LOCATION environment variable is set
+	DefaultOpenShiftRoutePluginVersion = "commit-8d0b3c6c5c18341f9f019cf1015b56b0d0c6085b"
 
 	// NamespaceScopedArgoRolloutsController is an environment variable that can be used to configure scope of Argo Rollouts controller
 	// Set true to allow only namespace-scoped Argo Rollouts controller deployment and false for cluster-scoped
@@ -39,6 +50,15 @@ const (
 	// ClusterScopedArgoRolloutsNamespaces is an environment variable that can be used to configure namespaces that are allowed to host cluster-scoped Argo Rollouts
 	ClusterScopedArgoRolloutsNamespaces = "CLUSTER_SCOPED_ARGO_ROLLOUTS_NAMESPACES"
 
+	// PluginCatalogConfigMap is an environment variable that can be used to reference a ConfigMap, as '<namespace>/<name>', that extends or overrides the embedded plugin catalog
+	PluginCatalogConfigMap = "PLUGIN_CATALOG_CONFIGMAP"
+
+	// PluginCatalogConfigMapKey is the key of the plugin catalog ConfigMap that contains the plugin catalog
+	PluginCatalogConfigMapKey = "catalog.yaml"
+
+	// EnableWebhooks is an environment variable that can be used to enable the RolloutManager validating admission webhook
+	EnableWebhooks = "ENABLE_WEBHOOKS"
+
 	KubernetesHostnameLabel = "kubernetes.io/hostname"
 
 	TopologyKubernetesZoneLabel = "topology.kubernetes.io/zone"
@@ -46,4 +66,17 @@ const (
 	ImagePullPolicy = "IMAGE_PULL_POLICY"
 	// DefaultRolloutsNetworkPolicy is the default name for Rollouts NetworkPolicy.
 	DefaultRolloutsNetworkPolicy = "argo-rollouts-network-policy"
+
+	// DefaultArgoRolloutsDashboardResourceName is the default name for Rollouts Dashboard resources such as
+	// deployment, service, ingress, route, role, rolebinding and serviceaccount.
+	DefaultArgoRolloutsDashboardResourceName = "argo-rollouts-dashboard"
+
+	// DefaultArgoRolloutsDashboardImage is the default image for the Rollouts Dashboard.
+	DefaultArgoRolloutsDashboardImage = "quay.io/argoproj/kubectl-argo-rollouts"
+
+	// DefaultArgoRolloutsDashboardPort is the port on which the Rollouts Dashboard is served.
+	DefaultArgoRolloutsDashboardPort = 3100
+
+	// DefaultRolloutsDashboardNetworkPolicy is the default name for Rollouts Dashboard NetworkPolicy.
+	DefaultRolloutsDashboardNetworkPolicy = "argo-rollouts-dashboard-network-policy"
 )
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	"github.com/distribution/reference"
@@ -14,12 +17,19 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/leaderelection"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) (appsv1.Deployment, error) {
 
-	// NOTE: When updating this function, ensure that normalizeDeployment is updated as well. See that function for details.
+	if err := validateAdditionalVolumesAndContainers(cr); err != nil {
+		return appsv1.Deployment{}, err
+	}
+
+	if err := validateDeploymentSpec(cr); err != nil {
+		return appsv1.Deployment{}, err
+	}
 
 	// Configuration for the desired deployment
 	desiredDeployment := appsv1.Deployment{
@@ -44,11 +54,7 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 		}
 	}
 
-	// Default number of replicas is 1, update it to 2 if HA is enabled
-	var replicas int32 = 1
-	if cr.Spec.HA != nil && cr.Spec.HA.Enabled {
-		replicas = 2
-	}
+	replicas := getRolloutsReplicas(cr)
 
 	desiredDeployment.Spec = appsv1.DeploymentSpec{
 		Replicas: &replicas,
@@ -71,6 +77,20 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 		},
 	}
 
+	if deploymentSpec := cr.Spec.Deployment; deploymentSpec != nil {
+		if deploymentSpec.Strategy != nil {
+			desiredDeployment.Spec.Strategy = *deploymentSpec.Strategy.DeepCopy()
+			if desiredDeployment.Spec.Strategy.Type == "" {
+				desiredDeployment.Spec.Strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+			}
+		}
+		if deploymentSpec.MinReadySeconds != nil {
+			desiredDeployment.Spec.MinReadySeconds = *deploymentSpec.MinReadySeconds
+		}
+		desiredDeployment.Spec.RevisionHistoryLimit = deploymentSpec.RevisionHistoryLimit
+		desiredDeployment.Spec.ProgressDeadlineSeconds = deploymentSpec.ProgressDeadlineSeconds
+	}
+
 	if cr.Spec.HA != nil && cr.Spec.HA.Enabled {
 		desiredDeployment.Spec.Template.Spec.Affinity = &corev1.Affinity{
 			PodAntiAffinity: &corev1.PodAntiAffinity{
@@ -101,6 +121,7 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 		desiredDeployment.Spec.Template.Spec.NodeSelector = appendStringMap(
 			desiredDeployment.Spec.Template.Spec.NodeSelector, cr.Spec.NodePlacement.NodeSelector)
 		desiredDeployment.Spec.Template.Spec.Tolerations = cr.Spec.NodePlacement.Tolerations
+		setSchedulingConstraints(&desiredDeployment.Spec.Template.Spec, *cr.Spec.NodePlacement)
 	}
 
 	desiredPodSpec := &desiredDeployment.Spec.Template.Spec
@@ -116,179 +137,146 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 	if err != nil {
 		return appsv1.Deployment{}, err
 	}
-	desiredPodSpec.Containers = []corev1.Container{
-		rolloutsCont,
+	desiredPodSpec.Containers = append([]corev1.Container{rolloutsCont}, cr.Spec.SidecarContainers...)
+
+	// Plugins with an image source are copied into the plugin-bin volume by init containers, which run before those of the user
+	if desiredPodSpec.InitContainers, err = getPluginImageInitContainers(cr); err != nil {
+		return appsv1.Deployment{}, err
 	}
+	desiredPodSpec.InitContainers = append(desiredPodSpec.InitContainers, cr.Spec.InitContainers...)
 
 	desiredPodSpec.Volumes = []corev1.Volume{
 		{
-			Name: "plugin-bin",
+			Name: pluginBinVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
 		},
 		{
-			Name: "tmp",
+			Name: tmpVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
 		},
 	}
+	desiredPodSpec.Volumes = append(desiredPodSpec.Volumes, cr.Spec.Volumes...)
 
 	return desiredDeployment, nil
 }
 
-// Reconcile the Rollouts controller deployment.
-func (r *RolloutManagerReconciler) reconcileRolloutsDeployment(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) error {
+// isSuspended returns true if the Rollouts controller Deployment should be scaled to zero.
+func isSuspended(cr rolloutsmanagerv1alpha1.RolloutManager) bool {
+	return cr.Spec.Suspend
+}
 
-	desiredDeployment, err := generateDesiredRolloutsDeployment(cr, sa)
-	if err != nil {
-		return err
-	}
+// isDeploymentSuspended returns true if the RolloutManager is suspended, and its Rollouts controller Deployment has been scaled to zero.
+func isDeploymentSuspended(cr rolloutsmanagerv1alpha1.RolloutManager, deploy *appsv1.Deployment) bool {
+	return isSuspended(cr) && deploy.Spec.Replicas != nil && *deploy.Spec.Replicas == 0
+}
 
-	normalizedDesiredDeployment, err := normalizeDeployment(desiredDeployment, cr)
-	if err != nil {
-		// If you see this warning in the logs, verify that normalizedDeployment is fully consistent with generateDesiredRolloutsDeployment. See normalizeDeployment for details.
-		log.Error(fmt.Errorf("unexpected fail on normalizing generated rollouts Deployment"), "", "err", err)
-		// We intentionally continue without returning, as the error is non-fatal at runtime
+// getRolloutsReplicas returns the number of replicas of the Rollouts controller Deployment: 1, or .spec.ha.replicas (2 by default) if HA is enabled, or 0 if it is suspended.
+func getRolloutsReplicas(cr rolloutsmanagerv1alpha1.RolloutManager) int32 {
+	if isSuspended(cr) {
+		return 0
 	}
-
-	if !reflect.DeepEqual(normalizedDesiredDeployment, desiredDeployment) { // sanity test to verify that generateDesiredRolloutsDeployments and normalizeDeployment are consistent.
-		// If you see this warning in the logs, verify that normalizedDeployment is fully consistent with generateDesiredRolloutsDeployment. See normalizeDeployment for details.
-		deploymentsDifferent := identifyDeploymentDifference(normalizedDesiredDeployment, desiredDeployment)
-		log.Error(fmt.Errorf("normalized form of desired Deployment was not equal: %v", deploymentsDifferent), "")
-		// We intentionally continue without returning, as the error is non-fatal at runtime
+	if cr.Spec.HA == nil || !cr.Spec.HA.Enabled {
+		return 1
 	}
-
-	// If the deployment for rollouts does not exist, create one.
-	actualDeployment := &appsv1.Deployment{}
-
-	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, actualDeployment); err != nil {
-		if !errors.IsNotFound(err) {
-			return fmt.Errorf("failed to get the Deployment %s: %w", DefaultArgoRolloutsResourceName, err)
-		}
-
-		return r.createNewRolloutsDeployment(ctx, cr, desiredDeployment)
+	if cr.Spec.HA.Replicas != nil {
+		return *cr.Spec.HA.Replicas
 	}
+	return 2
+}
 
-	normalizedActualDeployment, err := normalizeDeployment(*actualDeployment, cr)
-
-	if err != nil || !reflect.DeepEqual(normalizedActualDeployment, normalizedDesiredDeployment) {
-
-		deploymentsDifferent := identifyDeploymentDifference(normalizedActualDeployment, normalizedDesiredDeployment)
-
-		log.Info("updating Deployment due to detected difference: " + deploymentsDifferent)
-
-		if !reflect.DeepEqual(normalizedActualDeployment.Spec.Selector, normalizedDesiredDeployment.Spec.Selector) {
-			// delete and recreate the Deployment if the .spec.selector field changes: this field is immutable.
-
-			log.Info("deleting and recreating Deployment, as the .spec.selector field of the Deployment has changed. Since this field is immutable, the Deployment needs to be recreated.")
+// validateDeploymentSpec returns an error if the replicas of .spec.ha, or the rollout options of .spec.deployment, are invalid.
+func validateDeploymentSpec(cr rolloutsmanagerv1alpha1.RolloutManager) error {
 
-			if err := r.Client.Delete(ctx, &desiredDeployment); err != nil {
-				return fmt.Errorf("unable to delete Rollouts Deployment after .spec.selector change: %w", err)
-			}
+	if cr.Spec.HA != nil && cr.Spec.HA.Replicas != nil && *cr.Spec.HA.Replicas < 2 {
+		return fmt.Errorf("invalid .spec.ha.replicas: %d must be at least 2", *cr.Spec.HA.Replicas)
+	}
 
-			return r.createNewRolloutsDeployment(ctx, cr, desiredDeployment)
-		}
+	if cr.Spec.Deployment == nil || cr.Spec.Deployment.Strategy == nil {
+		return nil
+	}
 
-		if deploymentsDifferent == "" {
-			log.Error(fmt.Errorf("warning: a difference was detected by DeepEqual, but not by identifyDeploymentDifference"), "")
-			// this error is a warning, only. Continue.
+	switch strategy := cr.Spec.Deployment.Strategy; strategy.Type {
+	case "", appsv1.RollingUpdateDeploymentStrategyType:
+	case appsv1.RecreateDeploymentStrategyType:
+		if strategy.RollingUpdate != nil {
+			return fmt.Errorf("invalid .spec.deployment.strategy: rollingUpdate may only be set with the RollingUpdate strategy type")
 		}
-
-		actualDeployment.Spec.Strategy = desiredDeployment.Spec.Strategy
-		actualDeployment.Spec.Template.Spec.Containers = desiredDeployment.Spec.Template.Spec.Containers
-		actualDeployment.Spec.Template.Spec.ServiceAccountName = desiredDeployment.Spec.Template.Spec.ServiceAccountName
-
-		actualDeployment.Spec.Replicas = desiredDeployment.Spec.Replicas
-		actualDeployment.Spec.Template.Spec.Affinity = desiredDeployment.Spec.Template.Spec.Affinity
-
-		actualDeployment.Labels = combineStringMaps(actualDeployment.Labels, desiredDeployment.Labels)
-		actualDeployment.Annotations = combineStringMaps(actualDeployment.Annotations, desiredDeployment.Annotations)
-
-		actualDeployment.Spec.Template.Labels = desiredDeployment.Spec.Template.Labels
-		actualDeployment.Spec.Template.Annotations = desiredDeployment.Spec.Template.Annotations
-		actualDeployment.Spec.Selector = desiredDeployment.Spec.Selector
-		actualDeployment.Spec.Template.Spec.NodeSelector = desiredDeployment.Spec.Template.Spec.NodeSelector
-		actualDeployment.Spec.Template.Spec.Tolerations = desiredDeployment.Spec.Template.Spec.Tolerations
-		actualDeployment.Spec.Template.Spec.SecurityContext = desiredDeployment.Spec.Template.Spec.SecurityContext
-		actualDeployment.Spec.Template.Spec.Volumes = desiredDeployment.Spec.Template.Spec.Volumes
-		return r.Client.Update(ctx, actualDeployment)
+	default:
+		return fmt.Errorf("invalid .spec.deployment.strategy: type %s must be one of RollingUpdate or Recreate", strategy.Type)
 	}
-	return nil
-}
 
-func (r *RolloutManagerReconciler) createNewRolloutsDeployment(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, desiredDeployment appsv1.Deployment) error {
-	if err := controllerutil.SetControllerReference(&cr, &desiredDeployment, r.Scheme); err != nil {
-		return err
-	}
-	log.Info(fmt.Sprintf("Creating Deployment %s", DefaultArgoRolloutsResourceName))
-	return r.Client.Create(ctx, &desiredDeployment)
+	return nil
 }
 
-// identifyDeploymentDifference is a simple comparison of the contents of two deployments, returning "" if they are the same, otherwise returning the name of the field that changed.
-func identifyDeploymentDifference(x appsv1.Deployment, y appsv1.Deployment) string {
+// setSchedulingConstraints sets the scheduling constraints of the node placement, other than the node selector and the tolerations, to the pod spec.
+// The node affinity and pod affinity of the node placement replace those of the pod spec, while its pod anti-affinity only replaces that of the pod spec if it is set:
+// this way, the pod anti-affinity added when HA is enabled is kept, unless the user provides their own.
+func setSchedulingConstraints(podSpec *corev1.PodSpec, nodePlacement rolloutsmanagerv1alpha1.RolloutsNodePlacementSpec) {
 
-	xPodSpec := x.Spec.Template.Spec
-	yPodSpec := y.Spec.Template.Spec
-
-	if !reflect.DeepEqual(xPodSpec.Containers, yPodSpec.Containers) {
-		return "Spec.Template.Spec.Containers"
-	}
-
-	if xPodSpec.ServiceAccountName != yPodSpec.ServiceAccountName {
-		return "ServiceAccountName"
-	}
+	podSpec.TopologySpreadConstraints = nodePlacement.TopologySpreadConstraints
+	podSpec.PriorityClassName = nodePlacement.PriorityClassName
+	podSpec.RuntimeClassName = nodePlacement.RuntimeClassName
+	podSpec.SchedulerName = nodePlacement.SchedulerName
 
-	if !reflect.DeepEqual(x.Spec.Strategy, y.Spec.Strategy) {
-		return ".Spec.Strategy"
+	if nodePlacement.Affinity == nil {
+		return
 	}
 
-	if !reflect.DeepEqual(x.Labels, y.Labels) {
-		return "Labels"
+	affinity := nodePlacement.Affinity.DeepCopy()
+	if affinity.PodAntiAffinity == nil && podSpec.Affinity != nil {
+		affinity.PodAntiAffinity = podSpec.Affinity.PodAntiAffinity
 	}
+	podSpec.Affinity = affinity
+}
 
-	if !reflect.DeepEqual(x.Annotations, y.Annotations) {
-		return "Annotations"
-	}
+// Reconcile the Rollouts controller deployment.
+func (r *RolloutManagerReconciler) reconcileRolloutsDeployment(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) error {
 
-	if !reflect.DeepEqual(x.Spec.Template.Labels, y.Spec.Template.Labels) {
-		return ".Spec.Template.Labels"
+	desiredDeployment, err := generateDesiredRolloutsDeployment(cr, sa)
+	if err != nil {
+		return err
 	}
 
-	if !reflect.DeepEqual(x.Spec.Template.Annotations, y.Spec.Template.Annotations) {
-		return ".Spec.Template.Annotations"
+	// A change of the configuration of the Rollouts controller rolls out new pods, via the config hash annotation of the pod template
+	configHash, err := r.getRolloutsConfigHash(ctx, cr)
+	if err != nil {
+		return err
 	}
+	desiredDeployment.Spec.Template.Annotations[RolloutsConfigHashAnnotation] = configHash
 
-	if !reflect.DeepEqual(x.Spec.Selector, y.Spec.Selector) {
-		return ".Spec.Selector"
+	generatedDeployment := *desiredDeployment.DeepCopy()
+	if err := applyOverrides(cr, overrideKindDeployment, desiredDeployment.Name, &desiredDeployment); err != nil {
+		return err
 	}
-
-	if !reflect.DeepEqual(x.Spec.Template.Spec.NodeSelector, y.Spec.Template.Spec.NodeSelector) {
-		return "Spec.Template.Spec.NodeSelector"
+	if err := validateOverriddenDeployment(generatedDeployment, desiredDeployment); err != nil {
+		return err
 	}
 
-	if !reflect.DeepEqual(x.Spec.Template.Spec.Tolerations, y.Spec.Template.Spec.Tolerations) {
-		return "Spec.Template.Spec.Tolerations"
-	}
+	actualDeployment := &appsv1.Deployment{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, actualDeployment); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the Deployment %s: %w", DefaultArgoRolloutsResourceName, err)
+		}
 
-	if !reflect.DeepEqual(xPodSpec.SecurityContext, yPodSpec.SecurityContext) {
-		return "Spec.Template.Spec.SecurityContext"
-	}
+	} else if !reflect.DeepEqual(actualDeployment.Spec.Selector, desiredDeployment.Spec.Selector) {
+		// delete and recreate the Deployment if the .spec.selector field changes: this field is immutable.
 
-	if !reflect.DeepEqual(x.Spec.Template.Spec.Volumes, y.Spec.Template.Spec.Volumes) {
-		return "Spec.Template.Spec.Volumes"
-	}
+		log.Info("deleting and recreating Deployment, as the .spec.selector field of the Deployment has changed. Since this field is immutable, the Deployment needs to be recreated.")
 
-	if !reflect.DeepEqual(x.Spec.Replicas, y.Spec.Replicas) {
-		return "Spec.Replicas"
+		if err := r.Client.Delete(ctx, actualDeployment); err != nil {
+			return fmt.Errorf("unable to delete Rollouts Deployment after .spec.selector change: %w", err)
+		}
 	}
 
-	if !reflect.DeepEqual(x.Spec.Template.Spec.Affinity, y.Spec.Template.Spec.Affinity) {
-		return "Spec.Template.Spec.Affinity"
+	if err := controllerutil.SetControllerReference(&cr, &desiredDeployment, r.Scheme); err != nil {
+		return err
 	}
 
-	return ""
+	return r.applyObject(ctx, &desiredDeployment)
 }
 
 // defaultRolloutsContainerResources return the default resource constaints set on containers, when the RolloutManager CR does not have resource constraints set.
@@ -302,8 +290,6 @@ func defaultRolloutsContainerResources() corev1.ResourceRequirements {
 
 func rolloutsContainer(cr rolloutsmanagerv1alpha1.RolloutManager) (corev1.Container, error) {
 
-	// NOTE: When updating this function, ensure that normalizeDeployment is updated as well. See that function for details.
-
 	// Global proxy env vars go firstArgoRollouts
 	rolloutsEnv := cr.Spec.Env
 
@@ -349,7 +335,7 @@ func rolloutsContainer(cr rolloutsmanagerv1alpha1.RolloutManager) (corev1.Contai
 			SuccessThreshold:    int32(1),
 			TimeoutSeconds:      int32(10),
 		},
-		Name: "argo-rollouts",
+		Name: rolloutsContainerName,
 		Ports: []corev1.ContainerPort{
 			{
 				ContainerPort: 8080,
@@ -373,254 +359,21 @@ func rolloutsContainer(cr rolloutsmanagerv1alpha1.RolloutManager) (corev1.Contai
 				Type: corev1.SeccompProfileTypeRuntimeDefault,
 			},
 		},
-		VolumeMounts: []corev1.VolumeMount{
+		VolumeMounts: append([]corev1.VolumeMount{
 			{
-				MountPath: "/home/argo-rollouts/plugin-bin",
-				Name:      "plugin-bin",
+				MountPath: pluginBinMountPath,
+				Name:      pluginBinVolumeName,
 			},
 			{
-				MountPath: "/tmp",
-				Name:      "tmp",
+				MountPath: tmpMountPath,
+				Name:      tmpVolumeName,
 			},
-		},
+		}, cr.Spec.VolumeMounts...),
 		Resources: *containerResources,
 	}, nil
 
 }
 
-// One of the goals of an operator is to reconcile the live state of a resource on the cluster, with a target state for that resource. However, one of the challenges in doing so is that some fields of the resource will naturally differ from the values that are generated: for example, some field have default values which are only set after creation. This can make it challenging to compare the live/target status. Various strategies exist to handle.
-//
-// The strategy used in this file is implemented here in normalizeDeployment: normalizeDeployment will created a normalized representation of any input Deployment: the normal form will only contains fields which are relevant/useful to the operator. All other fields will be discarded.
-//
-// You can then use...
-//
-//	reflect.DeepEqual( normalizeDeployment( /* desired deployment */), normalizeDeployment( /* actual deployment from k8s*/))
-//
-// ... to determine if the actual deployment from k8s needs to be updated.
-//
-// NOTE: When updating 'generateDesiredRolloutsDeployment', ensure this function is updated as well.
-// - Specifically, in generateDesiredRolloutsDeployment, if a new field of Deployment is modified, it should be added to the copy logic here.
-func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha1.RolloutManager) (appsv1.Deployment, error) {
-
-	input := inputParam.DeepCopy()
-
-	res := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        input.ObjectMeta.Name,
-			Namespace:   input.ObjectMeta.Namespace,
-			Labels:      input.ObjectMeta.Labels,
-			Annotations: input.ObjectMeta.Annotations,
-		},
-	}
-
-	// Remove labels/annotations from the Deployment that are not in the set of labels/annotations that the operator will add to resources.
-	standardLabelsAndAnnotations := input.ObjectMeta.DeepCopy()
-	setRolloutsLabelsAndAnnotationsToObject(standardLabelsAndAnnotations, cr)
-
-	for k := range res.Labels {
-		if _, exists := standardLabelsAndAnnotations.Labels[k]; !exists {
-			delete(res.Labels, k)
-		}
-	}
-	for k := range res.Annotations {
-		if _, exists := standardLabelsAndAnnotations.Annotations[k]; !exists {
-			delete(res.Annotations, k)
-		}
-	}
-
-	if input.Spec.Selector == nil {
-		return appsv1.Deployment{}, fmt.Errorf("missing .spec.selector")
-	}
-
-	inputSpecSecurityContext := input.Spec.Template.Spec.SecurityContext
-
-	if inputSpecSecurityContext == nil {
-		return appsv1.Deployment{}, fmt.Errorf("missing .spec.template.spec.securityContext")
-	}
-
-	inputSpecVolumes := input.Spec.Template.Spec.Volumes
-	if inputSpecVolumes == nil || len(inputSpecVolumes) != 2 {
-		return appsv1.Deployment{}, fmt.Errorf("missing .spec.template.spec.volumes")
-	}
-
-	// Default number of replicas is 1
-	var replicas int32 = 1
-	if input.Spec.Replicas != nil {
-		replicas = *input.Spec.Replicas
-	}
-
-	res.Spec = appsv1.DeploymentSpec{
-		Replicas: &replicas,
-		Selector: &metav1.LabelSelector{
-			MatchLabels: normalizeMap(input.Spec.Selector.MatchLabels),
-		},
-		Template: corev1.PodTemplateSpec{
-			ObjectMeta: metav1.ObjectMeta{
-				Labels:      normalizeMap(input.Spec.Template.Labels),
-				Annotations: normalizeMap(input.Spec.Template.Annotations),
-			},
-			Spec: corev1.PodSpec{
-				NodeSelector:       input.Spec.Template.Spec.NodeSelector,
-				Tolerations:        input.Spec.Template.Spec.Tolerations,
-				ServiceAccountName: input.Spec.Template.Spec.ServiceAccountName,
-				SecurityContext: &corev1.PodSecurityContext{
-					RunAsNonRoot: input.Spec.Template.Spec.SecurityContext.RunAsNonRoot,
-				},
-				Volumes: []corev1.Volume{inputSpecVolumes[0], inputSpecVolumes[1]},
-			},
-		},
-		Strategy: appsv1.DeploymentStrategy{
-			Type: input.Spec.Strategy.Type,
-			// we ignore the default values set in RollingUpdate:
-		},
-	}
-
-	if len(input.Spec.Template.Spec.Containers) != 1 {
-		return appsv1.Deployment{}, fmt.Errorf("incorrect number of .spec.template.spec.containers")
-	}
-
-	inputContainer := input.Spec.Template.Spec.Containers[0]
-	inputLivenessProbe := inputContainer.LivenessProbe
-	inputPorts := inputContainer.Ports
-	inputSecurityContext := inputContainer.SecurityContext
-	inputVolumeMounts := inputContainer.VolumeMounts
-
-	if inputLivenessProbe == nil {
-		return appsv1.Deployment{}, fmt.Errorf("incorrect liveness probe")
-	}
-
-	if inputLivenessProbe.ProbeHandler.HTTPGet == nil {
-		return appsv1.Deployment{}, fmt.Errorf("incorrect http get in liveness probe")
-
-	}
-
-	if inputPorts == nil || len(inputPorts) != 2 {
-		return appsv1.Deployment{}, fmt.Errorf("incorrect input ports")
-	}
-
-	if inputSecurityContext == nil || inputSecurityContext.Capabilities == nil {
-		return appsv1.Deployment{}, fmt.Errorf("incorrect security context")
-	}
-
-	if inputVolumeMounts == nil || len(inputVolumeMounts) != 2 {
-		return appsv1.Deployment{}, fmt.Errorf("incorrect volume mounts")
-	}
-
-	// Nil string slices need to be converted to empty string slices, because  reflect.DeepEqual(nil, []string{}) is false, despite being functionally the same, here.
-	if len(inputContainer.Args) == 0 {
-		inputContainer.Args = make([]string, 0)
-	}
-
-	if len(inputContainer.Env) == 0 {
-		inputContainer.Env = make([]corev1.EnvVar, 0)
-	}
-
-	if input.Spec.Template.Spec.Affinity != nil {
-
-		res.Spec.Template.Spec.Affinity = &corev1.Affinity{}
-
-		// As of this writing, we don't touch pod affinity field at at all, so just copy it as is: any changes to this field should be reverted.
-		res.Spec.Template.Spec.Affinity.PodAffinity = input.Spec.Template.Spec.Affinity.PodAffinity
-
-		// We do touch anti affinity field, so check it then copy it into res
-		if input.Spec.Template.Spec.Affinity.PodAntiAffinity != nil {
-
-			if len(input.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 {
-				return appsv1.Deployment{}, fmt.Errorf("incorrect number of anti-affinity PreferredDuringSchedulingIgnoredDuringExecution")
-			}
-
-			if len(input.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 1 {
-				return appsv1.Deployment{}, fmt.Errorf("incorrect number of anti-affinity RequiredDuringSchedulingIgnoredDuringExecution")
-			}
-
-			res.Spec.Template.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
-				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
-					{
-						PodAffinityTerm: corev1.PodAffinityTerm{
-							LabelSelector: &metav1.LabelSelector{
-								MatchLabels: normalizeMap(input.Spec.Selector.MatchLabels),
-							},
-							TopologyKey: input.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.TopologyKey,
-						},
-						Weight: input.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].Weight,
-					},
-				},
-				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-					{
-						LabelSelector: &metav1.LabelSelector{
-							MatchLabels: normalizeMap(input.Spec.Selector.MatchLabels),
-						},
-						TopologyKey: input.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey,
-					},
-				},
-			}
-		}
-	}
-
-	res.Spec.Template.Spec.Containers = []corev1.Container{{
-		Args:            inputContainer.Args,
-		Env:             inputContainer.Env,
-		Image:           inputContainer.Image,
-		ImagePullPolicy: inputContainer.ImagePullPolicy,
-		LivenessProbe: &corev1.Probe{
-			FailureThreshold: inputLivenessProbe.FailureThreshold,
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path: inputLivenessProbe.ProbeHandler.HTTPGet.Path,
-					Port: inputLivenessProbe.ProbeHandler.HTTPGet.Port,
-				},
-			},
-			InitialDelaySeconds: inputLivenessProbe.InitialDelaySeconds,
-			PeriodSeconds:       inputLivenessProbe.PeriodSeconds,
-			SuccessThreshold:    inputLivenessProbe.SuccessThreshold,
-			TimeoutSeconds:      inputLivenessProbe.TimeoutSeconds,
-		},
-		Name: inputContainer.Name,
-		Ports: []corev1.ContainerPort{
-			{
-				ContainerPort: inputPorts[0].ContainerPort,
-				Name:          inputPorts[0].Name,
-			},
-			{
-				ContainerPort: inputPorts[1].ContainerPort,
-				Name:          inputPorts[1].Name,
-			},
-		},
-		ReadinessProbe: inputContainer.ReadinessProbe,
-		Resources:      inputContainer.Resources,
-		SecurityContext: &corev1.SecurityContext{
-			Capabilities: &corev1.Capabilities{
-				Drop: inputSecurityContext.Capabilities.Drop,
-			},
-			AllowPrivilegeEscalation: inputSecurityContext.AllowPrivilegeEscalation,
-			ReadOnlyRootFilesystem:   inputSecurityContext.ReadOnlyRootFilesystem,
-			RunAsNonRoot:             inputSecurityContext.RunAsNonRoot,
-			SeccompProfile:           inputSecurityContext.SeccompProfile,
-		},
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				Name:      inputVolumeMounts[0].Name,
-				MountPath: inputVolumeMounts[0].MountPath,
-			},
-			{
-				Name:      inputVolumeMounts[1].Name,
-				MountPath: inputVolumeMounts[1].MountPath,
-			},
-		},
-	}}
-
-	return res, nil
-
-}
-
-// Confirm nil maps to empty map, to allow them to be compared by reflect.DeepEqual()
-func normalizeMap(in map[string]string) map[string]string {
-	if len(in) == 0 {
-		return make(map[string]string, 0)
-	}
-	return in
-}
-
 // boolPtr returns a pointer to val
 func boolPtr(val bool) *bool {
 	return &val
@@ -667,7 +420,14 @@ func getRolloutsContainerImage(cr rolloutsmanagerv1alpha1.RolloutManager) (strin
 		tag = DefaultArgoRolloutsVersion
 	}
 
-	return combineImageTag(image, tag), nil
+	combinedImage := combineImageTag(image, tag)
+
+	// Ensure the combination of .spec.image and .spec.version is a valid image reference
+	if _, err := extractBaseImageName(combinedImage); err != nil {
+		return "", fmt.Errorf("invalid image '%s' generated from RolloutManager .spec.image and .spec.version: %w", combinedImage, err)
+	}
+
+	return combinedImage, nil
 }
 
 // extractBaseImageName extracts the base image name from a full image reference (removing tag/digest)
@@ -725,10 +485,28 @@ func getRolloutsCommandArgs(cr rolloutsmanagerv1alpha1.RolloutManager) ([]string
 
 	if cr.Spec.HA != nil && cr.Spec.HA.Enabled {
 		args = append(args, "--leader-elect", "true")
+
+		leaderElectionArgs, err := getLeaderElectionArgs(cr.Spec.HA.LeaderElection)
+		if err != nil {
+			return args, err
+		}
+		args = append(args, leaderElectionArgs...)
+	}
+
+	tuningArgs, err := getControllerTuningArgs(cr.Spec.Controller)
+	if err != nil {
+		return args, err
 	}
+	args = append(args, tuningArgs...)
+
+	trafficRoutingArgs, err := getTrafficRoutingArgs(cr.Spec.TrafficRouting)
+	if err != nil {
+		return args, err
+	}
+	args = append(args, trafficRoutingArgs...)
 
 	extraArgs := cr.Spec.ExtraCommandArgs
-	err := isMergable(extraArgs, args)
+	err = isMergable(extraArgs, args)
 	if err != nil {
 		return args, err
 	}
@@ -736,3 +514,176 @@ func getRolloutsCommandArgs(cr rolloutsmanagerv1alpha1.RolloutManager) ([]string
 	args = append(args, extraArgs...)
 	return args, nil
 }
+
+// Default values of the Rollouts controller tuning options, from https://argo-rollouts.readthedocs.io/en/stable/generated/rollouts-controller/
+const (
+	defaultControllerRolloutThreads    = 10
+	defaultControllerExperimentThreads = 10
+	defaultControllerAnalysisThreads   = 30
+	defaultControllerQPS               = 40
+	defaultControllerBurst             = 80
+	defaultControllerRolloutResync     = 900
+	defaultControllerLogLevel          = "info"
+	defaultControllerLogFormat         = "text"
+	defaultControllerKLogLevel         = 0
+)
+
+// Default values of the Rollouts controller leader election options
+const (
+	defaultLeaderElectionLeaseDuration = 15 * time.Second
+	defaultLeaderElectionRenewDeadline = 10 * time.Second
+	defaultLeaderElectionRetryPeriod   = 2 * time.Second
+)
+
+// getLeaderElectionArgs returns the Rollouts controller command arguments for the given .spec.ha.leaderElection, or an error if it is invalid.
+func getLeaderElectionArgs(leaderElection *rolloutsmanagerv1alpha1.RolloutManagerLeaderElectionSpec) ([]string, error) {
+
+	if leaderElection == nil {
+		return nil, nil
+	}
+
+	args := []string{}
+
+	// Options that are not set are compared using the defaults of the Rollouts controller
+	effective := map[string]time.Duration{}
+
+	for _, field := range []struct {
+		flag         string
+		path         string
+		value        *metav1.Duration
+		defaultValue time.Duration
+	}{
+		{"--leader-election-lease-duration", "leaseDuration", leaderElection.LeaseDuration, defaultLeaderElectionLeaseDuration},
+		{"--leader-election-renew-deadline", "renewDeadline", leaderElection.RenewDeadline, defaultLeaderElectionRenewDeadline},
+		{"--leader-election-retry-period", "retryPeriod", leaderElection.RetryPeriod, defaultLeaderElectionRetryPeriod},
+	} {
+		effective[field.path] = field.defaultValue
+		if field.value == nil {
+			continue
+		}
+		if field.value.Duration <= 0 {
+			return nil, fmt.Errorf("invalid .spec.ha.leaderElection.%s: %s must be positive", field.path, field.value.Duration)
+		}
+		effective[field.path] = field.value.Duration
+		args = append(args, field.flag, field.value.Duration.String())
+	}
+
+	// The same constraints as those of the leader election of client-go, which the Rollouts controller would otherwise fail on
+	if effective["leaseDuration"] <= effective["renewDeadline"] {
+		return nil, fmt.Errorf("invalid .spec.ha.leaderElection: leaseDuration (%s) must be greater than renewDeadline (%s)", effective["leaseDuration"], effective["renewDeadline"])
+	}
+	if float64(effective["renewDeadline"]) <= leaderelection.JitterFactor*float64(effective["retryPeriod"]) {
+		return nil, fmt.Errorf("invalid .spec.ha.leaderElection: renewDeadline (%s) must be greater than %.1f times retryPeriod (%s)", effective["renewDeadline"], leaderelection.JitterFactor, effective["retryPeriod"])
+	}
+
+	return args, nil
+}
+
+// getControllerTuningArgs returns the Rollouts controller command arguments for the given .spec.controller, or an error if it is invalid.
+func getControllerTuningArgs(controller *rolloutsmanagerv1alpha1.RolloutManagerControllerSpec) ([]string, error) {
+
+	if controller == nil {
+		return nil, nil
+	}
+
+	args := []string{}
+
+	for _, field := range []struct {
+		flag  string
+		path  string
+		value *int32
+		min   int32
+	}{
+		{"--rollout-threads", "rolloutThreads", controller.RolloutThreads, 1},
+		{"--experiment-threads", "experimentThreads", controller.ExperimentThreads, 1},
+		{"--analysis-threads", "analysisThreads", controller.AnalysisThreads, 1},
+		{"--qps", "qps", controller.QPS, 1},
+		{"--burst", "burst", controller.Burst, 1},
+		{"--rollout-resync", "rolloutResync", controller.RolloutResync, 1},
+		{"--kloglevel", "klogLevel", controller.KLogLevel, 0},
+	} {
+		if field.value == nil {
+			continue
+		}
+		if *field.value < field.min {
+			return nil, fmt.Errorf("invalid .spec.controller.%s: %d must be at least %d", field.path, *field.value, field.min)
+		}
+		args = append(args, field.flag, strconv.Itoa(int(*field.value)))
+	}
+
+	if controller.LogLevel != "" {
+		if !contains([]string{"debug", "info", "warn", "error"}, controller.LogLevel) {
+			return nil, fmt.Errorf("invalid .spec.controller.logLevel: %s must be one of debug, info, warn or error", controller.LogLevel)
+		}
+		args = append(args, "--loglevel", controller.LogLevel)
+	}
+
+	if controller.LogFormat != "" {
+		if !contains([]string{"text", "json"}, controller.LogFormat) {
+			return nil, fmt.Errorf("invalid .spec.controller.logFormat: %s must be one of text or json", controller.LogFormat)
+		}
+		args = append(args, "--logformat", controller.LogFormat)
+	}
+
+	return args, nil
+}
+
+// getEffectiveControllerSettings returns the tuning options that the Rollouts controller will run with, based on the given command arguments and the controller defaults.
+// Integer options whose value cannot be parsed (for example, a fractional --qps passed via ExtraCommandArgs) are omitted.
+func getEffectiveControllerSettings(args []string) *rolloutsmanagerv1alpha1.RolloutManagerControllerSpec {
+
+	int32Ptr := func(val int32) *int32 { return &val }
+
+	res := &rolloutsmanagerv1alpha1.RolloutManagerControllerSpec{
+		RolloutThreads:    int32Ptr(defaultControllerRolloutThreads),
+		ExperimentThreads: int32Ptr(defaultControllerExperimentThreads),
+		AnalysisThreads:   int32Ptr(defaultControllerAnalysisThreads),
+		QPS:               int32Ptr(defaultControllerQPS),
+		Burst:             int32Ptr(defaultControllerBurst),
+		RolloutResync:     int32Ptr(defaultControllerRolloutResync),
+		LogLevel:          defaultControllerLogLevel,
+		LogFormat:         defaultControllerLogFormat,
+		KLogLevel:         int32Ptr(defaultControllerKLogLevel),
+	}
+
+	intFields := map[string]**int32{
+		"--rollout-threads":    &res.RolloutThreads,
+		"--experiment-threads": &res.ExperimentThreads,
+		"--analysis-threads":   &res.AnalysisThreads,
+		"--qps":                &res.QPS,
+		"--burst":              &res.Burst,
+		"--rollout-resync":     &res.RolloutResync,
+		"--kloglevel":          &res.KLogLevel,
+	}
+
+	for i := 0; i < len(args); i++ {
+
+		if !isFlagArg(args[i]) {
+			continue
+		}
+
+		// Support both '--flag=value' and '--flag value' forms
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		if !hasValue && i+1 < len(args) && !isFlagArg(args[i+1]) {
+			value = args[i+1]
+			i++
+		}
+
+		switch flag {
+		case "--loglevel":
+			res.LogLevel = value
+		case "--logformat":
+			res.LogFormat = value
+		default:
+			if field, exists := intFields[flag]; exists {
+				if parsed, err := strconv.ParseInt(value, 10, 32); err == nil {
+					*field = int32Ptr(int32(parsed))
+				} else {
+					*field = nil
+				}
+			}
+		}
+	}
+
+	return res
+}
@@ -2,9 +2,8 @@ package rollouts
 
 import (
 	"context"
-	"fmt"
 	"os"
-	"reflect"
+	"time"
 
 	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 
@@ -12,9 +11,12 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -101,7 +103,8 @@ var _ = Describe("Deployment Test", func() {
 			Expect(fetchedDeployment.Spec.Template.Spec.NodeSelector).To(Equal(expectedDeployment.Spec.Template.Spec.NodeSelector))
 			Expect(fetchedDeployment.Spec.Template.Spec.Tolerations).To(Equal(expectedDeployment.Spec.Template.Spec.Tolerations))
 			Expect(fetchedDeployment.Spec.Template.Spec.SecurityContext).To(Equal(expectedDeployment.Spec.Template.Spec.SecurityContext))
-			Expect(fetchedDeployment.Spec.Template.Spec.Volumes).To(Equal(expectedDeployment.Spec.Template.Spec.Volumes))
+			Expect(fetchedDeployment.Spec.Template.Spec.Volumes).To(ContainElements(expectedDeployment.Spec.Template.Spec.Volumes), "operator-added volumes should be present")
+			Expect(getVolumeNames(fetchedDeployment.Spec.Template.Spec.Volumes)).To(ContainElements("plugin-bin-test", "tmp-test"), "volumes of other field managers should still be present")
 			Expect(fetchedDeployment.Spec.Template.Spec.Containers[0].Resources).To(Equal(expectedDeployment.Spec.Template.Spec.Containers[0].Resources))
 
 		})
@@ -155,6 +158,23 @@ var _ = Describe("Deployment Test", func() {
 			},
 		}
 
+		nonDefaultContainerResourcesWithDefaultLimits := nonDefaultContainerResourcesValue.DeepCopy()
+		for name, quantity := range defaultContainerResources.Limits {
+			nonDefaultContainerResourcesWithDefaultLimits.Limits[name] = quantity
+		}
+
+		It("should revert to the default container resources when .spec.controllerResources is removed from the CR", func() {
+			a.Spec.ControllerResources = nonDefaultContainerResourcesValue
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			a.Spec.ControllerResources = nil
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			fetchedDeployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, fetchedDeployment)).To(Succeed())
+			Expect(fetchedDeployment.Spec.Template.Spec.Containers[0].Resources).To(Equal(defaultContainerResources))
+		})
+
 		DescribeTable("Deployment CR should always be updated to be consistent with RolloutsManager .spec.controllerResources field, in both default and non-default cases", func(initialDeployment *corev1.ResourceRequirements, crValue *corev1.ResourceRequirements, expectedDeployment *corev1.ResourceRequirements) {
 
 			By("calling reconcileRolloutsDeployment to create the initial set of rollout resources")
@@ -186,7 +206,7 @@ var _ = Describe("Deployment Test", func() {
 		},
 			Entry("default deployment, with a empty CR .spec.containerResources -> no change in deployment from default", &defaultContainerResources, nil, &defaultContainerResources),
 			Entry("default deployment, with CR non-default value in .spec.containerResources -> deployment should now have value from CR", &defaultContainerResources, nonDefaultContainerResourcesValue, nonDefaultContainerResourcesValue),
-			Entry("deployment with non-default container resources, empty value in CR .spec.containerResources -> Deployment should revert to default value from CR", nonDefaultContainerResourcesValue, nil, &defaultContainerResources),
+			Entry("deployment with non-default container resources, empty value in CR .spec.containerResources -> Deployment should restore the default value, and keep the resources of other field managers", nonDefaultContainerResourcesValue, nil, nonDefaultContainerResourcesWithDefaultLimits),
 			Entry("deployment with a different non-default container resources, non-default value in CR .spec.containerResources -> Deployment should use CR value", &otherNonDefault, nonDefaultContainerResourcesValue, nonDefaultContainerResourcesValue),
 		)
 
@@ -242,128 +262,32 @@ var _ = Describe("Deployment Test", func() {
 
 	})
 
-	When("the deployment has not changed", func() {
-		It("should not report any difference, both before and after normalization", func() {
-
-			expectedDeployment, err := deploymentCR(DefaultArgoRolloutsResourceName, a.Namespace, DefaultArgoRolloutsResourceName, []string{"plugin-bin", "tmp"}, "linux", DefaultArgoRolloutsResourceName, a)
-			Expect(err).ToNot(HaveOccurred())
-			Expect(identifyDeploymentDifference(*expectedDeployment, *expectedDeployment)).To(Equal(""), "comparing the object with itself should always report no differences")
-
-			expectedDeployment, err = deploymentCR(DefaultArgoRolloutsResourceName, a.Namespace, DefaultArgoRolloutsResourceName, []string{"plugin-bin", "tmp"}, "linux", DefaultArgoRolloutsResourceName, a)
-			Expect(err).ToNot(HaveOccurred())
-			expectedDeploymentNormalized, err := normalizeDeployment(*expectedDeployment, a)
-			Expect(err).To(Succeed())
-
-			Expect(identifyDeploymentDifference(expectedDeploymentNormalized, *expectedDeployment)).To(Equal(""))
-			Expect(identifyDeploymentDifference(*expectedDeployment, expectedDeploymentNormalized)).To(Equal(""))
-			Expect(identifyDeploymentDifference(*expectedDeployment, *expectedDeployment)).To(Equal(""))
-
-		})
-	})
-
-	When("normalizeDeployment is called with a Deployment containing additional, user-defined labels/annotations", func() {
-
-		It("should ensure the user-defiend labels/annotations are be removed by called to normalizeDeployment, while preserving the values contributed by the operation", func() {
-
-			originalDeployment, err := deploymentCR(DefaultArgoRolloutsResourceName, a.Namespace, DefaultArgoRolloutsResourceName, []string{"plugin-bin", "tmp"}, "linux", DefaultArgoRolloutsResourceName, a)
-			Expect(err).ToNot(HaveOccurred())
-
-			By("creating a new object with user added labels/annotations")
-			new := originalDeployment.DeepCopy()
-
-			new.Annotations["newAnnotation"] = "newAnnotationValue"
-			Expect(identifyDeploymentDifference(*originalDeployment, *new)).To(Equal("Annotations"), "identifyDeploymentDifference should correctly identify that the annotations value has changed")
-
-			new.Labels["newLabel"] = "newLabelValue"
-			Expect(identifyDeploymentDifference(*originalDeployment, *new)).To(Equal("Labels"), "identifyDeploymentDifference should correctly identify that the labels value has changed")
-
-			By("calling normalizeDeployment on the object with user defined values")
-			res, err := normalizeDeployment(*new, a)
-			Expect(err).To(BeNil())
-
-			Expect(res.Labels).ToNot(HaveKey("newLabel"), "user label should not be present")
-			Expect(res.Annotations).ToNot(HaveKey("newAnnotation"), "user annotation should not be present")
-			for k := range originalDeployment.Annotations {
-				Expect(res.Annotations).To(HaveKey(k), "default operator annotations should still be present")
-			}
-			for k := range originalDeployment.Labels {
-				Expect(res.Labels).To(HaveKey(k), "default operator labels should still be present")
-			}
-
-		})
-	})
-
 	When("the Rollouts Deployment resource is changed by the user, outside of the operator", func() {
 
-		areEqual := func(x appsv1.Deployment, y appsv1.Deployment, rm v1alpha1.RolloutManager) bool {
-
-			xRes, xErr := normalizeDeployment(x, rm)
-			yRes, yErr := normalizeDeployment(y, rm)
-
-			if fmt.Sprintf("%v", xErr) != fmt.Sprintf("%v", yErr) {
-				return false
-			}
-
-			res := reflect.DeepEqual(xRes, yRes)
-
-			// Sanity test that identifyDeploymentDifference gives the same result as reflect.DeepEqual
-			deploymentDiff := identifyDeploymentDifference(x, y)
-			ExpectWithOffset(0, res == (deploymentDiff == "")).To(BeTrue())
-
-			return res
-		}
-
 		DescribeTable("controller should detect and revert the change", func(fxn func(deployment *appsv1.Deployment)) {
 
-			By("ensuring that deploymentCR properly detects the change")
-			defaultDeployment, err := deploymentCR(DefaultArgoRolloutsResourceName, a.Namespace, DefaultArgoRolloutsResourceName, []string{"plugin-bin", "tmp"}, "linux", DefaultArgoRolloutsResourceName, a)
-			Expect(err).ToNot(HaveOccurred())
-
-			defaultDeploymentModified, err := deploymentCR(DefaultArgoRolloutsResourceName, a.Namespace, DefaultArgoRolloutsResourceName, []string{"plugin-bin", "tmp"}, "linux", DefaultArgoRolloutsResourceName, a)
-			Expect(err).ToNot(HaveOccurred())
-
-			Expect(identifyDeploymentDifference(*defaultDeployment, *defaultDeploymentModified)).To(BeEmpty(), "they should be the same before one is modified")
-
-			fxn(defaultDeploymentModified)
-			Expect(identifyDeploymentDifference(*defaultDeployment, *defaultDeploymentModified)).ToNot(BeEmpty(), "after being modified, they should no longer be the same")
-
-			By("ensuring the reconcileRolloutsDeployment will detect and revert the change")
-			{
-				By("calling reconcileRolloutsDeployment to create a default Deployment")
-				expectedDepl := appsv1.Deployment{
-					ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace},
-				}
-				Expect(r.reconcileRolloutsDeployment(context.Background(), a, *sa)).To(Succeed())
-				Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(&expectedDepl), &expectedDepl)).To(Succeed())
-				updatedDepl := expectedDepl.DeepCopy()
-				Expect(areEqual(*updatedDepl, expectedDepl, a)).To(BeTrue(), "copy should be same as original")
-
-				By("updating the Deployment using the function, and then updating the cluster resource")
-				fxn(updatedDepl)
-				Expect(r.Client.Update(context.Background(), updatedDepl)).To(Succeed())
-
-				By("retrieving the cluster resource after the update, to ensure it is equal to the updated version")
-				updatedDeplFromClient := appsv1.Deployment{
-					ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace},
-				}
-				Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(&updatedDeplFromClient), &updatedDeplFromClient)).To(Succeed())
-				Expect(areEqual(*updatedDepl, updatedDeplFromClient, a)).To(BeTrue(), "resource on cluster should match the resource we called Update with")
-
-				Expect(areEqual(updatedDeplFromClient, expectedDepl, a)).ToNot(BeTrue(), "resource on cluster should NOT match the original Deployment that was created by the call to reconcileRolloutsDeployment")
+			By("calling reconcileRolloutsDeployment to create a default Deployment")
+			expectedDepl := appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace},
+			}
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&expectedDepl), &expectedDepl)).To(Succeed())
 
-				By("calling reconcileRolloutsDeployment again, it should revert the change back to default")
-				Expect(r.reconcileRolloutsDeployment(context.Background(), a, *sa)).To(Succeed())
+			By("updating the Deployment using the function, as another field manager")
+			updatedDepl := expectedDepl.DeepCopy()
+			fxn(updatedDepl)
+			Expect(r.Client.Update(ctx, updatedDepl)).To(Succeed())
 
-				finalDeplFromClient := appsv1.Deployment{
-					ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace},
-				}
+			updatedDeplFromClient := appsv1.Deployment{}
+			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&expectedDepl), &updatedDeplFromClient)).To(Succeed())
+			Expect(updatedDeplFromClient.Spec).ToNot(Equal(expectedDepl.Spec), "resource on cluster should NOT match the original Deployment that was created by the call to reconcileRolloutsDeployment")
 
-				By("retrieving the Deployment version from the cluster")
-				Expect(r.Client.Get(context.Background(), client.ObjectKeyFromObject(&finalDeplFromClient), &finalDeplFromClient)).To(Succeed())
-				Expect(areEqual(finalDeplFromClient, expectedDepl, a)).To(BeTrue(), "version from cluster should have been reconciled back to the default")
-
-			}
+			By("calling reconcileRolloutsDeployment again, it should revert the change back to default")
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
 
+			finalDeplFromClient := appsv1.Deployment{}
+			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&expectedDepl), &finalDeplFromClient)).To(Succeed())
+			Expect(finalDeplFromClient.Spec).To(Equal(expectedDepl.Spec), "version from cluster should have been reconciled back to the default")
 		},
 			Entry("spec.selector", func(deployment *appsv1.Deployment) {
 				deployment.Spec.Selector = &metav1.LabelSelector{
@@ -375,12 +299,8 @@ var _ = Describe("Deployment Test", func() {
 					Type: "not-a-real-strategy",
 				}
 			}),
-			Entry(".spec.template.spec.containers.args", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.Containers[0].Args = []string{"new", "args"}
-			}),
-			Entry(".spec.template.spec.containers.env", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
-					{Name: "my-env", Value: "my-env-value"}}
+			Entry(".spec.template.spec.containers.image", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.Containers[0].Image = "quay.io/example/argo-rollouts:latest"
 			}),
 			Entry(".spec.template.spec.containers.resources", func(deployment *appsv1.Deployment) {
 				deployment.Spec.Template.Spec.Containers[0].Resources = corev1.ResourceRequirements{
@@ -390,61 +310,84 @@ var _ = Describe("Deployment Test", func() {
 				}
 			}),
 			Entry(".spec.template.spec.containers.volumeMounts", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
-					{Name: "not-an-expected-name"},
-				}
+				deployment.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name = "not-an-expected-name"
 			}),
 			Entry(".spec.template.spec.serviceAccountName", func(deployment *appsv1.Deployment) {
 				deployment.Spec.Template.Spec.ServiceAccountName = "different-service-account-name"
 			}),
 			Entry(".spec.template.labels", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Labels = map[string]string{"new": "label"}
+				deployment.Spec.Template.Labels[DefaultRolloutsSelectorKey] = "not-an-expected-value"
 			}),
 			Entry(".spec.template.spec.nodeSelector", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.NodeSelector = map[string]string{"my": "node"}
-			}),
-			Entry(".spec.template.spec.tolerations", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "value"}}
+				deployment.Spec.Template.Spec.NodeSelector["kubernetes.io/os"] = "windows"
 			}),
 			Entry(".spec.template.spec.securityContext", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
-					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost},
-				}
+				deployment.Spec.Template.Spec.SecurityContext.RunAsNonRoot = boolPtr(false)
 			}),
 			Entry(".spec.template.spec.volumes", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "my-volume"}}
-			}),
-			Entry(".spec.template.spec.containers.resources", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.Containers[0].Resources = corev1.ResourceRequirements{
-					Requests: corev1.ResourceList{
-						corev1.ResourceCPU:    resource.MustParse("100m"),
-						corev1.ResourceMemory: resource.MustParse("100Mi"),
-					},
-					Limits: corev1.ResourceList{
-						corev1.ResourceCPU:    resource.MustParse("500m"),
-						corev1.ResourceMemory: resource.MustParse("500Mi"),
-					},
-				}
-			}),
-			Entry(".spec.replicas", func(deployment *appsv1.Deployment) {
-				var replicas int32 = 2
-				deployment.Spec.Replicas = &replicas
-			}),
-			Entry(".spec.template.spec.affinity.podantiaffinity", func(deployment *appsv1.Deployment) {
-				deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{
-					PodAntiAffinity: &corev1.PodAntiAffinity{
-						RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-							{TopologyKey: "TopologyKey"},
-						},
-						PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
-							{PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "ToplogyKey"}},
-						},
-					},
-				}
+				deployment.Spec.Template.Spec.Volumes = deployment.Spec.Template.Spec.Volumes[1:]
 			}),
 		)
 	})
 
+	When("the Rollouts Deployment is modified by other field managers", func() {
+
+		It("should keep the fields added by other field managers, and report the fields of the operator that were modified", func() {
+			a.Spec.AdditionalMetadata = &v1alpha1.ResourceMetadata{Annotations: map[string]string{"team": "a"}}
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			By("adding a sidecar container, an environment variable, a volume and a label, as a service mesh would, and modifying the replicas, an annotation and the image")
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			podSpec := &deployment.Spec.Template.Spec
+			podSpec.Containers = append(podSpec.Containers, corev1.Container{Name: "istio-proxy", Image: "docker.io/istio/proxyv2:1.24.0"})
+			podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{Name: "MESH_ENABLED", Value: "true"})
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{Name: "istio-envoy", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+			deployment.Spec.Template.Labels["security.istio.io/tlsMode"] = "istio"
+			replicas := int32(5)
+			deployment.Spec.Replicas = &replicas
+			deployment.Spec.Template.Annotations["team"] = "b"
+			image := podSpec.Containers[0].Image
+			podSpec.Containers[0].Image = "quay.io/example/argo-rollouts:latest"
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+			By("reconciling the Deployment")
+			conflictsCtx, conflicts := withApplyConflicts(ctx)
+			Expect(r.reconcileRolloutsDeployment(conflictsCtx, a, *sa)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			podSpec = &deployment.Spec.Template.Spec
+			Expect(getContainerNames(podSpec.Containers)).To(Equal([]string{"argo-rollouts", "istio-proxy"}))
+			Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "MESH_ENABLED", Value: "true"}))
+			Expect(getVolumeNames(podSpec.Volumes)).To(ContainElement("istio-envoy"))
+			Expect(deployment.Spec.Template.Labels).To(HaveKeyWithValue("security.istio.io/tlsMode", "istio"))
+
+			By("verifying that the image was restored, that the replicas and the annotation were left to the other field manager, and that the conflicts were reported")
+			Expect(podSpec.Containers[0].Image).To(Equal(image))
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(5)))
+			Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue("team", "b"))
+			Expect(conflicts.conflicts).To(ConsistOf(ContainSubstring("Deployment argo-rollouts: .spec.template.spec.containers[name=\"argo-rollouts\"].image")))
+			Expect(conflicts.unforcedConflicts).To(ConsistOf(SatisfyAll(
+				ContainSubstring("Deployment argo-rollouts: "),
+				ContainSubstring(".spec.replicas"),
+				ContainSubstring(".spec.template.metadata.annotations.team"),
+			)))
+			condition := conflicts.getFieldManagerConflictCondition()
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonConflictingFieldManagers))
+			Expect(condition.Message).To(ContainSubstring("have been left as is: Deployment argo-rollouts: "))
+
+			By("reconciling again, which should only report the conflicts on the fields that were left to the other field manager")
+			conflictsCtx, conflicts = withApplyConflicts(ctx)
+			Expect(r.reconcileRolloutsDeployment(conflictsCtx, a, *sa)).To(Succeed())
+			Expect(conflicts.conflicts).To(BeEmpty())
+			Expect(conflicts.unforcedConflicts).To(HaveLen(1))
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(5)))
+		})
+	})
+
 	When("HA is enabled via RolloutManager .spec.HA", func() {
 
 		It("should have a Deployment that contains two replicas and the --leader-elect argument, and it should have anti-affinity rule is added by default when HA is enabled", func() {
@@ -484,13 +427,13 @@ var _ = Describe("Deployment Test", func() {
 			Expect(preferred).To(HaveLen(1))
 			Expect(preferred[0].Weight).To(Equal(int32(100)))
 			Expect(preferred[0].PodAffinityTerm.TopologyKey).To(Equal(TopologyKubernetesZoneLabel))
-			Expect(preferred[0].PodAffinityTerm.LabelSelector.MatchLabels).To(Equal(normalizeMap(fetchedDeployment.Spec.Selector.MatchLabels)))
+			Expect(preferred[0].PodAffinityTerm.LabelSelector.MatchLabels).To(Equal(fetchedDeployment.Spec.Selector.MatchLabels))
 
 			By("Verify RequiredDuringSchedulingIgnoredDuringExecution")
 			required := affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
 			Expect(required).To(HaveLen(1))
 			Expect(required[0].TopologyKey).To(Equal(KubernetesHostnameLabel))
-			Expect(required[0].LabelSelector.MatchLabels).To(Equal(normalizeMap(fetchedDeployment.Spec.Selector.MatchLabels)))
+			Expect(required[0].LabelSelector.MatchLabels).To(Equal(fetchedDeployment.Spec.Selector.MatchLabels))
 		})
 	})
 })
@@ -591,121 +534,53 @@ var _ = Describe("generateDesiredRolloutsDeployment tests", func() {
 				},
 			}))
 		})
-	})
-})
-
-var _ = Describe("normalizeDeployment tests to verify that an error is returned", func() {
-	var (
-		a          v1alpha1.RolloutManager
-		deployment *appsv1.Deployment
-	)
-
-	BeforeEach(func() {
-		a = *makeTestRolloutManager()
 
-		// Set up a valid deployment object
-		var err error
-		deployment, err = deploymentCR(DefaultArgoRolloutsResourceName, a.Namespace, DefaultArgoRolloutsResourceName, []string{"plugin-bin", "tmp"}, "linux", DefaultArgoRolloutsResourceName, a)
-		Expect(err).ToNot(HaveOccurred())
-	})
-
-	DescribeTable("should return an error when",
-		func(modifyDeployment func(), expectedError string) {
-			modifyDeployment()
-			_, err := normalizeDeployment(*deployment, a)
-			Expect(err).To(HaveOccurred())
-			Expect(err).To(MatchError(expectedError))
-		},
-
-		Entry("spec.selector is nil", func() {
-			deployment.Spec.Selector = nil
-		}, "missing .spec.selector"),
-
-		Entry("spec.template.spec.securityContext is nil", func() {
-			deployment.Spec.Template.Spec.SecurityContext = nil
-		}, "missing .spec.template.spec.securityContext"),
-
-		Entry("spec.template.spec.volumes is nil", func() {
-			deployment.Spec.Template.Spec.Volumes = nil
-		}, "missing .spec.template.spec.volumes"),
-
-		Entry("spec.template.spec.volumes has incorrect length", func() {
-			deployment.Spec.Template.Spec.Volumes = []corev1.Volume{
-				{Name: "volume1"},
+		It("should set the scheduling constraints, and keep the HA pod anti-affinity unless it is replaced", func() {
+			runtimeClassName := "gvisor"
+			nodeAffinity := &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "node-role.kubernetes.io/infra", Operator: corev1.NodeSelectorOpExists}},
+				}}},
 			}
-		}, "missing .spec.template.spec.volumes"),
-
-		Entry("spec.template.spec.containers has incorrect length", func() {
-			deployment.Spec.Template.Spec.Containers = []corev1.Container{
-				{Name: "test-1"},
-				{Name: "test-2"},
-			}
-		}, "incorrect number of .spec.template.spec.containers"),
-
-		Entry("liveness probe is nil", func() {
-			deployment.Spec.Template.Spec.Containers[0].LivenessProbe = nil
-		}, "incorrect liveness probe"),
+			topologySpreadConstraints := []corev1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       TopologyKubernetesZoneLabel,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName}},
+			}}
+
+			cr.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true}
+			cr.Spec.NodePlacement.Affinity = &corev1.Affinity{NodeAffinity: nodeAffinity}
+			cr.Spec.NodePlacement.TopologySpreadConstraints = topologySpreadConstraints
+			cr.Spec.NodePlacement.PriorityClassName = "system-cluster-critical"
+			cr.Spec.NodePlacement.RuntimeClassName = &runtimeClassName
+			cr.Spec.NodePlacement.SchedulerName = "custom-scheduler"
 
-		Entry("liveness probe http get is nil", func() {
-			deployment.Spec.Template.Spec.Containers[0].LivenessProbe.ProbeHandler.HTTPGet = nil
-		}, "incorrect http get in liveness probe"),
-
-		Entry("input ports is nil", func() {
-			deployment.Spec.Template.Spec.Containers[0].Ports = nil
-		}, "incorrect input ports"),
-
-		Entry("input ports has incorrect length", func() {
-			deployment.Spec.Template.Spec.Containers[0].Ports = []corev1.ContainerPort{
-				{ContainerPort: 8080, Name: "http"},
-			}
-		}, "incorrect input ports"),
-
-		Entry("security context is nil", func() {
-			deployment.Spec.Template.Spec.Containers[0].SecurityContext = nil
-		}, "incorrect security context"),
-
-		Entry("security context capabilities is nil", func() {
-			deployment.Spec.Template.Spec.Containers[0].SecurityContext.Capabilities = nil
-		}, "incorrect security context"),
-
-		Entry("volume mounts is nil", func() {
-			deployment.Spec.Template.Spec.Containers[0].VolumeMounts = nil
-		}, "incorrect volume mounts"),
+			deployment, err := generateDesiredRolloutsDeployment(cr, sa)
+			Expect(err).ToNot(HaveOccurred())
 
-		Entry("volume mounts has incorrect length", func() {
-			deployment.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
-				{Name: "volume1", MountPath: "/mnt/volume1"},
-			}
-		}, "incorrect volume mounts"),
-
-		Entry("input.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution has incorrect length: if pod anti affinity is set, it should have only 1 PreferredDuringSchedulingIgnoredDuringExecution", func() {
-			deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{
-				PodAntiAffinity: &corev1.PodAntiAffinity{
-					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
-						PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "PodAffinityTerm1"},
-					}, {
-						PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "PodAffinityTerm2"},
-					}},
-				},
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.Affinity.NodeAffinity).To(Equal(nodeAffinity))
+			Expect(podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey).To(Equal(KubernetesHostnameLabel))
+			Expect(podSpec.TopologySpreadConstraints).To(Equal(topologySpreadConstraints))
+			Expect(podSpec.PriorityClassName).To(Equal("system-cluster-critical"))
+			Expect(podSpec.RuntimeClassName).To(Equal(&runtimeClassName))
+			Expect(podSpec.SchedulerName).To(Equal("custom-scheduler"))
+
+			By("replacing the HA pod anti-affinity with the one of the user")
+			podAntiAffinity := &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+					Weight:          50,
+					PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: KubernetesHostnameLabel},
+				}},
 			}
-		}, "incorrect number of anti-affinity PreferredDuringSchedulingIgnoredDuringExecution"),
+			cr.Spec.NodePlacement.Affinity.PodAntiAffinity = podAntiAffinity
 
-		Entry("input.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution has incorrect length: if pod anti affinity is set, it should have only 1 RequiredDuringSchedulingIgnoredDuringExecution", func() {
-			deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{
-				PodAntiAffinity: &corev1.PodAntiAffinity{
-
-					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
-						PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "PodAffinityTerm1"}, // this is valid
-					}},
-
-					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{ // too many
-						{TopologyKey: "TopologyKey1"},
-						{TopologyKey: "TopologyKey2"},
-					},
-				},
-			}
-		}, "incorrect number of anti-affinity RequiredDuringSchedulingIgnoredDuringExecution"),
-	)
+			deployment, err = generateDesiredRolloutsDeployment(cr, sa)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.Affinity.PodAntiAffinity).To(Equal(podAntiAffinity))
+		})
+	})
 })
 
 var _ = Describe("getRolloutsContainerImage tests", func() {
@@ -886,6 +761,358 @@ var _ = Describe("rolloutsContainer tests", func() {
 	})
 })
 
+var _ = Describe("getRolloutsCommandArgs tests", func() {
+
+	int32Ptr := func(val int32) *int32 { return &val }
+
+	It("should render the fields of .spec.controller as command arguments, followed by ExtraCommandArgs", func() {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Controller = &v1alpha1.RolloutManagerControllerSpec{
+				RolloutThreads:    int32Ptr(20),
+				ExperimentThreads: int32Ptr(5),
+				AnalysisThreads:   int32Ptr(15),
+				QPS:               int32Ptr(50),
+				Burst:             int32Ptr(100),
+				RolloutResync:     int32Ptr(600),
+				LogLevel:          "debug",
+				LogFormat:         "json",
+				KLogLevel:         int32Ptr(0),
+			}
+			rm.Spec.ExtraCommandArgs = []string{"--service-threads=5"}
+		})
+
+		args, err := getRolloutsCommandArgs(cr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args).To(Equal([]string{
+			"--rollout-threads", "20",
+			"--experiment-threads", "5",
+			"--analysis-threads", "15",
+			"--qps", "50",
+			"--burst", "100",
+			"--rollout-resync", "600",
+			"--kloglevel", "0",
+			"--loglevel", "debug",
+			"--logformat", "json",
+			"--service-threads=5",
+		}))
+	})
+
+	DescribeTable("should return an error for invalid .spec.controller fields", func(controller v1alpha1.RolloutManagerControllerSpec, expectedError string) {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Controller = &controller
+		})
+		_, err := getRolloutsCommandArgs(cr)
+		Expect(err).To(MatchError(expectedError))
+	},
+		Entry("zero rollout threads", v1alpha1.RolloutManagerControllerSpec{RolloutThreads: int32Ptr(0)}, "invalid .spec.controller.rolloutThreads: 0 must be at least 1"),
+		Entry("negative qps", v1alpha1.RolloutManagerControllerSpec{QPS: int32Ptr(-1)}, "invalid .spec.controller.qps: -1 must be at least 1"),
+		Entry("negative klog level", v1alpha1.RolloutManagerControllerSpec{KLogLevel: int32Ptr(-1)}, "invalid .spec.controller.klogLevel: -1 must be at least 0"),
+		Entry("unknown log level", v1alpha1.RolloutManagerControllerSpec{LogLevel: "verbose"}, "invalid .spec.controller.logLevel: verbose must be one of debug, info, warn or error"),
+		Entry("unknown log format", v1alpha1.RolloutManagerControllerSpec{LogFormat: "xml"}, "invalid .spec.controller.logFormat: xml must be one of text or json"),
+	)
+
+	DescribeTable("should detect conflicts between .spec.controller and ExtraCommandArgs", func(extraArgs []string) {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Controller = &v1alpha1.RolloutManagerControllerSpec{QPS: int32Ptr(50)}
+			rm.Spec.ExtraCommandArgs = extraArgs
+		})
+		_, err := getRolloutsCommandArgs(cr)
+		Expect(err).To(MatchError(ContainSubstring("is already part of the default command arguments")))
+	},
+		Entry("flag and value as separate arguments", []string{"--qps", "60"}),
+		Entry("flag and value as a single argument", []string{"--qps=60"}),
+	)
+
+	It("should render the leader election durations of .spec.ha as command arguments, only when HA is enabled", func() {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{
+				Enabled: true,
+				LeaderElection: &v1alpha1.RolloutManagerLeaderElectionSpec{
+					LeaseDuration: &metav1.Duration{Duration: 30 * time.Second},
+					RetryPeriod:   &metav1.Duration{Duration: 5 * time.Second},
+				},
+			}
+		})
+
+		args, err := getRolloutsCommandArgs(cr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args).To(Equal([]string{
+			"--leader-elect", "true",
+			"--leader-election-lease-duration", "30s",
+			"--leader-election-retry-period", "5s",
+		}))
+
+		cr.Spec.HA.Enabled = false
+		args, err = getRolloutsCommandArgs(cr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args).To(BeEmpty())
+	})
+
+	DescribeTable("should return an error for invalid leader election durations", func(leaderElection v1alpha1.RolloutManagerLeaderElectionSpec, expectedError string) {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true, LeaderElection: &leaderElection}
+		})
+		_, err := getRolloutsCommandArgs(cr)
+		Expect(err).To(MatchError(expectedError))
+	},
+		Entry("negative duration", v1alpha1.RolloutManagerLeaderElectionSpec{RetryPeriod: &metav1.Duration{Duration: -time.Second}},
+			"invalid .spec.ha.leaderElection.retryPeriod: -1s must be positive"),
+		Entry("lease duration not greater than the default renew deadline", v1alpha1.RolloutManagerLeaderElectionSpec{LeaseDuration: &metav1.Duration{Duration: 10 * time.Second}},
+			"invalid .spec.ha.leaderElection: leaseDuration (10s) must be greater than renewDeadline (10s)"),
+		Entry("renew deadline too short for the retry period", v1alpha1.RolloutManagerLeaderElectionSpec{RenewDeadline: &metav1.Duration{Duration: 2 * time.Second}},
+			"invalid .spec.ha.leaderElection: renewDeadline (2s) must be greater than 1.2 times retryPeriod (2s)"),
+	)
+})
+
+var _ = Describe("Deployment rollout options tests", func() {
+
+	int32Ptr := func(val int32) *int32 { return &val }
+
+	var sa corev1.ServiceAccount
+
+	BeforeEach(func() {
+		sa = corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}}
+	})
+
+	It("should set the HA replicas and the rollout options of .spec.deployment", func() {
+		maxSurge := intstr.FromInt32(0)
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true, Replicas: int32Ptr(3)}
+			rm.Spec.Deployment = &v1alpha1.RolloutManagerDeploymentSpec{
+				Strategy:                &appsv1.DeploymentStrategy{RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &maxSurge}},
+				MinReadySeconds:         int32Ptr(10),
+				RevisionHistoryLimit:    int32Ptr(3),
+				ProgressDeadlineSeconds: int32Ptr(300),
+			}
+		})
+
+		deployment, err := generateDesiredRolloutsDeployment(cr, sa)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deployment.Spec.Replicas).To(Equal(int32Ptr(3)))
+		Expect(deployment.Spec.Strategy).To(Equal(appsv1.DeploymentStrategy{
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &maxSurge},
+		}))
+		Expect(deployment.Spec.MinReadySeconds).To(Equal(int32(10)))
+		Expect(deployment.Spec.RevisionHistoryLimit).To(Equal(int32Ptr(3)))
+		Expect(deployment.Spec.ProgressDeadlineSeconds).To(Equal(int32Ptr(300)))
+	})
+
+	It("should neither update the Deployment when the API server sets the default rollout options, nor keep the rollout options that are no longer set", func() {
+		ctx := context.Background()
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Deployment = &v1alpha1.RolloutManagerDeploymentSpec{RevisionHistoryLimit: int32Ptr(3)}
+		})
+		r := makeTestReconciler(&cr)
+		Expect(createNamespace(r, cr.Namespace)).To(Succeed())
+		sa.Namespace = cr.Namespace
+
+		Expect(r.reconcileRolloutsDeployment(ctx, cr, sa)).To(Succeed())
+
+		By("simulating the defaults set by the API server")
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		maxSurge, maxUnavailable := intstr.FromString("25%"), intstr.FromString("25%")
+		deployment.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{MaxSurge: &maxSurge, MaxUnavailable: &maxUnavailable}
+		deployment.Spec.ProgressDeadlineSeconds = int32Ptr(600)
+		deployment.Spec.Template.Spec.SchedulerName = corev1.DefaultSchedulerName
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+		resourceVersion := deployment.ResourceVersion
+
+		Expect(r.reconcileRolloutsDeployment(ctx, cr, sa)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.ResourceVersion).To(Equal(resourceVersion))
+
+		By("removing the revision history limit from .spec.deployment")
+		cr.Spec.Deployment = nil
+		Expect(r.reconcileRolloutsDeployment(ctx, cr, sa)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.Spec.RevisionHistoryLimit).To(BeNil())
+		Expect(deployment.Spec.ProgressDeadlineSeconds).To(Equal(int32Ptr(600)))
+	})
+
+	DescribeTable("should reject invalid replicas and rollout options", func(opt rolloutManagerOpt, expectedError string) {
+		cr := *makeTestRolloutManager(opt)
+		Expect(validateDeploymentSpec(cr)).To(MatchError(expectedError))
+		_, err := generateDesiredRolloutsDeployment(cr, sa)
+		Expect(err).To(MatchError(expectedError))
+	},
+		Entry("with less than 2 HA replicas", rolloutManagerOpt(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true, Replicas: int32Ptr(1)}
+		}), "invalid .spec.ha.replicas: 1 must be at least 2"),
+		Entry("with an unknown strategy type", rolloutManagerOpt(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Deployment = &v1alpha1.RolloutManagerDeploymentSpec{Strategy: &appsv1.DeploymentStrategy{Type: "BlueGreen"}}
+		}), "invalid .spec.deployment.strategy: type BlueGreen must be one of RollingUpdate or Recreate"),
+		Entry("with rollingUpdate set for the Recreate strategy", rolloutManagerOpt(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Deployment = &v1alpha1.RolloutManagerDeploymentSpec{Strategy: &appsv1.DeploymentStrategy{
+				Type:          appsv1.RecreateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{},
+			}}
+		}), "invalid .spec.deployment.strategy: rollingUpdate may only be set with the RollingUpdate strategy type"),
+	)
+
+	Context("suspending the RolloutManager", func() {
+
+		var (
+			ctx context.Context
+			rm  *v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+			req ctrl.Request
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			rm = makeTestRolloutManager()
+			r = makeTestReconciler(rm)
+			Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+			os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+			req = ctrl.Request{NamespacedName: types.NamespacedName{Name: rm.Name, Namespace: rm.Namespace}}
+		})
+
+		AfterEach(func() {
+			os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+		})
+
+		It("should generate a Deployment with zero replicas, and the replicas of .spec.ha once resumed", func() {
+			cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true, Replicas: int32Ptr(3)}
+				rm.Spec.Suspend = true
+			})
+
+			deployment, err := generateDesiredRolloutsDeployment(cr, sa)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployment.Spec.Replicas).To(Equal(int32Ptr(0)))
+
+			cr.Spec.Suspend = false
+			deployment, err = generateDesiredRolloutsDeployment(cr, sa)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deployment.Spec.Replicas).To(Equal(int32Ptr(getRolloutsReplicas(cr))))
+		})
+
+		It("should scale the Deployment to zero while the RolloutManager is suspended, and restore its replicas afterwards", func() {
+			reconcileWith := func(update func(rm *v1alpha1.RolloutManager)) {
+				Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+				update(rm)
+				Expect(r.Client.Update(ctx, rm)).To(Succeed())
+
+				_, err := r.Reconcile(ctx, req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+			}
+
+			By("suspending a RolloutManager with HA enabled")
+			reconcileWith(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true, Replicas: int32Ptr(3)}
+				rm.Spec.Suspend = true
+			})
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(0)))
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsMetricsServiceName, &corev1.Service{})).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultRolloutsConfigMapName, &corev1.ConfigMap{})).To(Succeed())
+
+			Expect(rm.Status.Phase).To(Equal(v1alpha1.PhaseSuspended))
+			Expect(rm.Status.RolloutController).To(Equal(v1alpha1.PhaseSuspended))
+			Expect(rm.Status.Replicas).To(Equal(int32(0)))
+			Expect(rm.Status.Leader).To(BeNil())
+			Expect(meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeLeaderLeaseStale)).To(BeNil())
+
+			available := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeAvailable)
+			Expect(available.Status).To(Equal(metav1.ConditionFalse))
+			Expect(available.Reason).To(Equal(v1alpha1.RolloutManagerReasonSuspended))
+
+			controllerHealthy := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeControllerHealthy)
+			Expect(controllerHealthy.Status).To(Equal(metav1.ConditionTrue))
+			Expect(controllerHealthy.Reason).To(Equal(v1alpha1.RolloutManagerReasonSuspended))
+
+			degraded := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeDegraded)
+			Expect(degraded.Status).To(Equal(metav1.ConditionFalse))
+
+			By("resuming the RolloutManager")
+			reconcileWith(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.Suspend = false
+			})
+
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+			Expect(rm.Status.Phase).To(Equal(v1alpha1.PhasePending))
+			Expect(rm.Status.Replicas).To(Equal(int32(3)))
+
+			available = meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeAvailable)
+			Expect(available.Reason).To(Equal(v1alpha1.RolloutManagerReasonMinimumReplicasUnavailable))
+		})
+
+		It("should stop requeueing for the leader Lease, and remove the LeaderLeaseStale condition, while a RolloutManager with HA enabled is suspended", func() {
+			Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true}
+			Expect(r.Client.Update(ctx, rm)).To(Succeed())
+
+			By("reconciling without a leader Lease, which reports it as stale")
+			res, err := r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(leaderStatusRefreshInterval))
+
+			Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+			leaderLeaseStale := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeLeaderLeaseStale)
+			Expect(leaderLeaseStale).ToNot(BeNil())
+			Expect(leaderLeaseStale.Status).To(Equal(metav1.ConditionTrue))
+
+			By("suspending the RolloutManager")
+			rm.Spec.Suspend = true
+			Expect(r.Client.Update(ctx, rm)).To(Succeed())
+
+			res, err = r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeZero())
+
+			Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+			Expect(meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeLeaderLeaseStale)).To(BeNil())
+			Expect(rm.Status.Leader).To(BeNil())
+
+			By("resuming the RolloutManager, which reports the leader Lease again")
+			rm.Spec.Suspend = false
+			Expect(r.Client.Update(ctx, rm)).To(Succeed())
+
+			res, err = r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(leaderStatusRefreshInterval))
+
+			Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+			Expect(meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeLeaderLeaseStale)).ToNot(BeNil())
+		})
+	})
+})
+
+var _ = Describe("getEffectiveControllerSettings tests", func() {
+
+	int32Ptr := func(val int32) *int32 { return &val }
+
+	It("should return the defaults of the Rollouts controller when no tuning arguments are set", func() {
+		Expect(getEffectiveControllerSettings([]string{"--namespaced"})).To(Equal(&v1alpha1.RolloutManagerControllerSpec{
+			RolloutThreads:    int32Ptr(10),
+			ExperimentThreads: int32Ptr(10),
+			AnalysisThreads:   int32Ptr(30),
+			QPS:               int32Ptr(40),
+			Burst:             int32Ptr(80),
+			RolloutResync:     int32Ptr(900),
+			LogLevel:          "info",
+			LogFormat:         "text",
+			KLogLevel:         int32Ptr(0),
+		}))
+	})
+
+	It("should parse both '--flag value' and '--flag=value' forms, and omit values that cannot be parsed", func() {
+		settings := getEffectiveControllerSettings([]string{"--namespaced", "--rollout-threads", "20", "--loglevel=debug", "--qps=12.5", "--burst", "--kloglevel=4"})
+		Expect(settings.RolloutThreads).To(Equal(int32Ptr(20)))
+		Expect(settings.LogLevel).To(Equal("debug"))
+		Expect(settings.QPS).To(BeNil())
+		Expect(settings.Burst).To(BeNil())
+		Expect(settings.KLogLevel).To(Equal(int32Ptr(4)))
+		Expect(settings.AnalysisThreads).To(Equal(int32Ptr(30)))
+	})
+})
+
 func deploymentCR(name string, namespace string, rolloutsSelectorLabel string, volumeNames []string, nodeSelector string, serviceAccount string, rolloutManager v1alpha1.RolloutManager) (*appsv1.Deployment, error) {
 	runAsNonRoot := true
 	deploymentCR := &appsv1.Deployment{
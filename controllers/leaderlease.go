@@ -0,0 +1,89 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// rolloutsLeaderElectionLeaseName is the name of the Lease used by the Rollouts controller pods for leader election, in the namespace of the RolloutManager.
+	rolloutsLeaderElectionLeaseName = "argo-rollouts-controller-lock"
+
+	// leaderStatusRefreshInterval is the interval at which RolloutManagers with HA enabled are requeued, to detect a Lease that is no longer renewed.
+	// The renew time reported in .status.leader is refreshed at most this often, since the Lease is renewed every few seconds.
+	leaderStatusRefreshInterval = time.Minute
+)
+
+// isHAEnabled returns true if HA is enabled for the Rollouts controller.
+func isHAEnabled(cr rolloutsmanagerv1alpha1.RolloutManager) bool {
+	return cr.Spec.HA != nil && cr.Spec.HA.Enabled
+}
+
+// getLeaderStatus returns the .status.leader of the RolloutManager, and its LeaderLeaseStale condition, based on the leader election Lease of the Rollouts controller.
+// The Lease is read on every call, and not cached, as it is renewed every few seconds.
+func (r *RolloutManagerReconciler) getLeaderStatus(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (*rolloutsmanagerv1alpha1.RolloutManagerLeaderStatus, metav1.Condition, error) {
+
+	lease := &coordinationv1.Lease{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, rolloutsLeaderElectionLeaseName, lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, metav1.Condition{}, fmt.Errorf("failed to get the Lease %s: %w", rolloutsLeaderElectionLeaseName, err)
+		}
+		return &rolloutsmanagerv1alpha1.RolloutManagerLeaderStatus{},
+			createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeLeaderLeaseStale, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonNoLeader, fmt.Sprintf("the Lease %s does not exist yet", rolloutsLeaderElectionLeaseName)), nil
+	}
+
+	status := &rolloutsmanagerv1alpha1.RolloutManagerLeaderStatus{
+		LeaseDurationSeconds: lease.Spec.LeaseDurationSeconds,
+		LeaderTransitions:    lease.Spec.LeaseTransitions,
+	}
+	if lease.Spec.HolderIdentity != nil {
+		status.HolderIdentity = *lease.Spec.HolderIdentity
+		// The Rollouts controller uses '<hostname>_<uuid>' as its identity, and the hostname of a pod is its name
+		status.Pod, _, _ = strings.Cut(status.HolderIdentity, "_")
+	}
+	if lease.Spec.RenewTime != nil {
+		status.RenewTime = &metav1.Time{Time: lease.Spec.RenewTime.Time}
+
+		// Avoid updating the status of the RolloutManager every time the Lease is renewed
+		if previous := cr.Status.Leader; previous != nil && previous.RenewTime != nil && previous.HolderIdentity == status.HolderIdentity &&
+			status.RenewTime.Sub(previous.RenewTime.Time) < leaderStatusRefreshInterval && !status.RenewTime.Before(previous.RenewTime) {
+			status.RenewTime = previous.RenewTime
+		}
+	}
+
+	if status.HolderIdentity == "" {
+		return status, createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeLeaderLeaseStale, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonNoLeader, fmt.Sprintf("the Lease %s has no holder", rolloutsLeaderElectionLeaseName)), nil
+	}
+
+	leaseDuration := defaultLeaderElectionLeaseDuration
+	if lease.Spec.LeaseDurationSeconds != nil {
+		leaseDuration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+
+	if lease.Spec.RenewTime == nil || time.Since(lease.Spec.RenewTime.Time) > leaseDuration {
+		return status, createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeLeaderLeaseStale, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonLeaseNotRenewed,
+			fmt.Sprintf("the Lease %s held by %s has not been renewed within its duration of %s", rolloutsLeaderElectionLeaseName, status.Pod, leaseDuration)), nil
+	}
+
+	return status, createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeLeaderLeaseStale, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonLeaseRenewed, ""), nil
+}
+
+// removeConditionsFromSlice returns the conditions, excluding those of the given type, and true if a condition was removed.
+func removeConditionsFromSlice(conditionType string, existingConditions []metav1.Condition) (bool, []metav1.Condition) {
+
+	res := []metav1.Condition{}
+	for _, condition := range existingConditions {
+		if condition.Type != conditionType {
+			res = append(res, condition)
+		}
+	}
+
+	return len(res) != len(existingConditions), res
+}
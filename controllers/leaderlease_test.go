@@ -0,0 +1,141 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("Leader Lease tests", func() {
+
+	var (
+		ctx context.Context
+		rm  *v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	int32Ptr := func(val int32) *int32 { return &val }
+	stringPtr := func(val string) *string { return &val }
+
+	createLease := func(holderIdentity string, renewTime time.Time) *coordinationv1.Lease {
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: rolloutsLeaderElectionLeaseName, Namespace: rm.Namespace},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       stringPtr(holderIdentity),
+				LeaseDurationSeconds: int32Ptr(15),
+				LeaseTransitions:     int32Ptr(3),
+				RenewTime:            &metav1.MicroTime{Time: renewTime},
+			},
+		}
+		Expect(r.Client.Create(ctx, lease)).To(Succeed())
+		return lease
+	}
+
+	findCondition := func(conditions []metav1.Condition) *metav1.Condition {
+		for i := range conditions {
+			if conditions[i].Type == v1alpha1.RolloutManagerConditionTypeLeaderLeaseStale {
+				return &conditions[i]
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rm = makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true}
+		})
+		r = makeTestReconciler(rm)
+		Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+	})
+
+	It("should report the leader pod of a Lease that is renewed", func() {
+		renewTime := time.Now().Add(-5 * time.Second).Truncate(time.Second)
+		createLease("argo-rollouts-5d8f9c7b4-abcde_0f8e1d2c-3b4a-5968-7a6b-8c9d0e1f2a3b", renewTime)
+
+		leader, condition, err := r.getLeaderStatus(ctx, *rm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leader).To(Equal(&v1alpha1.RolloutManagerLeaderStatus{
+			Pod:                  "argo-rollouts-5d8f9c7b4-abcde",
+			HolderIdentity:       "argo-rollouts-5d8f9c7b4-abcde_0f8e1d2c-3b4a-5968-7a6b-8c9d0e1f2a3b",
+			RenewTime:            &metav1.Time{Time: renewTime},
+			LeaseDurationSeconds: int32Ptr(15),
+			LeaderTransitions:    int32Ptr(3),
+		}))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonLeaseRenewed))
+
+		By("keeping the previously reported renew time, if the Lease was renewed recently by the same holder")
+		previousRenewTime := metav1.NewTime(renewTime.Add(-10 * time.Second))
+		rm.Status.Leader = leader.DeepCopy()
+		rm.Status.Leader.RenewTime = &previousRenewTime
+
+		leader, _, err = r.getLeaderStatus(ctx, *rm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leader.RenewTime).To(Equal(&previousRenewTime))
+	})
+
+	It("should report a Lease that was not renewed within its duration as stale", func() {
+		createLease("argo-rollouts-5d8f9c7b4-abcde_0f8e1d2c", time.Now().Add(-time.Minute))
+
+		leader, condition, err := r.getLeaderStatus(ctx, *rm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leader.Pod).To(Equal("argo-rollouts-5d8f9c7b4-abcde"))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonLeaseNotRenewed))
+		Expect(condition.Message).To(Equal("the Lease argo-rollouts-controller-lock held by argo-rollouts-5d8f9c7b4-abcde has not been renewed within its duration of 15s"))
+	})
+
+	It("should report a missing Lease as stale", func() {
+		leader, condition, err := r.getLeaderStatus(ctx, *rm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(leader).To(Equal(&v1alpha1.RolloutManagerLeaderStatus{}))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonNoLeader))
+	})
+
+	Context("reconciling the RolloutManager", func() {
+
+		BeforeEach(func() {
+			os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+		})
+
+		It("should set the leader status and condition while HA is enabled, requeue, and remove them once HA is disabled", func() {
+			createLease("argo-rollouts-5d8f9c7b4-abcde_0f8e1d2c", time.Now())
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: rm.Name, Namespace: rm.Namespace}}
+			res, err := r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(leaderStatusRefreshInterval))
+
+			Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+			Expect(rm.Status.Leader).ToNot(BeNil())
+			Expect(rm.Status.Leader.Pod).To(Equal("argo-rollouts-5d8f9c7b4-abcde"))
+			Expect(findCondition(rm.Status.Conditions)).ToNot(BeNil())
+			Expect(findCondition(rm.Status.Conditions).Status).To(Equal(metav1.ConditionFalse))
+
+			rm.Spec.HA.Enabled = false
+			Expect(r.Client.Update(ctx, rm)).To(Succeed())
+
+			res, err = r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(BeZero())
+
+			Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+			Expect(rm.Status.Leader).To(BeNil())
+			Expect(findCondition(rm.Status.Conditions)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,524 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// The following types mirror the notification configuration format of Argo Rollouts (notifications-engine), and are used to render .spec.notifications of RolloutManager into the notification ConfigMap.
+// See https://argo-rollouts.readthedocs.io/en/stable/features/notifications/
+
+type slackServiceConfig struct {
+	Token    string `yaml:"token"`
+	Username string `yaml:"username,omitempty"`
+	Icon     string `yaml:"icon,omitempty"`
+	APIURL   string `yaml:"apiURL,omitempty"`
+}
+
+type webhookServiceConfig struct {
+	URL                string                  `yaml:"url"`
+	Headers            []webhookHeaderConfig   `yaml:"headers,omitempty"`
+	BasicAuth          *webhookBasicAuthConfig `yaml:"basicAuth,omitempty"`
+	InsecureSkipVerify bool                    `yaml:"insecureSkipVerify,omitempty"`
+}
+
+type webhookHeaderConfig struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type webhookBasicAuthConfig struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+type emailServiceConfig struct {
+	Host               string `yaml:"host"`
+	Port               int32  `yaml:"port"`
+	From               string `yaml:"from"`
+	Username           string `yaml:"username,omitempty"`
+	Password           string `yaml:"password,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
+}
+
+type notificationTemplateConfig struct {
+	Message string                           `yaml:"message,omitempty"`
+	Email   *emailTemplateConfig             `yaml:"email,omitempty"`
+	Slack   *slackTemplateConfig             `yaml:"slack,omitempty"`
+	Webhook map[string]webhookTemplateConfig `yaml:"webhook,omitempty"`
+}
+
+type emailTemplateConfig struct {
+	Subject string `yaml:"subject"`
+}
+
+type slackTemplateConfig struct {
+	Attachments string `yaml:"attachments,omitempty"`
+	Blocks      string `yaml:"blocks,omitempty"`
+}
+
+type webhookTemplateConfig struct {
+	Method string `yaml:"method,omitempty"`
+	Path   string `yaml:"path,omitempty"`
+	Body   string `yaml:"body,omitempty"`
+}
+
+type notificationTriggerConditionConfig struct {
+	Description string   `yaml:"description,omitempty"`
+	OncePer     string   `yaml:"oncePer,omitempty"`
+	When        string   `yaml:"when"`
+	Send        []string `yaml:"send"`
+}
+
+type notificationSubscriptionConfig struct {
+	Recipients []string `yaml:"recipients"`
+	Triggers   []string `yaml:"triggers,omitempty"`
+	Selector   string   `yaml:"selector,omitempty"`
+}
+
+// reconcileRolloutsNotifications renders .spec.notifications of the RolloutManager into the notification ConfigMap. The referenced secret values are applied with the notification Secret: see getNotificationSecretValues.
+// The Rollouts controller is restarted when the notification ConfigMap or Secret changes, via the config hash of its Deployment: see getRolloutsConfigHash.
+func (r *RolloutManagerReconciler) reconcileRolloutsNotifications(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	if err := validateNotifications(cr); err != nil {
+		return err
+	}
+
+	desiredData, _, err := renderNotificationsConfig(cr.Spec.Notifications)
+	if err != nil {
+		return err
+	}
+
+	return r.reconcileRolloutsNotificationConfigMap(ctx, cr, desiredData)
+}
+
+// reconcileRolloutsNotificationConfigMap creates/updates the notification ConfigMap with the given data, or deletes it (if it is owned by the RolloutManager) when .spec.notifications is not set.
+func (r *RolloutManagerReconciler) reconcileRolloutsNotificationConfigMap(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, desiredData map[string]string) error {
+
+	expectedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultRolloutsNotificationConfigMapName,
+			Namespace: cr.Namespace,
+		},
+		Data: desiredData,
+	}
+
+	setRolloutsLabelsAndAnnotationsToObject(&expectedConfigMap.ObjectMeta, cr)
+	if r.ResourceLabels != nil {
+		setCustomLabels(&expectedConfigMap.ObjectMeta, r.ResourceLabels)
+	}
+
+	liveConfigMap := &corev1.ConfigMap{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, expectedConfigMap.Name, liveConfigMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the ConfigMap %s: %w", expectedConfigMap.Name, err)
+		}
+
+		if cr.Spec.Notifications == nil {
+			// The ConfigMap is not managed by the operator, and doesn't exist, so nothing to do
+			return nil
+		}
+
+		if err := controllerutil.SetControllerReference(&cr, expectedConfigMap, r.Scheme); err != nil {
+			return err
+		}
+
+		log.Info(fmt.Sprintf("Creating ConfigMap %s", expectedConfigMap.Name))
+		return r.applyObject(ctx, expectedConfigMap)
+	}
+
+	ownedByController := metav1.IsControlledBy(liveConfigMap, &cr)
+
+	if cr.Spec.Notifications == nil {
+
+		// If the controller created/owns the ConfigMap, delete it
+		if ownedByController {
+			log.Info(fmt.Sprintf(".spec.notifications has been removed, deleting ConfigMap %s", liveConfigMap.Name))
+			return r.Client.Delete(ctx, liveConfigMap)
+		}
+
+		// Otherwise, the ConfigMap exists, but the controller didn't create it, so just return (don't touch it)
+		return nil
+	}
+
+	// A ConfigMap created by a user is not taken over by the controller, but its data is applied
+	if ownedByController {
+		if err := controllerutil.SetControllerReference(&cr, expectedConfigMap, r.Scheme); err != nil {
+			return err
+		}
+	}
+
+	return r.applyObject(ctx, expectedConfigMap)
+}
+
+// getNotificationSecretValues returns the values of the user-owned Secrets referenced by .spec.notifications, by key of the notification Secret.
+// The values are applied with the notification Secret (see reconcileRolloutsSecrets), so the keys that were previously copied by the operator, but are no longer referenced, are removed,
+// while the other keys of the notification Secret are left untouched.
+func (r *RolloutManagerReconciler) getNotificationSecretValues(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (map[string][]byte, error) {
+
+	if err := validateNotifications(cr); err != nil {
+		return nil, err
+	}
+
+	_, secretRefs, err := renderNotificationsConfig(cr.Spec.Notifications)
+	if err != nil {
+		return nil, err
+	}
+
+	var res map[string][]byte
+	for key, ref := range secretRefs {
+		value, err := getNotificationSecretValue(ctx, r.Client, cr.Namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			if res == nil {
+				res = map[string][]byte{}
+			}
+			res[key] = value
+		}
+	}
+
+	return res, nil
+}
+
+// getNotificationSecretValue returns the value of the given key of a user-owned Secret. If the Secret (or key) does not exist, and the reference is optional, nil is returned.
+func getNotificationSecretValue(ctx context.Context, k8sClient client.Client, namespace string, ref corev1.SecretKeySelector) ([]byte, error) {
+
+	optional := ref.Optional != nil && *ref.Optional
+
+	secret := &corev1.Secret{}
+	if err := fetchObject(ctx, k8sClient, namespace, ref.Name, secret); err != nil {
+		if apierrors.IsNotFound(err) && optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to retrieve Secret %s referenced by .spec.notifications: %w", ref.Name, err)
+	}
+
+	value, exists := secret.Data[ref.Key]
+	if !exists {
+		if optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("key %s does not exist in Secret %s referenced by .spec.notifications", ref.Key, ref.Name)
+	}
+
+	return value, nil
+}
+
+// validateNotifications returns an error if .spec.notifications of the RolloutManager is invalid.
+func validateNotifications(cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	_, secretRefs, err := renderNotificationsConfig(cr.Spec.Notifications)
+	if err != nil {
+		return err
+	}
+
+	if len(secretRefs) > 0 && cr.Spec.SkipNotificationSecretDeployment {
+		return fmt.Errorf(".spec.notifications references Secret values, which cannot be used when .spec.skipNotificationSecretDeployment is true")
+	}
+
+	return nil
+}
+
+// renderNotificationsConfig renders the given notifications spec into the data of the notification ConfigMap.
+// It also returns the secret values that are referenced by the rendered data, as a map from the key of the notification Secret to the user-owned Secret key that contains its value.
+func renderNotificationsConfig(spec *rolloutsmanagerv1alpha1.RolloutManagerNotificationsSpec) (map[string]string, map[string]corev1.SecretKeySelector, error) {
+
+	data := map[string]string{}
+	secretRefs := map[string]corev1.SecretKeySelector{}
+
+	if spec == nil {
+		return data, secretRefs, nil
+	}
+
+	// addSecretRef records that the notification Secret key 'key' should contain the value of 'ref', and returns the reference to use in the rendered configuration
+	addSecretRef := func(key string, ref corev1.SecretKeySelector) (string, error) {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return "", fmt.Errorf("invalid notification secret key '%s': %s", key, strings.Join(errs, ", "))
+		}
+		if _, exists := secretRefs[key]; exists {
+			return "", fmt.Errorf("notification secret key '%s' is referenced more than once", key)
+		}
+		secretRefs[key] = ref
+		return "$" + key, nil
+	}
+
+	for _, service := range spec.Services {
+
+		serviceType, config, err := renderNotificationService(service, addSecretRef)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := "service." + serviceType
+		if service.Name != "" && service.Name != serviceType {
+			key += "." + service.Name
+		}
+
+		if _, exists := data[key]; exists {
+			return nil, nil, fmt.Errorf("notification service '%s' is defined more than once", key)
+		}
+		data[key] = config
+	}
+
+	for _, template := range spec.Templates {
+
+		key := "template." + template.Name
+		if _, exists := data[key]; exists {
+			return nil, nil, fmt.Errorf("notification template '%s' is defined more than once", template.Name)
+		}
+
+		templateConfig := notificationTemplateConfig{Message: template.Message}
+		if template.Email != nil {
+			templateConfig.Email = &emailTemplateConfig{Subject: template.Email.Subject}
+		}
+		if template.Slack != nil {
+			templateConfig.Slack = &slackTemplateConfig{Attachments: template.Slack.Attachments, Blocks: template.Slack.Blocks}
+		}
+		if len(template.Webhook) > 0 {
+			templateConfig.Webhook = map[string]webhookTemplateConfig{}
+			for name, webhook := range template.Webhook {
+				templateConfig.Webhook[name] = webhookTemplateConfig{Method: webhook.Method, Path: webhook.Path, Body: webhook.Body}
+			}
+		}
+
+		if err := marshalNotificationConfig(data, key, templateConfig); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, trigger := range spec.Triggers {
+
+		key := "trigger." + trigger.Name
+		if _, exists := data[key]; exists {
+			return nil, nil, fmt.Errorf("notification trigger '%s' is defined more than once", trigger.Name)
+		}
+
+		conditions := []notificationTriggerConditionConfig{}
+		for _, condition := range trigger.Conditions {
+			conditions = append(conditions, notificationTriggerConditionConfig{
+				Description: condition.Description,
+				OncePer:     condition.OncePer,
+				When:        condition.When,
+				Send:        condition.Send,
+			})
+		}
+
+		if err := marshalNotificationConfig(data, key, conditions); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(spec.DefaultTriggers) > 0 {
+		if err := marshalNotificationConfig(data, "defaultTriggers", spec.DefaultTriggers); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(spec.Subscriptions) > 0 {
+		subscriptions := []notificationSubscriptionConfig{}
+		for _, subscription := range spec.Subscriptions {
+			subscriptions = append(subscriptions, notificationSubscriptionConfig{
+				Recipients: subscription.Recipients,
+				Triggers:   subscription.Triggers,
+				Selector:   subscription.Selector,
+			})
+		}
+		if err := marshalNotificationConfig(data, "subscriptions", subscriptions); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(spec.Context) > 0 {
+		if err := marshalNotificationConfig(data, "context", spec.Context); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return data, secretRefs, nil
+}
+
+// renderNotificationService returns the type and the rendered configuration of the given notification service.
+func renderNotificationService(service rolloutsmanagerv1alpha1.NotificationService, addSecretRef func(string, corev1.SecretKeySelector) (string, error)) (string, string, error) {
+
+	typesSet := 0
+	for _, set := range []bool{service.Slack != nil, service.Webhook != nil, service.Email != nil, service.Custom != nil} {
+		if set {
+			typesSet++
+		}
+	}
+	if typesSet != 1 {
+		return "", "", fmt.Errorf("notification service '%s' must specify exactly one of slack, webhook, email or custom", service.Name)
+	}
+
+	var serviceType string
+	var config interface{}
+	var err error
+
+	// serviceName returns the name of this service, which defaults to its type
+	serviceName := func() string {
+		if service.Name == "" {
+			return serviceType
+		}
+		return service.Name
+	}
+
+	// secretKey returns the key of the notification Secret that is used for the given field of this service
+	secretKey := func(field string) string {
+		return serviceName() + "-" + field
+	}
+
+	switch {
+	case service.Slack != nil:
+		serviceType = "slack"
+		slack := slackServiceConfig{
+			Username: service.Slack.Username,
+			Icon:     service.Slack.Icon,
+			APIURL:   service.Slack.APIURL,
+		}
+		if slack.Token, err = addSecretRef(secretKey("token"), service.Slack.TokenSecretRef); err != nil {
+			return "", "", err
+		}
+		config = slack
+
+	case service.Webhook != nil:
+		serviceType = "webhook"
+		webhook := webhookServiceConfig{
+			URL:                service.Webhook.URL,
+			InsecureSkipVerify: service.Webhook.InsecureSkipVerify,
+		}
+		for _, header := range service.Webhook.Headers {
+			value := header.Value
+			if (header.Value == "") == (header.ValueFrom == nil) {
+				return "", "", fmt.Errorf("header '%s' of notification service '%s' must specify exactly one of value or valueFrom", header.Name, serviceName())
+			}
+			if header.ValueFrom != nil {
+				if value, err = addSecretRef(secretKey("header-"+strings.ToLower(header.Name)), *header.ValueFrom); err != nil {
+					return "", "", err
+				}
+			}
+			webhook.Headers = append(webhook.Headers, webhookHeaderConfig{Name: header.Name, Value: value})
+		}
+		if service.Webhook.BasicAuth != nil {
+			webhook.BasicAuth = &webhookBasicAuthConfig{Username: service.Webhook.BasicAuth.Username}
+			if webhook.BasicAuth.Password, err = addSecretRef(secretKey("password"), service.Webhook.BasicAuth.PasswordSecretRef); err != nil {
+				return "", "", err
+			}
+		}
+		config = webhook
+
+	case service.Email != nil:
+		serviceType = "email"
+		email := emailServiceConfig{
+			Host:               service.Email.Host,
+			Port:               service.Email.Port,
+			From:               service.Email.From,
+			Username:           service.Email.Username,
+			InsecureSkipVerify: service.Email.InsecureSkipVerify,
+		}
+		if service.Email.PasswordSecretRef != nil {
+			if email.Password, err = addSecretRef(secretKey("password"), *service.Email.PasswordSecretRef); err != nil {
+				return "", "", err
+			}
+		}
+		config = email
+
+	case service.Custom != nil:
+		serviceType = service.Custom.Type
+		if serviceType == "" {
+			return "", "", fmt.Errorf("notification service '%s' must specify the type of the custom service", service.Name)
+		}
+
+		// Sort the keys, so that errors are reported deterministically
+		keys := make([]string, 0, len(service.Custom.SecretRefs))
+		for key := range service.Custom.SecretRefs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if _, err := addSecretRef(key, service.Custom.SecretRefs[key]); err != nil {
+				return "", "", err
+			}
+		}
+
+		// The raw configuration is used as-is, but must be valid YAML
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(service.Custom.Config), &parsed); err != nil {
+			return "", "", fmt.Errorf("invalid config of notification service '%s': %w", serviceType, err)
+		}
+		return serviceType, service.Custom.Config, nil
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return "", "", fmt.Errorf("error marshalling notification service '%s' to string: %w", serviceType, err)
+	}
+
+	return serviceType, string(out), nil
+}
+
+// marshalNotificationConfig marshals the given value to YAML, and stores it in data under the given key.
+func marshalNotificationConfig(data map[string]string, key string, value interface{}) error {
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error marshalling notification configuration '%s' to string: %w", key, err)
+	}
+	data[key] = string(out)
+	return nil
+}
+
+// notificationSecretNames returns the names of the user-owned Secrets that are referenced by .spec.notifications of the RolloutManager.
+func notificationSecretNames(cr rolloutsmanagerv1alpha1.RolloutManager) []string {
+
+	// The spec may be invalid, in which case no Secrets are returned
+	_, secretRefs, err := renderNotificationsConfig(cr.Spec.Notifications)
+	if err != nil {
+		return nil
+	}
+
+	var res []string
+	for _, ref := range secretRefs {
+		res = append(res, ref.Name)
+	}
+	return res
+}
+
+// enqueueRolloutManagersReferencingSecret returns a reconcile request for each RolloutManager, in the namespace of the given Secret, whose .spec.notifications references the Secret,
+// or whose Rollouts controller mounts the Secret via .spec.volumes, in which case the Secret is part of its config hash.
+func (r *RolloutManagerReconciler) enqueueRolloutManagersReferencingSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+
+	var rolloutManagerList rolloutsmanagerv1alpha1.RolloutManagerList
+
+	if err := r.Client.List(ctx, &rolloutManagerList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Unable to list RolloutManagers in enqueueRolloutManagersReferencingSecret")
+		return []reconcile.Request{}
+	}
+
+	var res []reconcile.Request
+
+	for idx := range rolloutManagerList.Items {
+		rm := rolloutManagerList.Items[idx]
+		_, configSecretNames := getRolloutsConfigInputNames(rm)
+		for _, name := range append(notificationSecretNames(rm), configSecretNames...) {
+			if name == obj.GetName() {
+				res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+				break
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,481 @@
+package rollouts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/client-go/applyconfigurations"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v6/typed"
+)
+
+const (
+	// maxPlannedChangeFields is the maximum number of fields listed in a planned change.
+	maxPlannedChangeFields = 20
+)
+
+// isObserveModeEnabled returns true if the operator should only report the changes that it would apply to the resources of the RolloutManager.
+func isObserveModeEnabled(cr rolloutsmanagerv1alpha1.RolloutManager) bool {
+	return cr.Spec.ReconcileMode == rolloutsmanagerv1alpha1.ReconcileModeObserve
+}
+
+// reconcilePlan collects the changes that would be applied to the resources of a RolloutManager, while it is in Observe mode.
+type reconcilePlan struct {
+	changes []rolloutsmanagerv1alpha1.RolloutManagerPlannedChange
+}
+
+// record adds a change to the plan. Changes with the same action on the same resource are combined.
+func (p *reconcilePlan) record(change rolloutsmanagerv1alpha1.RolloutManagerPlannedChange) {
+
+	for i, existing := range p.changes {
+		if existing.Action == change.Action && existing.Kind == change.Kind && existing.Namespace == change.Namespace && existing.Name == change.Name {
+			p.changes[i].Fields = combineFieldPaths(existing.Fields, change.Fields)
+			return
+		}
+	}
+
+	change.Fields = combineFieldPaths(nil, change.Fields)
+	p.changes = append(p.changes, change)
+}
+
+// getPlannedChanges returns the changes of the plan, in the order in which they would be applied. The number of fields of each change is limited to maxPlannedChangeFields.
+func (p *reconcilePlan) getPlannedChanges() []rolloutsmanagerv1alpha1.RolloutManagerPlannedChange {
+
+	changes := []rolloutsmanagerv1alpha1.RolloutManagerPlannedChange{}
+	for _, change := range p.changes {
+		if len(change.Fields) > maxPlannedChangeFields {
+			change.Fields = append(change.Fields[:maxPlannedChangeFields:maxPlannedChangeFields], fmt.Sprintf("and %d more", len(change.Fields)-maxPlannedChangeFields))
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// getDriftDetectedCondition returns the DriftDetected condition of the RolloutManager, based on the changes of the plan.
+func (p *reconcilePlan) getDriftDetectedCondition() metav1.Condition {
+
+	if len(p.changes) == 0 {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeDriftDetected, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonAsExpected, "")
+	}
+
+	counts := map[rolloutsmanagerv1alpha1.RolloutManagerPlannedChangeAction]int{}
+	for _, change := range p.changes {
+		counts[change.Action]++
+	}
+
+	return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeDriftDetected, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonResourcesOutOfSync,
+		fmt.Sprintf("%d resources would be created, %d updated and %d deleted: see .status.plannedChanges", counts[rolloutsmanagerv1alpha1.PlannedChangeActionCreate],
+			counts[rolloutsmanagerv1alpha1.PlannedChangeActionUpdate], counts[rolloutsmanagerv1alpha1.PlannedChangeActionDelete]))
+}
+
+// observeRolloutsManager reconciles the RolloutManager in Observe mode: the desired state of every resource is computed as usual, but the
+// writes to the resources are only sent to the API server as dry-run requests, and the changes they would make are reported in .status.plannedChanges.
+func (r *RolloutManagerReconciler) observeRolloutsManager(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (reconcileStatusResult, error) {
+
+	plan := &reconcilePlan{}
+
+	observer := &RolloutManagerReconciler{
+		Client:                                newObservingClient(r.Client, r.Scheme, plan),
+		Scheme:                                r.Scheme,
+		OpenShiftRoutePluginLocation:          r.OpenShiftRoutePluginLocation,
+		ResourceLabels:                        r.ResourceLabels,
+		NamespaceScopedArgoRolloutsController: r.NamespaceScopedArgoRolloutsController,
+		PluginCatalogConfigMap:                r.PluginCatalogConfigMap,
+		pluginVerifier:                        r.getPluginVerifier(),
+	}
+
+	rr, err := observer.reconcileRolloutsManager(ctx, cr)
+	if err != nil {
+		return rr, err
+	}
+
+	// Fields modified by other field managers are not restored in Observe mode, so the FieldManagerConflict condition is left as is
+	conditions := []metav1.Condition{}
+	for _, condition := range rr.conditions {
+		if condition.Type != rolloutsmanagerv1alpha1.RolloutManagerConditionTypeFieldManagerConflict {
+			conditions = append(conditions, condition)
+		}
+	}
+	rr.conditions = append(conditions, plan.getDriftDetectedCondition())
+	rr.plannedChanges = plan.getPlannedChanges()
+
+	return rr, nil
+}
+
+// observingClient is a client which sends every write as a dry-run request, and records the changes that the write would make in a reconcilePlan.
+type observingClient struct {
+	client.Client
+
+	scheme        *runtime.Scheme
+	typeConverter managedfields.TypeConverter
+	plan          *reconcilePlan
+
+	// deleted contains the resources that were deleted earlier in the plan. As the deletion is not applied, writes to these resources are recorded
+	// as a creation, without being sent to the API server (for example, when a Deployment is recreated because its selector changed).
+	deleted map[string]bool
+}
+
+func newObservingClient(c client.Client, scheme *runtime.Scheme, plan *reconcilePlan) *observingClient {
+	return &observingClient{
+		Client:        client.NewDryRunClient(c),
+		scheme:        scheme,
+		typeConverter: applyconfigurations.NewTypeConverter(scheme),
+		plan:          plan,
+		deleted:       map[string]bool{},
+	}
+}
+
+func (c *observingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+
+	if !c.deleted[getPlannedChangeKey(gvk, obj)] {
+		if err := c.Client.Create(ctx, obj, opts...); err != nil {
+			return err
+		}
+	}
+
+	c.plan.record(newPlannedChange(rolloutsmanagerv1alpha1.PlannedChangeActionCreate, gvk, obj, nil))
+	return nil
+}
+
+func (c *observingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+
+	gvk, live, err := c.getLiveObject(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if live == nil {
+		// Let the API server report that the resource does not exist
+		return c.Client.Update(ctx, obj, opts...)
+	}
+
+	fields, err := c.getUpdatedFields(gvk, live, obj)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+
+	if len(fields) > 0 {
+		c.plan.record(newPlannedChange(rolloutsmanagerv1alpha1.PlannedChangeActionUpdate, gvk, obj, fields))
+	}
+	return nil
+}
+
+func (c *observingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+
+	if c.deleted[getPlannedChangeKey(gvk, obj)] {
+		c.plan.record(newPlannedChange(rolloutsmanagerv1alpha1.PlannedChangeActionCreate, gvk, obj, nil))
+		return nil
+	}
+
+	if err := c.Client.Patch(ctx, obj, patch, opts...); err != nil {
+		return err
+	}
+
+	c.plan.record(newPlannedChange(rolloutsmanagerv1alpha1.PlannedChangeActionUpdate, gvk, obj, nil))
+	return nil
+}
+
+func (c *observingClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+
+	content, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(content); err != nil {
+		return err
+	}
+	gvk := u.GroupVersionKind()
+
+	if c.deleted[getPlannedChangeKey(gvk, u)] {
+		c.plan.record(newPlannedChange(rolloutsmanagerv1alpha1.PlannedChangeActionCreate, gvk, u, nil))
+		return nil
+	}
+
+	_, live, err := c.getLiveObject(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	var fields []string
+	if live != nil {
+		if fields, err = c.getAppliedFields(gvk, live, u); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Client.Apply(ctx, obj, opts...); err != nil {
+		return err
+	}
+
+	if live == nil {
+		c.plan.record(newPlannedChange(rolloutsmanagerv1alpha1.PlannedChangeActionCreate, gvk, u, nil))
+	} else if len(fields) > 0 {
+		c.plan.record(newPlannedChange(rolloutsmanagerv1alpha1.PlannedChangeActionUpdate, gvk, u, fields))
+	}
+	return nil
+}
+
+func (c *observingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Client.Delete(ctx, obj, opts...); err != nil {
+		return err
+	}
+
+	c.deleted[getPlannedChangeKey(gvk, obj)] = true
+	c.plan.record(newPlannedChange(rolloutsmanagerv1alpha1.PlannedChangeActionDelete, gvk, obj, nil))
+	return nil
+}
+
+// getLiveObject returns the GroupVersionKind of obj, and the live state of the resource, or nil if it does not exist.
+func (c *observingClient) getLiveObject(ctx context.Context, obj client.Object) (schema.GroupVersionKind, client.Object, error) {
+
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return gvk, nil, err
+	}
+
+	var live client.Object
+	if _, isUnstructured := obj.(*unstructured.Unstructured); isUnstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		live = u
+	} else {
+		newObj, err := c.scheme.New(gvk)
+		if err != nil {
+			return gvk, nil, err
+		}
+		live = newObj.(client.Object)
+	}
+
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return gvk, nil, nil
+		}
+		return gvk, nil, err
+	}
+
+	return gvk, live, nil
+}
+
+// getUpdatedFields returns the fields of the live resource that would be added, modified or removed by replacing it with obj.
+func (c *observingClient) getUpdatedFields(gvk schema.GroupVersionKind, live client.Object, obj client.Object) ([]string, error) {
+
+	typedLive, typedObj, err := c.toTypedValues(gvk, live, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison, err := typedLive.Compare(typedObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+
+	return getComparisonFieldPaths(comparison), nil
+}
+
+// getAppliedFields returns the fields of the live resource that would be added, modified or removed by server-side applying obj as the FieldManager of the operator:
+// the fields set in obj are merged into the live resource, and the fields that are only owned by the operator, but are no longer set in obj, are removed.
+func (c *observingClient) getAppliedFields(gvk schema.GroupVersionKind, live client.Object, obj client.Object) ([]string, error) {
+
+	typedLive, typedObj, err := c.toTypedValues(gvk, live, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := typedLive.Merge(typedObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+
+	appliedFields, err := typedObj.ToFieldSet()
+	if err != nil {
+		return nil, err
+	}
+
+	ownedFields, otherFields, err := getManagedFieldSets(live.GetManagedFields())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the managed fields of %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+	merged = merged.RemoveItems(ownedFields.Difference(appliedFields).Difference(otherFields))
+
+	comparison, err := typedLive.Compare(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+
+	return getComparisonFieldPaths(comparison), nil
+}
+
+// toTypedValues converts the live state and the desired state of a resource to typed values of the same type. Resources whose schema is not known
+// to the operator (for example, custom resources) are converted using a deduced schema, in which every list is atomic.
+func (c *observingClient) toTypedValues(gvk schema.GroupVersionKind, live client.Object, obj client.Object) (*typed.TypedValue, *typed.TypedValue, error) {
+
+	unstructuredLive, err := toUnstructuredForComparison(gvk, live)
+	if err != nil {
+		return nil, nil, err
+	}
+	unstructuredObj, err := toUnstructuredForComparison(gvk, obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typedLive, liveErr := c.typeConverter.ObjectToTyped(unstructuredLive, typed.AllowDuplicates)
+	typedObj, objErr := c.typeConverter.ObjectToTyped(unstructuredObj, typed.AllowDuplicates)
+	if liveErr == nil && objErr == nil {
+		return typedLive, typedObj, nil
+	}
+
+	deducedTypeConverter := managedfields.NewDeducedTypeConverter()
+	if typedLive, err = deducedTypeConverter.ObjectToTyped(unstructuredLive); err != nil {
+		return nil, nil, err
+	}
+	if typedObj, err = deducedTypeConverter.ObjectToTyped(unstructuredObj); err != nil {
+		return nil, nil, err
+	}
+	return typedLive, typedObj, nil
+}
+
+// toUnstructuredForComparison converts obj to an unstructured object, without the fields that are set by the API server, and without null values,
+// which the API server does not persist.
+func toUnstructuredForComparison(gvk schema.GroupVersionKind, obj client.Object) (*unstructured.Unstructured, error) {
+
+	// The content of an unstructured object is not copied by the converter, and is modified below
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: removeNullValues(content).(map[string]interface{})}
+	u.SetGroupVersionKind(gvk)
+	for _, field := range [][]string{{"status"}, {"metadata", "managedFields"}, {"metadata", "resourceVersion"}, {"metadata", "generation"}, {"metadata", "uid"}, {"metadata", "creationTimestamp"}} {
+		unstructured.RemoveNestedField(u.Object, field...)
+	}
+	return u, nil
+}
+
+// removeNullValues returns value, without the null values of its maps.
+func removeNullValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if item == nil {
+				delete(v, key)
+			} else {
+				v[key] = removeNullValues(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = removeNullValues(item)
+		}
+	}
+	return value
+}
+
+// getManagedFieldSets returns the fields of a resource that are applied by the FieldManager of the operator, and the fields that are managed by other field managers.
+func getManagedFieldSets(managedFields []metav1.ManagedFieldsEntry) (*fieldpath.Set, *fieldpath.Set, error) {
+
+	ownedFields, otherFields := &fieldpath.Set{}, &fieldpath.Set{}
+
+	for _, entry := range managedFields {
+		if entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, nil, err
+		}
+
+		if entry.Manager == FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			ownedFields = ownedFields.Union(fields)
+		} else {
+			otherFields = otherFields.Union(fields)
+		}
+	}
+
+	return ownedFields, otherFields, nil
+}
+
+// getComparisonFieldPaths returns the outermost paths of the fields that differ in the comparison, sorted.
+func getComparisonFieldPaths(comparison *typed.Comparison) []string {
+
+	paths := []string{}
+	comparison.Added.Union(comparison.Modified).Union(comparison.Removed).Iterate(func(path fieldpath.Path) {
+		paths = append(paths, path.String())
+	})
+
+	return combineFieldPaths(nil, paths)
+}
+
+// combineFieldPaths returns the union of the given field paths, sorted, without the paths that are nested in another path.
+func combineFieldPaths(one []string, two []string) []string {
+
+	paths := append(append([]string{}, one...), two...)
+	sort.Strings(paths)
+
+	res := []string{}
+	for _, path := range paths {
+		nested := false
+		for _, parent := range res {
+			if path == parent || strings.HasPrefix(path, parent+".") || strings.HasPrefix(path, parent+"[") {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			res = append(res, path)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+func newPlannedChange(action rolloutsmanagerv1alpha1.RolloutManagerPlannedChangeAction, gvk schema.GroupVersionKind, obj client.Object, fields []string) rolloutsmanagerv1alpha1.RolloutManagerPlannedChange {
+	return rolloutsmanagerv1alpha1.RolloutManagerPlannedChange{
+		Action:    action,
+		Kind:      gvk.Kind,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Fields:    fields,
+	}
+}
+
+func getPlannedChangeKey(gvk schema.GroupVersionKind, obj client.Object) string {
+	return gvk.String() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
@@ -0,0 +1,167 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("Observe mode tests", func() {
+
+	var (
+		ctx context.Context
+		rm  *v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+		req ctrl.Request
+	)
+
+	int32Ptr := func(val int32) *int32 { return &val }
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rm = makeTestRolloutManager()
+		r = makeTestReconciler(rm)
+		Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+		os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Name: rm.Name, Namespace: rm.Namespace}}
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+	})
+
+	reconcileWith := func(update func(rm *v1alpha1.RolloutManager)) {
+		Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+		update(rm)
+		Expect(r.Client.Update(ctx, rm)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+	}
+
+	It("should report the resources that would be created, without creating them", func() {
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.ReconcileMode = v1alpha1.ReconcileModeObserve
+		})
+
+		Expect(rm.Status.PlannedChanges).To(ContainElements(
+			v1alpha1.RolloutManagerPlannedChange{Action: v1alpha1.PlannedChangeActionCreate, Kind: "ServiceAccount", Name: DefaultArgoRolloutsResourceName, Namespace: rm.Namespace},
+			v1alpha1.RolloutManagerPlannedChange{Action: v1alpha1.PlannedChangeActionCreate, Kind: "ClusterRole", Name: DefaultArgoRolloutsResourceName},
+			v1alpha1.RolloutManagerPlannedChange{Action: v1alpha1.PlannedChangeActionCreate, Kind: "Deployment", Name: DefaultArgoRolloutsResourceName, Namespace: rm.Namespace},
+		))
+
+		condition := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeDriftDetected)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonResourcesOutOfSync))
+		Expect(condition.Message).To(Equal(fmt.Sprintf("%d resources would be created, 0 updated and 0 deleted: see .status.plannedChanges", len(rm.Status.PlannedChanges))))
+
+		err := fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &appsv1.Deployment{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should report the fields that would be updated, and the resources that would be deleted, without modifying them", func() {
+		By("reconciling the RolloutManager with HA and a revision history limit")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true}
+			rm.Spec.Deployment = &v1alpha1.RolloutManagerDeploymentSpec{RevisionHistoryLimit: int32Ptr(3)}
+		})
+		Expect(rm.Status.PlannedChanges).To(BeEmpty())
+		Expect(meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeDriftDetected)).To(BeNil())
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		resourceVersion := deployment.ResourceVersion
+
+		By("switching to Observe mode, which should not report any change")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.ReconcileMode = v1alpha1.ReconcileModeObserve
+		})
+		Expect(rm.Status.PlannedChanges).To(BeEmpty())
+		condition := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeDriftDetected)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+		By("changing the version, and disabling HA and the revision history limit")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Version = "v1.8.0"
+			rm.Spec.HA = nil
+			rm.Spec.Deployment = nil
+		})
+
+		var deploymentChange v1alpha1.RolloutManagerPlannedChange
+		for _, change := range rm.Status.PlannedChanges {
+			if change.Kind == "Deployment" {
+				deploymentChange = change
+			}
+		}
+		Expect(deploymentChange.Action).To(Equal(v1alpha1.PlannedChangeActionUpdate))
+		Expect(deploymentChange.Fields).To(ContainElements(
+			".spec.replicas",
+			".spec.revisionHistoryLimit",
+			`.spec.template.spec.containers[name="argo-rollouts"].image`,
+		))
+		Expect(rm.Status.PlannedChanges).To(ContainElement(v1alpha1.RolloutManagerPlannedChange{
+			Action: v1alpha1.PlannedChangeActionDelete, Kind: "PodDisruptionBudget", Name: DefaultArgoRolloutsResourceName, Namespace: rm.Namespace,
+		}))
+
+		condition = meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeDriftDetected)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+
+		By("verifying that the resources were not modified")
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.ResourceVersion).To(Equal(resourceVersion))
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &policyv1.PodDisruptionBudget{})).To(Succeed())
+
+		By("switching back to Apply mode, which should apply the changes and clear the planned changes")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.ReconcileMode = v1alpha1.ReconcileModeApply
+		})
+		Expect(rm.Status.PlannedChanges).To(BeEmpty())
+		Expect(meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeDriftDetected)).To(BeNil())
+
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(HaveSuffix(":v1.8.0"))
+		Expect(deployment.Spec.RevisionHistoryLimit).To(BeNil())
+		err := fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &policyv1.PodDisruptionBudget{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should report a resource that would be recreated as deleted and created", func() {
+		plan := &reconcilePlan{}
+		c := newObservingClient(r.Client, r.Scheme, plan)
+
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: rm.Namespace}}
+		Expect(r.Client.Create(ctx, deployment)).To(Succeed())
+
+		Expect(c.Delete(ctx, deployment)).To(Succeed())
+		Expect(c.Create(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: rm.Namespace}})).To(Succeed())
+
+		Expect(plan.getPlannedChanges()).To(Equal([]v1alpha1.RolloutManagerPlannedChange{
+			{Action: v1alpha1.PlannedChangeActionDelete, Kind: "Deployment", Name: DefaultArgoRolloutsResourceName, Namespace: rm.Namespace},
+			{Action: v1alpha1.PlannedChangeActionCreate, Kind: "Deployment", Name: DefaultArgoRolloutsResourceName, Namespace: rm.Namespace},
+		}))
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &appsv1.Deployment{})).To(Succeed())
+	})
+
+	DescribeTable("combineFieldPaths should return the sorted union of the field paths, without the nested paths", func(one []string, two []string, expected []string) {
+		Expect(combineFieldPaths(one, two)).To(Equal(expected))
+	},
+		Entry("no paths", nil, nil, nil),
+		Entry("nested paths", []string{".spec.template.spec", ".metadata.labels.app"}, []string{".spec.template.spec.containers", ".metadata.labels"}, []string{".metadata.labels", ".spec.template.spec"}),
+		Entry("paths with a common prefix", []string{".spec.replicas", `.spec.template.spec.containers[name="argo-rollouts"]`}, []string{".spec.replicasX", `.spec.template.spec.containers[name="argo-rollouts"].image`},
+			[]string{".spec.replicas", ".spec.replicasX", `.spec.template.spec.containers[name="argo-rollouts"]`}),
+	)
+})
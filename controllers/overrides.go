@@ -0,0 +1,224 @@
+package rollouts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+// Kinds of the resources of the Rollouts controller that may be patched via .spec.overrides
+const (
+	overrideKindDeployment     = "Deployment"
+	overrideKindService        = "Service"
+	overrideKindServiceAccount = "ServiceAccount"
+	overrideKindNetworkPolicy  = "NetworkPolicy"
+	overrideKindRole           = "Role"
+	overrideKindClusterRole    = "ClusterRole"
+)
+
+// errInvalidOverride is returned when a patch of .spec.overrides cannot be parsed.
+var errInvalidOverride = errors.New("invalid override")
+
+// validateOverrides returns an error wrapping errInvalidOverride if an override of the RolloutManager is invalid,
+// or if the patched Role/ClusterRole or Deployment of the Rollouts controller would be invalid.
+func validateOverrides(cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	for i, override := range cr.Spec.Overrides {
+
+		switch override.Kind {
+		case overrideKindDeployment, overrideKindService, overrideKindServiceAccount, overrideKindNetworkPolicy, overrideKindRole, overrideKindClusterRole:
+		default:
+			return fmt.Errorf("%w: .spec.overrides[%d]: unsupported kind '%s'", errInvalidOverride, i, override.Kind)
+		}
+
+		patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+		if err != nil {
+			return fmt.Errorf("%w: .spec.overrides[%d]: unable to parse patch: %v", errInvalidOverride, i, err)
+		}
+
+		switch override.Type {
+		case "", rolloutsmanagerv1alpha1.OverridePatchTypeStrategic:
+			if err := json.Unmarshal(patch, &map[string]any{}); err != nil {
+				return fmt.Errorf("%w: .spec.overrides[%d]: a strategic merge patch must be an object: %v", errInvalidOverride, i, err)
+			}
+		case rolloutsmanagerv1alpha1.OverridePatchTypeJSON:
+			if _, err := jsonpatch.DecodePatch(patch); err != nil {
+				return fmt.Errorf("%w: .spec.overrides[%d]: a JSON patch must be a list of operations: %v", errInvalidOverride, i, err)
+			}
+		default:
+			return fmt.Errorf("%w: .spec.overrides[%d]: unsupported patch type '%s'", errInvalidOverride, i, override.Type)
+		}
+	}
+
+	// The patched resources are validated in advance, so that the webhook rejects them as well
+	rules := getPolicyRules(cr)
+
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}, Rules: rules}
+	if err := applyOverrides(cr, overrideKindRole, role.Name, role); err != nil {
+		return wrapInvalidOverride(err)
+	}
+	if err := validateOverriddenPolicyRules(overrideKindRole, rules, role.Rules, true); err != nil {
+		return err
+	}
+
+	clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}, Rules: rules}
+	if err := applyOverrides(cr, overrideKindClusterRole, clusterRole.Name, clusterRole); err != nil {
+		return wrapInvalidOverride(err)
+	}
+	if err := validateOverriddenPolicyRules(overrideKindClusterRole, rules, clusterRole.Rules, false); err != nil {
+		return err
+	}
+
+	if !hasOverrides(cr, overrideKindDeployment, DefaultArgoRolloutsResourceName) {
+		return nil
+	}
+
+	deployment, err := generateDesiredRolloutsDeployment(cr, corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: cr.Namespace}})
+	if err != nil {
+		// The other fields of the RolloutManager are validated during the reconciliation of the Deployment
+		return nil
+	}
+	patchedDeployment := *deployment.DeepCopy()
+	if err := applyOverrides(cr, overrideKindDeployment, patchedDeployment.Name, &patchedDeployment); err != nil {
+		return wrapInvalidOverride(err)
+	}
+
+	return validateOverriddenDeployment(deployment, patchedDeployment)
+}
+
+// wrapInvalidOverride wraps an error of applyOverrides with errInvalidOverride, unless it is already wrapped.
+func wrapInvalidOverride(err error) error {
+	if errors.Is(err, errInvalidOverride) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", errInvalidOverride, err)
+}
+
+// validateOverriddenPolicyRules returns an error wrapping errInvalidOverride if the overrides of the Role/ClusterRole of the Rollouts controller added a rule that
+// plugins may not add either, see validateDelegatedPolicyRule. The rules generated by the operator are not validated.
+func validateOverriddenPolicyRules(kind string, rules []rbacv1.PolicyRule, patchedRules []rbacv1.PolicyRule, namespaceScoped bool) error {
+	for _, rule := range patchedRules {
+		if slices.ContainsFunc(rules, func(generatedRule rbacv1.PolicyRule) bool { return equality.Semantic.DeepEqual(generatedRule, rule) }) {
+			continue
+		}
+		if err := validateDelegatedPolicyRule(rule, namespaceScoped); err != nil {
+			return fmt.Errorf("%w: invalid rule added to the %s: %v", errInvalidOverride, kind, err)
+		}
+	}
+	return nil
+}
+
+// validateOverriddenDeployment returns an error wrapping errInvalidOverride if the overrides of the Rollouts controller Deployment removed or renamed one of its containers or volumes,
+// or one of the volume mounts of its containers, such as the plugin-bin volume, or declared a container or volume more than once.
+func validateOverriddenDeployment(deployment appsv1.Deployment, patchedDeployment appsv1.Deployment) error {
+
+	podSpec, patchedPodSpec := deployment.Spec.Template.Spec, patchedDeployment.Spec.Template.Spec
+
+	for _, containers := range [][2][]corev1.Container{{podSpec.InitContainers, patchedPodSpec.InitContainers}, {podSpec.Containers, patchedPodSpec.Containers}} {
+		for _, container := range containers[0] {
+			index := slices.IndexFunc(containers[1], func(patchedContainer corev1.Container) bool { return patchedContainer.Name == container.Name })
+			if index < 0 {
+				return fmt.Errorf("%w: the container %s of the Deployment must not be removed", errInvalidOverride, container.Name)
+			}
+			for _, volumeMount := range container.VolumeMounts {
+				if !slices.ContainsFunc(containers[1][index].VolumeMounts, func(patchedVolumeMount corev1.VolumeMount) bool {
+					return patchedVolumeMount.Name == volumeMount.Name && patchedVolumeMount.MountPath == volumeMount.MountPath
+				}) {
+					return fmt.Errorf("%w: the volume mount %s of the container %s of the Deployment must not be removed", errInvalidOverride, volumeMount.Name, container.Name)
+				}
+			}
+		}
+	}
+
+	for _, volume := range podSpec.Volumes {
+		if !slices.ContainsFunc(patchedPodSpec.Volumes, func(patchedVolume corev1.Volume) bool { return patchedVolume.Name == volume.Name }) {
+			return fmt.Errorf("%w: the volume %s of the Deployment must not be removed", errInvalidOverride, volume.Name)
+		}
+	}
+
+	// Init containers and containers share the same namespace of names
+	containerNames := map[string]bool{}
+	for _, container := range append(append([]corev1.Container{}, patchedPodSpec.InitContainers...), patchedPodSpec.Containers...) {
+		if containerNames[container.Name] {
+			return fmt.Errorf("%w: the container %s of the Deployment is defined more than once", errInvalidOverride, container.Name)
+		}
+		containerNames[container.Name] = true
+	}
+
+	volumeNames := map[string]bool{}
+	for _, volume := range patchedPodSpec.Volumes {
+		if volumeNames[volume.Name] {
+			return fmt.Errorf("%w: the volume %s of the Deployment is defined more than once", errInvalidOverride, volume.Name)
+		}
+		volumeNames[volume.Name] = true
+	}
+
+	return nil
+}
+
+// hasOverrides returns true if the RolloutManager defines at least one override for the resource of the given kind and name.
+func hasOverrides(cr rolloutsmanagerv1alpha1.RolloutManager, kind string, name string) bool {
+	for _, override := range cr.Spec.Overrides {
+		if override.Kind == kind && (override.Name == "" || override.Name == name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyOverrides applies the overrides of the RolloutManager for the given kind and name to obj, in the order in which they are declared.
+func applyOverrides[T any](cr rolloutsmanagerv1alpha1.RolloutManager, kind string, name string, obj *T) error {
+
+	if !hasOverrides(cr, kind, name) {
+		return nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	for i, override := range cr.Spec.Overrides {
+		if override.Kind != kind || (override.Name != "" && override.Name != name) {
+			continue
+		}
+
+		patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+		if err != nil {
+			return fmt.Errorf("%w: .spec.overrides[%d]: unable to parse patch: %v", errInvalidOverride, i, err)
+		}
+
+		if override.Type == rolloutsmanagerv1alpha1.OverridePatchTypeJSON {
+			jsonPatch, err := jsonpatch.DecodePatch(patch)
+			if err != nil {
+				return fmt.Errorf("%w: .spec.overrides[%d]: %v", errInvalidOverride, i, err)
+			}
+			if data, err = jsonPatch.Apply(data); err != nil {
+				return fmt.Errorf("unable to apply .spec.overrides[%d] to %s %s: %w", i, kind, name, err)
+			}
+		} else {
+			if data, err = strategicpatch.StrategicMergePatch(data, patch, obj); err != nil {
+				return fmt.Errorf("unable to apply .spec.overrides[%d] to %s %s: %w", i, kind, name, err)
+			}
+		}
+	}
+
+	var patched T
+	if err := json.Unmarshal(data, &patched); err != nil {
+		return fmt.Errorf("unable to apply .spec.overrides to %s %s: %w", kind, name, err)
+	}
+	*obj = patched
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package rollouts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Override tests", func() {
+
+	const sidecarPatch = `
+spec:
+  template:
+    spec:
+      priorityClassName: system-cluster-critical
+      containers:
+      - name: log-shipper
+        image: quay.io/test/log-shipper:v1
+`
+
+	DescribeTable("should reject invalid overrides", func(override v1alpha1.RolloutManagerOverride, expectedError string) {
+		err := validateOverrides(v1alpha1.RolloutManager{Spec: v1alpha1.RolloutManagerSpec{Overrides: []v1alpha1.RolloutManagerOverride{override}}})
+		Expect(errors.Is(err, errInvalidOverride)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring(expectedError)))
+	},
+		Entry("with an unsupported kind", v1alpha1.RolloutManagerOverride{Kind: "Secret", Patch: "data: {}"}, "unsupported kind 'Secret'"),
+		Entry("with an invalid patch", v1alpha1.RolloutManagerOverride{Kind: "Deployment", Patch: "spec: ["}, "unable to parse patch"),
+		Entry("with a strategic merge patch that is not an object", v1alpha1.RolloutManagerOverride{Kind: "Deployment", Patch: "- a"}, "a strategic merge patch must be an object"),
+		Entry("with a JSON patch that is not a list", v1alpha1.RolloutManagerOverride{Kind: "Deployment", Type: v1alpha1.OverridePatchTypeJSON, Patch: "spec: {}"}, "a JSON patch must be a list of operations"),
+		Entry("with an unsupported patch type", v1alpha1.RolloutManagerOverride{Kind: "Deployment", Type: "merge", Patch: "spec: {}"}, "unsupported patch type 'merge'"),
+	)
+
+	DescribeTable("should reject overrides which grant forbidden rules or remove the containers and volumes of the operator", func(override v1alpha1.RolloutManagerOverride, expectedError string) {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Overrides = []v1alpha1.RolloutManagerOverride{override}
+		})
+
+		err := validateOverrides(cr)
+		Expect(errors.Is(err, errInvalidOverride)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring(expectedError)))
+	},
+		Entry("with a wildcard rule added to the ClusterRole", v1alpha1.RolloutManagerOverride{Kind: "ClusterRole", Type: v1alpha1.OverridePatchTypeJSON,
+			Patch: `[{"op": "add", "path": "/rules/-", "value": {"apiGroups": ["*"], "resources": ["*"], "verbs": ["*"]}}]`},
+			"invalid rule added to the ClusterRole: verb '*' is not allowed"),
+		Entry("with the rules of the Role replaced by access to secrets", v1alpha1.RolloutManagerOverride{Kind: "Role",
+			Patch: "rules:\n- apiGroups: ['']\n  resources: ['secrets']\n  verbs: ['get']"},
+			"invalid rule added to the Role: resource 'secrets' is not allowed"),
+		Entry("with a renamed Rollouts controller container", v1alpha1.RolloutManagerOverride{Kind: "Deployment", Type: v1alpha1.OverridePatchTypeJSON,
+			Patch: `[{"op": "replace", "path": "/spec/template/spec/containers/0/name", "value": "renamed"}]`},
+			"the container argo-rollouts of the Deployment must not be removed"),
+		Entry("with the volumes of the Deployment removed", v1alpha1.RolloutManagerOverride{Kind: "Deployment", Type: v1alpha1.OverridePatchTypeJSON,
+			Patch: `[{"op": "remove", "path": "/spec/template/spec/volumes"}]`},
+			"of the Deployment must not be removed"),
+		Entry("with the plugin-bin volume mount removed", v1alpha1.RolloutManagerOverride{Kind: "Deployment",
+			Patch: "spec:\n  template:\n    spec:\n      containers:\n      - name: argo-rollouts\n        $patch: replace"},
+			"of the Deployment must not be removed"),
+		Entry("with a patch that cannot be applied", v1alpha1.RolloutManagerOverride{Kind: "ClusterRole", Type: v1alpha1.OverridePatchTypeJSON,
+			Patch: `[{"op": "replace", "path": "/rules/1000", "value": {}}]`},
+			"unable to apply .spec.overrides[0] to ClusterRole argo-rollouts"),
+	)
+
+	It("should accept overrides which add allowed rules to the ClusterRole", func() {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Overrides = []v1alpha1.RolloutManagerOverride{{Kind: "ClusterRole", Type: v1alpha1.OverridePatchTypeJSON,
+				Patch: `[{"op": "add", "path": "/rules/-", "value": {"apiGroups": ["example.io"], "resources": ["widgets"], "verbs": ["get", "list"]}}]`}}
+		})
+		Expect(validateOverrides(cr)).To(Succeed())
+	})
+
+	It("should apply strategic merge and JSON patches in order, only to the matching resources", func() {
+		cr := v1alpha1.RolloutManager{Spec: v1alpha1.RolloutManagerSpec{Overrides: []v1alpha1.RolloutManagerOverride{
+			{Kind: "Deployment", Type: v1alpha1.OverridePatchTypeJSON, Patch: `[{"op": "add", "path": "/spec/template/spec/containers/0/args/-", "value": "--test"}]`},
+			{Kind: "Deployment", Patch: sidecarPatch},
+			{Kind: "Deployment", Name: "other-deployment", Patch: "spec: {replicas: 5}"},
+		}}}
+
+		deployment := appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "argo-rollouts", Image: "quay.io/argoproj/argo-rollouts:latest", Args: []string{"--namespaced"}}},
+			}}},
+		}
+
+		Expect(applyOverrides(cr, overrideKindDeployment, deployment.Name, &deployment)).To(Succeed())
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.PriorityClassName).To(Equal("system-cluster-critical"))
+		Expect(podSpec.Containers).To(ConsistOf(
+			corev1.Container{Name: "argo-rollouts", Image: "quay.io/argoproj/argo-rollouts:latest", Args: []string{"--namespaced", "--test"}},
+			corev1.Container{Name: "log-shipper", Image: "quay.io/test/log-shipper:v1"},
+		))
+		Expect(deployment.Spec.Replicas).To(BeNil())
+
+		By("not modifying resources of other kinds")
+		sa := corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}}
+		Expect(applyOverrides(cr, overrideKindServiceAccount, sa.Name, &sa)).To(Succeed())
+		Expect(sa).To(Equal(corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}}))
+	})
+
+	Context("reconciling resources with overrides", func() {
+
+		var (
+			ctx context.Context
+			a   v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+			sa  *corev1.ServiceAccount
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			a = *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.Overrides = []v1alpha1.RolloutManagerOverride{{Kind: "Deployment", Patch: sidecarPatch}}
+			})
+			r = makeTestReconciler(&a)
+			Expect(createNamespace(r, a.Namespace)).To(Succeed())
+
+			sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace}}
+			Expect(r.Client.Create(ctx, sa)).To(Succeed())
+		})
+
+		It("should create the patched Deployment, and neither revert the patch nor the defaults set by the API server", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.PriorityClassName).To(Equal("system-cluster-critical"))
+			Expect(getContainerNames(deployment.Spec.Template.Spec.Containers)).To(ConsistOf("argo-rollouts", "log-shipper"))
+
+			By("simulating the defaults set by the API server")
+			deployment.Spec.Template.Spec.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+			deployment.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+			resourceVersion := deployment.ResourceVersion
+
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.ResourceVersion).To(Equal(resourceVersion))
+		})
+
+		It("should repair drift of the patched fields, and remove the patched fields when the override is removed", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			deployment.Spec.Template.Spec.Containers = deployment.Spec.Template.Spec.Containers[1:]
+			deployment.Spec.Template.Spec.PriorityClassName = ""
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.PriorityClassName).To(Equal("system-cluster-critical"))
+			Expect(getContainerNames(deployment.Spec.Template.Spec.Containers)).To(ConsistOf("argo-rollouts", "log-shipper"))
+
+			a.Spec.Overrides = nil
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(getContainerNames(deployment.Spec.Template.Spec.Containers)).To(Equal([]string{"argo-rollouts"}))
+		})
+
+		It("should apply overrides to existing resources whose patched fields are not otherwise reconciled", func() {
+			_, err := r.reconcileRolloutsMetricsService(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+
+			a.Spec.Overrides = []v1alpha1.RolloutManagerOverride{
+				{Kind: "Service", Patch: "spec:\n  sessionAffinity: ClientIP\n"},
+				{Kind: "ServiceAccount", Patch: "automountServiceAccountToken: false\n"},
+			}
+
+			service, err := r.reconcileRolloutsMetricsService(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
+			resourceVersion := service.ResourceVersion
+
+			service, err = r.reconcileRolloutsMetricsService(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(service.ResourceVersion).To(Equal(resourceVersion))
+
+			serviceAccount, err := r.reconcileRolloutsServiceAccount(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(serviceAccount.AutomountServiceAccountToken).To(Equal(boolPtr(false)))
+		})
+
+		It("should not revert rules added to the ClusterRole via a JSON patch", func() {
+			a.Spec.Overrides = []v1alpha1.RolloutManagerOverride{{
+				Kind:  "ClusterRole",
+				Type:  v1alpha1.OverridePatchTypeJSON,
+				Patch: `[{"op": "add", "path": "/rules/-", "value": {"apiGroups": ["test.io"], "resources": ["tests"], "verbs": ["get"]}}]`,
+			}}
+
+			clusterRole, err := r.reconcileRolloutsClusterRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterRole.Rules).To(ContainElement(rbacv1.PolicyRule{APIGroups: []string{"test.io"}, Resources: []string{"tests"}, Verbs: []string{"get"}}))
+			Expect(clusterRole.Rules).To(HaveLen(len(GetPolicyRules()) + 1))
+
+			clusterRole, err = r.reconcileRolloutsClusterRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterRole.Rules).To(HaveLen(len(GetPolicyRules()) + 1))
+			resourceVersion := clusterRole.ResourceVersion
+
+			clusterRole, err = r.reconcileRolloutsClusterRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterRole.ResourceVersion).To(Equal(resourceVersion))
+		})
+	})
+})
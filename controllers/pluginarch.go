@@ -0,0 +1,153 @@
+package rollouts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// archNodeLabel is the well-known label that reports the CPU architecture of a node.
+	archNodeLabel = corev1.LabelArchStable
+
+	// defaultPluginArchitecture is selected when it is supported by every plugin that declares architectures, and no architecture is selected via .spec.nodePlacement.
+	defaultPluginArchitecture = "amd64"
+)
+
+// errUnsupportedPluginArchitecture is returned when no architecture is supported by every plugin, or when the architecture selected via .spec.nodePlacement is not supported by a plugin.
+var errUnsupportedPluginArchitecture = errors.New("unsupported plugin architecture")
+
+// validatePluginArchitectures returns an error if the per-architecture locations of the plugins are invalid.
+func validatePluginArchitectures(plugins rolloutsmanagerv1alpha1.Plugins) error {
+
+	for _, pluginList := range [][]rolloutsmanagerv1alpha1.Plugin{plugins.TrafficManagement, plugins.Metric, plugins.Step} {
+		for _, plugin := range pluginList {
+			if len(plugin.Architectures) == 0 {
+				continue
+			}
+
+			if plugin.Location != "" || plugin.Version != "" || plugin.Image != nil {
+				return fmt.Errorf("plugin %s must not set architectures together with a location, a version or an image", plugin.Name)
+			}
+
+			architectures := map[string]bool{}
+			for _, arch := range plugin.Architectures {
+				if arch.Architecture == "" || arch.Location == "" {
+					return fmt.Errorf("plugin %s must set both the architecture and the location of each of its architectures", plugin.Name)
+				}
+				if architectures[arch.Architecture] {
+					return fmt.Errorf("plugin %s declares architecture %s more than once", plugin.Name, arch.Architecture)
+				}
+				architectures[arch.Architecture] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// getPluginArchitecture returns the architecture that the Rollouts controller must run on, so that every plugin that declares per-architecture locations has an executable for it.
+// The plugins must be resolved from the plugin catalog, and openShiftRoutePlugin is the OpenShift Route plugin, or nil if it is disabled.
+//
+// The architecture of a plugin with a single location is unknown, so it neither restricts the architectures nor causes the Rollouts controller to be pinned.
+// An empty string is returned if no plugin declares per-architecture locations, in which case the Rollouts controller is not pinned to an architecture.
+func getPluginArchitecture(cr rolloutsmanagerv1alpha1.RolloutManager, openShiftRoutePlugin *rolloutsmanagerv1alpha1.Plugin) (string, error) {
+
+	if err := validatePluginArchitectures(cr.Spec.Plugins); err != nil {
+		return "", err
+	}
+
+	plugins := []rolloutsmanagerv1alpha1.Plugin{}
+	if openShiftRoutePlugin != nil {
+		plugins = append(plugins, *openShiftRoutePlugin)
+	}
+	plugins = append(plugins, cr.Spec.Plugins.TrafficManagement...)
+	plugins = append(plugins, cr.Spec.Plugins.Metric...)
+	plugins = append(plugins, cr.Spec.Plugins.Step...)
+
+	// supported contains the architectures supported by every plugin so far, or nil if no plugin has declared architectures yet
+	var supported map[string]bool
+
+	for _, plugin := range plugins {
+		// Plugins with a single location, and images, which are pulled for the architecture of the node, are not restricted to architectures
+		if len(plugin.Architectures) == 0 {
+			continue
+		}
+
+		architectures := map[string]bool{}
+		for _, arch := range plugin.Architectures {
+			if supported == nil || supported[arch.Architecture] {
+				architectures[arch.Architecture] = true
+			}
+		}
+		if len(architectures) == 0 {
+			return "", fmt.Errorf("%w: plugin %s does not support any architecture supported by the other plugins", errUnsupportedPluginArchitecture, plugin.Name)
+		}
+		supported = architectures
+	}
+
+	if supported == nil {
+		return "", nil
+	}
+
+	// An architecture selected by the user takes precedence
+	if cr.Spec.NodePlacement != nil {
+		if arch, exists := cr.Spec.NodePlacement.NodeSelector[archNodeLabel]; exists {
+			if !supported[arch] {
+				return "", fmt.Errorf("%w: architecture %s selected via .spec.nodePlacement is not supported by every plugin", errUnsupportedPluginArchitecture, arch)
+			}
+			return arch, nil
+		}
+	}
+
+	if supported[defaultPluginArchitecture] {
+		return defaultPluginArchitecture, nil
+	}
+
+	architectures := []string{}
+	for arch := range supported {
+		architectures = append(architectures, arch)
+	}
+	sort.Strings(architectures)
+
+	return architectures[0], nil
+}
+
+// resolvePluginArchitectures returns a copy of the RolloutManager, where each plugin with per-architecture locations uses the location and checksum of the given architecture,
+// and where the Rollouts controller is pinned to nodes of that architecture via .spec.nodePlacement.
+func resolvePluginArchitectures(cr rolloutsmanagerv1alpha1.RolloutManager, arch string) rolloutsmanagerv1alpha1.RolloutManager {
+
+	cr = *cr.DeepCopy()
+
+	for _, pluginList := range [][]rolloutsmanagerv1alpha1.Plugin{cr.Spec.Plugins.TrafficManagement, cr.Spec.Plugins.Metric, cr.Spec.Plugins.Step} {
+		for i := range pluginList {
+			pluginList[i] = resolvePluginArchitecture(pluginList[i], arch)
+		}
+	}
+
+	if cr.Spec.NodePlacement == nil {
+		cr.Spec.NodePlacement = &rolloutsmanagerv1alpha1.RolloutsNodePlacementSpec{}
+	}
+	cr.Spec.NodePlacement.NodeSelector = appendStringMap(cr.Spec.NodePlacement.NodeSelector, map[string]string{archNodeLabel: arch})
+
+	return cr
+}
+
+// resolvePluginArchitecture returns the plugin with the location and checksum of the given architecture, if it declares per-architecture locations.
+func resolvePluginArchitecture(plugin rolloutsmanagerv1alpha1.Plugin, arch string) rolloutsmanagerv1alpha1.Plugin {
+
+	for _, pluginArch := range plugin.Architectures {
+		if pluginArch.Architecture == arch {
+			plugin.Location = pluginArch.Location
+			if plugin.SHA256 == "" {
+				plugin.SHA256 = pluginArch.SHA256
+			}
+		}
+	}
+	plugin.Architectures = nil
+
+	return plugin
+}
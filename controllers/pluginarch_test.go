@@ -0,0 +1,213 @@
+package rollouts
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Plugin architecture tests", func() {
+
+	multiArchPlugin := func(name string, archs ...string) v1alpha1.Plugin {
+		plugin := v1alpha1.Plugin{Name: name}
+		for _, arch := range archs {
+			plugin.Architectures = append(plugin.Architectures, v1alpha1.PluginArchitecture{
+				Architecture: arch,
+				Location:     "https://test-path/" + name + "-linux-" + arch,
+				SHA256:       "sha256-" + arch,
+			})
+		}
+		return plugin
+	}
+
+	makeRolloutManager := func(plugins v1alpha1.Plugins, nodeSelector map[string]string) v1alpha1.RolloutManager {
+		return *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Plugins = plugins
+			if nodeSelector != nil {
+				rm.Spec.NodePlacement = &v1alpha1.RolloutsNodePlacementSpec{NodeSelector: nodeSelector}
+			}
+		})
+	}
+
+	// openShiftRoutePlugin is the OpenShift Route plugin of the plugin catalog, while a plugin with a single location is set via OPENSHIFT_ROUTE_PLUGIN_LOCATION
+	openShiftRoutePlugin := multiArchPlugin(OpenShiftRolloutPluginName, "amd64", "arm64")
+	singleLocationOpenShiftRoutePlugin := v1alpha1.Plugin{Name: OpenShiftRolloutPluginName, Location: "https://test-path/openshift"}
+
+	DescribeTable("should select an architecture supported by every plugin", func(plugins v1alpha1.Plugins, nodeSelector map[string]string, openShiftRoutePlugin *v1alpha1.Plugin, expectedArch string) {
+		arch, err := getPluginArchitecture(makeRolloutManager(plugins, nodeSelector), openShiftRoutePlugin)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arch).To(Equal(expectedArch))
+	},
+		Entry("without plugin architectures", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{{Name: "test/metric", Location: "https://test-path"}}}, map[string]string{"kubernetes.io/arch": "arm64"}, &singleLocationOpenShiftRoutePlugin, ""),
+		Entry("preferring amd64", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "arm64", "amd64")}}, nil, nil, "amd64"),
+		Entry("with the only common architecture", v1alpha1.Plugins{
+			Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "arm64", "amd64", "s390x")},
+			Step:   []v1alpha1.Plugin{multiArchPlugin("test/step", "s390x", "arm64")},
+		}, nil, nil, "arm64"),
+		Entry("with the architecture selected via nodePlacement", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "amd64", "arm64")}}, map[string]string{"kubernetes.io/arch": "arm64"}, nil, "arm64"),
+		Entry("with only the OpenShift Route plugin", v1alpha1.Plugins{}, nil, &openShiftRoutePlugin, "amd64"),
+		Entry("with the OpenShift Route plugin, and the architecture selected via nodePlacement", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "amd64", "arm64")}}, map[string]string{"kubernetes.io/arch": "arm64"}, &openShiftRoutePlugin, "arm64"),
+		Entry("with an OpenShift Route plugin with a single location", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "arm64")}}, nil, &singleLocationOpenShiftRoutePlugin, "arm64"),
+		Entry("with a plugin with a single location, and the architecture selected via nodePlacement", v1alpha1.Plugins{
+			Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "arm64", "amd64")},
+			Step:   []v1alpha1.Plugin{{Name: "test/step", Location: "https://test-path"}},
+		}, map[string]string{"kubernetes.io/arch": "arm64"}, nil, "arm64"),
+		Entry("with a plugin image", v1alpha1.Plugins{
+			Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "arm64")},
+			Step:   []v1alpha1.Plugin{{Name: "test/step", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v1", Path: "/bin/plugin"}}},
+		}, nil, nil, "arm64"),
+	)
+
+	DescribeTable("should return an error if no architecture is supported by every plugin", func(plugins v1alpha1.Plugins, nodeSelector map[string]string, openShiftRoutePlugin *v1alpha1.Plugin, expectedError string) {
+		_, err := getPluginArchitecture(makeRolloutManager(plugins, nodeSelector), openShiftRoutePlugin)
+		Expect(errors.Is(err, errUnsupportedPluginArchitecture)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring(expectedError)))
+	},
+		Entry("with disjoint architectures", v1alpha1.Plugins{
+			Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "amd64")},
+			Step:   []v1alpha1.Plugin{multiArchPlugin("test/step", "arm64")},
+		}, nil, nil, "plugin test/step does not support any architecture supported by the other plugins"),
+		Entry("with an unsupported architecture selected via nodePlacement", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "amd64")}}, map[string]string{"kubernetes.io/arch": "arm64"}, nil, "architecture arm64 selected via .spec.nodePlacement is not supported by every plugin"),
+		Entry("with an architecture not supported by the OpenShift Route plugin", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{multiArchPlugin("test/metric", "s390x")}}, nil, &openShiftRoutePlugin,
+			"plugin test/metric does not support any architecture supported by the other plugins"),
+		Entry("with an architecture selected via nodePlacement which is not supported by the OpenShift Route plugin", v1alpha1.Plugins{}, map[string]string{"kubernetes.io/arch": "s390x"}, &openShiftRoutePlugin,
+			"architecture s390x selected via .spec.nodePlacement is not supported by every plugin"),
+	)
+
+	DescribeTable("should reject invalid plugin architectures", func(plugin v1alpha1.Plugin, expectedError string) {
+		Expect(validatePlugins(v1alpha1.RolloutManager{Spec: v1alpha1.RolloutManagerSpec{
+			Plugins: v1alpha1.Plugins{Metric: []v1alpha1.Plugin{plugin}},
+		}})).To(MatchError(expectedError))
+	},
+		Entry("with a location", v1alpha1.Plugin{Name: "test/metric", Location: "https://test-path", Architectures: multiArchPlugin("test/metric", "amd64").Architectures},
+			"plugin test/metric must not set architectures together with a location, a version or an image"),
+		Entry("without a location", v1alpha1.Plugin{Name: "test/metric", Architectures: []v1alpha1.PluginArchitecture{{Architecture: "amd64"}}},
+			"plugin test/metric must set both the architecture and the location of each of its architectures"),
+		Entry("with a duplicate architecture", multiArchPlugin("test/metric", "amd64", "amd64"),
+			"plugin test/metric declares architecture amd64 more than once"),
+	)
+
+	It("should resolve the location and checksum of the architecture, and pin the Rollouts controller to it", func() {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Plugins.Metric = []v1alpha1.Plugin{multiArchPlugin("test/metric", "amd64", "arm64")}
+			rm.Spec.NodePlacement = &v1alpha1.RolloutsNodePlacementSpec{NodeSelector: map[string]string{"key1": "value1"}}
+		})
+
+		resolved := resolvePluginArchitectures(cr, "arm64")
+		Expect(resolved.Spec.Plugins.Metric).To(Equal([]v1alpha1.Plugin{{
+			Name:     "test/metric",
+			Location: "https://test-path/test/metric-linux-arm64",
+			SHA256:   "sha256-arm64",
+		}}))
+		Expect(resolved.Spec.NodePlacement.NodeSelector).To(Equal(map[string]string{"key1": "value1", "kubernetes.io/arch": "arm64"}))
+
+		By("not modifying the RolloutManager")
+		Expect(cr.Spec.Plugins.Metric[0].Architectures).To(HaveLen(2))
+		Expect(cr.Spec.NodePlacement.NodeSelector).To(HaveLen(1))
+	})
+
+	Context("reconciling a RolloutManager with plugin architectures", func() {
+
+		var (
+			ctx context.Context
+			rm  *v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			rm = makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.Plugins.TrafficManagement = []v1alpha1.Plugin{multiArchPlugin("test/traffic", "amd64", "arm64")}
+				rm.Spec.NodePlacement = &v1alpha1.RolloutsNodePlacementSpec{NodeSelector: map[string]string{"kubernetes.io/arch": "arm64"}}
+				rm.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{OpenShiftRoute: &v1alpha1.OpenShiftRouteTrafficRoutingSpec{Enabled: boolPtr(false)}}
+			})
+			r = makeTestReconciler(rm)
+			Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+
+			os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+		})
+
+		It("should write the location of the selected architecture into the ConfigMap, and schedule the Rollouts controller on it", func() {
+			_, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring("https://test-path/test/traffic-linux-arm64"))
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("kubernetes.io/arch", "arm64"))
+		})
+
+		It("should resolve the per-architecture locations of catalog plugins, including the OpenShift Route plugin", func() {
+			rm.Spec.Plugins.Metric = []v1alpha1.Plugin{{Name: "argoproj-labs/sample-prometheus", Version: "v0.0.3"}}
+			rm.Spec.TrafficRouting = nil
+			r.OpenShiftRoutePluginLocation = ""
+
+			_, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[MetricPluginConfigMapKey]).To(ContainSubstring("/metric-plugin-linux-arm64"))
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring("/rollouts-plugin-trafficrouter-openshift-linux-arm64"))
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("kubernetes.io/arch", "arm64"))
+
+			By("selecting amd64 once the architecture is no longer selected via .spec.nodePlacement")
+			delete(rm.Spec.NodePlacement.NodeSelector, "kubernetes.io/arch")
+			_, err = r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring("/rollouts-plugin-trafficrouter-openshift-linux-amd64"))
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("kubernetes.io/arch", "amd64"))
+		})
+
+		It("should not restrict the architecture of the Rollouts controller for plugins with a single location", func() {
+			rm.Spec.Plugins.Metric = []v1alpha1.Plugin{{Name: "test/metric", Location: "https://test-path/metric-plugin"}}
+			r.OpenShiftRoutePluginLocation = "https://test-path/openshift-plugin"
+			rm.Spec.TrafficRouting = nil
+
+			_, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("kubernetes.io/arch", "arm64"))
+
+			By("not pinning the Rollouts controller if no plugin declares architectures")
+			rm.Spec.Plugins.TrafficManagement = nil
+			delete(rm.Spec.NodePlacement.NodeSelector, "kubernetes.io/arch")
+			_, err = r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.NodeSelector).ToNot(HaveKey("kubernetes.io/arch"))
+		})
+
+		It("should report an unsupported architecture in the status condition", func() {
+			rm.Spec.NodePlacement.NodeSelector["kubernetes.io/arch"] = "s390x"
+
+			res, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).To(HaveOccurred())
+			Expect(res.condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(res.condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonUnsupportedPluginArchitecture))
+		})
+	})
+})
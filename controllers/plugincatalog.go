@@ -0,0 +1,276 @@
+package rollouts
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// embeddedPluginCatalog is the plugin catalog shipped with the operator.
+//
+//go:embed plugin_catalog.yaml
+var embeddedPluginCatalog []byte
+
+// errUnknownPluginVersion is returned when a plugin references a name and version that is not part of the plugin catalog.
+var errUnknownPluginVersion = errors.New("unknown plugin version")
+
+// pluginCatalog is a catalog of plugins, which may be referenced by name and version from .spec.plugins.
+type pluginCatalog struct {
+	Plugins []pluginCatalogEntry `json:"plugins"`
+}
+
+// pluginCatalogEntry lists the known versions of a plugin.
+type pluginCatalogEntry struct {
+	Name     string                 `json:"name"`
+	Versions []pluginCatalogVersion `json:"versions"`
+}
+
+// pluginCatalogVersion describes where to download a version of a plugin, either from a single location or from a location per architecture, and the RBAC rules it requires.
+type pluginCatalogVersion struct {
+	Version       string                                       `json:"version"`
+	Location      string                                       `json:"location,omitempty"`
+	SHA256        string                                       `json:"sha256,omitempty"`
+	Architectures []rolloutsmanagerv1alpha1.PluginArchitecture `json:"architectures,omitempty"`
+	Rules         []rbacv1.PolicyRule                          `json:"rules,omitempty"`
+}
+
+// parsePluginCatalog parses a plugin catalog, and returns an error if an entry is incomplete.
+func parsePluginCatalog(data []byte) (pluginCatalog, error) {
+	catalog := pluginCatalog{}
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return catalog, fmt.Errorf("failed to parse plugin catalog: %w", err)
+	}
+
+	for _, entry := range catalog.Plugins {
+		if entry.Name == "" {
+			return catalog, fmt.Errorf("invalid plugin catalog: plugin name must not be empty")
+		}
+		for _, version := range entry.Versions {
+			if version.Version == "" {
+				return catalog, fmt.Errorf("invalid plugin catalog: version of plugin %s must not be empty", entry.Name)
+			}
+			if (version.Location == "") == (len(version.Architectures) == 0) {
+				return catalog, fmt.Errorf("invalid plugin catalog: version %s of plugin %s must set either a location or architectures", version.Version, entry.Name)
+			}
+			for _, arch := range version.Architectures {
+				if arch.Architecture == "" || arch.Location == "" {
+					return catalog, fmt.Errorf("invalid plugin catalog: version %s of plugin %s must set both the architecture and the location of each of its architectures", version.Version, entry.Name)
+				}
+			}
+		}
+	}
+
+	return catalog, nil
+}
+
+// lookup returns the given version of a plugin, if it is part of the catalog.
+func (c pluginCatalog) lookup(name string, version string) (pluginCatalogVersion, bool) {
+	for _, entry := range c.Plugins {
+		if entry.Name != name {
+			continue
+		}
+		for _, v := range entry.Versions {
+			if v.Version == version {
+				return v, true
+			}
+		}
+	}
+	return pluginCatalogVersion{}, false
+}
+
+// merge returns a catalog containing the versions of both catalogs. Versions of 'override' replace the versions of c with the same name and version.
+func (c pluginCatalog) merge(override pluginCatalog) pluginCatalog {
+
+	merged := pluginCatalog{}
+
+	for _, entry := range append(append([]pluginCatalogEntry{}, c.Plugins...), override.Plugins...) {
+
+		index := -1
+		for i := range merged.Plugins {
+			if merged.Plugins[i].Name == entry.Name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			merged.Plugins = append(merged.Plugins, pluginCatalogEntry{Name: entry.Name})
+			index = len(merged.Plugins) - 1
+		}
+
+		for _, version := range entry.Versions {
+			replaced := false
+			for i := range merged.Plugins[index].Versions {
+				if merged.Plugins[index].Versions[i].Version == version.Version {
+					merged.Plugins[index].Versions[i] = version
+					replaced = true
+				}
+			}
+			if !replaced {
+				merged.Plugins[index].Versions = append(merged.Plugins[index].Versions, version)
+			}
+		}
+	}
+
+	return merged
+}
+
+// getPluginCatalog returns the embedded plugin catalog, merged with the catalog of the PLUGIN_CATALOG_CONFIGMAP ConfigMap, if configured.
+func (r *RolloutManagerReconciler) getPluginCatalog(ctx context.Context) (pluginCatalog, error) {
+	return loadPluginCatalog(ctx, r.Client, r.PluginCatalogConfigMap)
+}
+
+// loadPluginCatalog returns the embedded plugin catalog, merged with the catalog of the given ConfigMap. The ConfigMap is ignored when its name is empty.
+func loadPluginCatalog(ctx context.Context, k8sClient client.Client, catalogConfigMap types.NamespacedName) (pluginCatalog, error) {
+
+	catalog, err := parsePluginCatalog(embeddedPluginCatalog)
+	if err != nil {
+		return catalog, err
+	}
+
+	if catalogConfigMap.Name == "" {
+		return catalog, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := fetchObject(ctx, k8sClient, catalogConfigMap.Namespace, catalogConfigMap.Name, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info(fmt.Sprintf("plugin catalog ConfigMap %s not found, only the embedded plugin catalog is used", catalogConfigMap.String()))
+			return catalog, nil
+		}
+		return catalog, fmt.Errorf("failed to get plugin catalog ConfigMap %s: %w", catalogConfigMap.String(), err)
+	}
+
+	override, err := parsePluginCatalog([]byte(configMap.Data[PluginCatalogConfigMapKey]))
+	if err != nil {
+		return catalog, fmt.Errorf("invalid plugin catalog ConfigMap %s: %w", catalogConfigMap.String(), err)
+	}
+
+	return catalog.merge(override), nil
+}
+
+// lookupOpenShiftRoutePlugin returns the OpenShift Route plugin, or nil if it is disabled via .spec.trafficRouting.openShiftRoute: from the given location if set,
+// which is the OPENSHIFT_ROUTE_PLUGIN_LOCATION environment variable of the operator, or DefaultOpenShiftRoutePluginVersion of the plugin catalog otherwise.
+// The per-architecture locations of the catalog are returned as is, and are resolved by resolvePluginArchitecture.
+func lookupOpenShiftRoutePlugin(cr rolloutsmanagerv1alpha1.RolloutManager, catalog pluginCatalog, location string) (*rolloutsmanagerv1alpha1.Plugin, error) {
+
+	if !cr.Spec.TrafficRouting.IsOpenShiftRouteEnabled() {
+		return nil, nil
+	}
+
+	if location != "" {
+		return &rolloutsmanagerv1alpha1.Plugin{Name: OpenShiftRolloutPluginName, Location: location}, nil
+	}
+
+	version, exists := catalog.lookup(OpenShiftRolloutPluginName, DefaultOpenShiftRoutePluginVersion)
+	if !exists {
+		return nil, fmt.Errorf("%w: plugin %s version %s is not part of the plugin catalog", errUnknownPluginVersion, OpenShiftRolloutPluginName, DefaultOpenShiftRoutePluginVersion)
+	}
+
+	return &rolloutsmanagerv1alpha1.Plugin{
+		Name:          OpenShiftRolloutPluginName,
+		Location:      version.Location,
+		SHA256:        version.SHA256,
+		Architectures: append([]rolloutsmanagerv1alpha1.PluginArchitecture{}, version.Architectures...),
+	}, nil
+}
+
+// getOpenShiftRoutePlugin returns the OpenShift Route plugin, or nil if it is disabled via .spec.trafficRouting.openShiftRoute.
+// If the plugin has per-architecture locations, the location of the architecture selected by getPluginArchitecture is returned.
+func (r *RolloutManagerReconciler) getOpenShiftRoutePlugin(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (*rolloutsmanagerv1alpha1.Plugin, error) {
+
+	catalog, err := r.getPluginCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plugin, err := lookupOpenShiftRoutePlugin(cr, catalog, r.OpenShiftRoutePluginLocation)
+	if err != nil || plugin == nil || len(plugin.Architectures) == 0 {
+		return plugin, err
+	}
+
+	// The architecture of a RolloutManager that was resolved by resolvePluginArchitectures is the one of its node selector
+	arch, err := getPluginArchitecture(cr, plugin)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := resolvePluginArchitecture(*plugin, arch)
+	return &resolved, nil
+}
+
+// resolvePlugins returns a copy of plugins, where the plugins that reference a version instead of a location or an image are resolved from the catalog.
+// The location and checksum, or the per-architecture locations, of a resolved plugin are set from the catalog, and the RBAC rules of the catalog are added to the rules of the plugin.
+func resolvePlugins(catalog pluginCatalog, plugins rolloutsmanagerv1alpha1.Plugins) (rolloutsmanagerv1alpha1.Plugins, error) {
+
+	resolveList := func(pluginList []rolloutsmanagerv1alpha1.Plugin) ([]rolloutsmanagerv1alpha1.Plugin, error) {
+		if pluginList == nil {
+			return nil, nil
+		}
+
+		resolved := []rolloutsmanagerv1alpha1.Plugin{}
+		for _, plugin := range pluginList {
+			plugin = *plugin.DeepCopy()
+
+			// Plugins with per-architecture locations are resolved by resolvePluginArchitectures
+			if len(plugin.Architectures) > 0 {
+				resolved = append(resolved, plugin)
+				continue
+			}
+
+			// The version would otherwise be silently ignored
+			if plugin.Version != "" && (plugin.Location != "" || plugin.Image != nil) {
+				return nil, fmt.Errorf("plugin %s must not set a version together with a location or an image", plugin.Name)
+			}
+
+			if plugin.Location == "" && plugin.Image == nil {
+				if plugin.Version == "" {
+					return nil, fmt.Errorf("plugin %s must set either a location, a version, an image or architectures", plugin.Name)
+				}
+
+				version, exists := catalog.lookup(plugin.Name, plugin.Version)
+				if !exists {
+					return nil, fmt.Errorf("%w: plugin %s version %s is not part of the plugin catalog", errUnknownPluginVersion, plugin.Name, plugin.Version)
+				}
+
+				plugin.Location = version.Location
+				if plugin.SHA256 == "" {
+					plugin.SHA256 = version.SHA256
+				}
+				plugin.Rules = append(plugin.Rules, version.Rules...)
+
+				// A version with per-architecture locations is resolved by resolvePluginArchitectures, like a plugin that declares architectures
+				if len(version.Architectures) > 0 {
+					plugin.Architectures = append([]rolloutsmanagerv1alpha1.PluginArchitecture{}, version.Architectures...)
+					plugin.Version = ""
+				}
+			}
+
+			resolved = append(resolved, plugin)
+		}
+		return resolved, nil
+	}
+
+	res := plugins
+	var err error
+
+	if res.TrafficManagement, err = resolveList(plugins.TrafficManagement); err != nil {
+		return res, err
+	}
+	if res.Metric, err = resolveList(plugins.Metric); err != nil {
+		return res, err
+	}
+	if res.Step, err = resolveList(plugins.Step); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
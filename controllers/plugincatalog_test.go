@@ -0,0 +1,251 @@
+package rollouts
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Plugin catalog tests", func() {
+
+	const (
+		gatewayAPIPluginName     = "argoproj-labs/gatewayAPI"
+		gatewayAPIPluginLocation = "https://github.com/argoproj-labs/rollouts-plugin-trafficrouter-gatewayapi/releases/download/v0.4.0/gatewayapi-plugin-linux-amd64"
+	)
+
+	It("should parse the embedded plugin catalog", func() {
+		catalog, err := parsePluginCatalog(embeddedPluginCatalog)
+		Expect(err).ToNot(HaveOccurred())
+
+		version, exists := catalog.lookup(gatewayAPIPluginName, "v0.4.0")
+		Expect(exists).To(BeTrue())
+		Expect(version.Architectures).To(ContainElement(HaveField("Location", gatewayAPIPluginLocation)))
+		Expect(version.Rules).ToNot(BeEmpty())
+
+		_, exists = catalog.lookup(gatewayAPIPluginName, "v0.0.0")
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should pin every location of the embedded plugin catalog to its sha256 checksum", func() {
+		catalog, err := parsePluginCatalog(embeddedPluginCatalog)
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, entry := range catalog.Plugins {
+			for _, version := range entry.Versions {
+				if len(version.Architectures) == 0 {
+					Expect(version.SHA256).To(MatchRegexp("^[0-9a-f]{64}$"),
+						"plugin %s version %s has no sha256: run hack/update-plugin-catalog-checksums.sh", entry.Name, version.Version)
+				}
+				for _, arch := range version.Architectures {
+					Expect(arch.SHA256).To(MatchRegexp("^[0-9a-f]{64}$"),
+						"plugin %s version %s has no sha256 for %s: run hack/update-plugin-catalog-checksums.sh", entry.Name, version.Version, arch.Architecture)
+				}
+			}
+		}
+	})
+
+	DescribeTable("should reject an incomplete plugin catalog", func(data string, expectedError string) {
+		_, err := parsePluginCatalog([]byte(data))
+		Expect(err).To(MatchError(ContainSubstring(expectedError)))
+	},
+		Entry("without plugin name", "plugins:\n- versions:\n  - version: v1\n    location: https://test-path\n", "plugin name must not be empty"),
+		Entry("without version", "plugins:\n- name: test\n  versions:\n  - location: https://test-path\n", "version of plugin test must not be empty"),
+		Entry("without location", "plugins:\n- name: test\n  versions:\n  - version: v1\n", "version v1 of plugin test must set either a location or architectures"),
+		Entry("with both a location and architectures", "plugins:\n- name: test\n  versions:\n  - version: v1\n    location: https://test-path\n    architectures:\n    - architecture: amd64\n      location: https://test-path/amd64\n",
+			"version v1 of plugin test must set either a location or architectures"),
+		Entry("with an architecture without location", "plugins:\n- name: test\n  versions:\n  - version: v1\n    architectures:\n    - architecture: amd64\n",
+			"version v1 of plugin test must set both the architecture and the location of each of its architectures"),
+		Entry("invalid yaml", "plugins: {", "failed to parse plugin catalog"),
+	)
+
+	It("should merge catalogs, replacing versions with the same name and version", func() {
+		catalog := pluginCatalog{Plugins: []pluginCatalogEntry{
+			{Name: "a", Versions: []pluginCatalogVersion{{Version: "v1", Location: "https://a/v1"}, {Version: "v2", Location: "https://a/v2"}}},
+		}}
+		override := pluginCatalog{Plugins: []pluginCatalogEntry{
+			{Name: "a", Versions: []pluginCatalogVersion{{Version: "v2", Location: "https://mirror/a/v2", SHA256: "abc"}, {Version: "v3", Location: "https://a/v3"}}},
+			{Name: "b", Versions: []pluginCatalogVersion{{Version: "v1", Location: "https://b/v1"}}},
+		}}
+
+		Expect(catalog.merge(override)).To(Equal(pluginCatalog{Plugins: []pluginCatalogEntry{
+			{Name: "a", Versions: []pluginCatalogVersion{
+				{Version: "v1", Location: "https://a/v1"},
+				{Version: "v2", Location: "https://mirror/a/v2", SHA256: "abc"},
+				{Version: "v3", Location: "https://a/v3"},
+			}},
+			{Name: "b", Versions: []pluginCatalogVersion{{Version: "v1", Location: "https://b/v1"}}},
+		}}))
+	})
+
+	Context("resolving plugins", func() {
+
+		var catalog pluginCatalog
+
+		BeforeEach(func() {
+			catalog = pluginCatalog{Plugins: []pluginCatalogEntry{
+				{Name: "test/plugin", Versions: []pluginCatalogVersion{{
+					Version:  "v1.0.0",
+					Location: "https://test-path/v1.0.0",
+					SHA256:   "test-sha256",
+					Rules:    []rbacv1.PolicyRule{{APIGroups: []string{"test.io"}, Resources: []string{"tests"}, Verbs: []string{"get"}}},
+				}}},
+			}}
+		})
+
+		It("should resolve the location, checksum and rules of a plugin that references a version", func() {
+			plugins := v1alpha1.Plugins{
+				TrafficManagement: []v1alpha1.Plugin{{Name: "test/plugin", Version: "v1.0.0"}},
+				Metric:            []v1alpha1.Plugin{{Name: "test/metric", Location: "https://test-path/metric"}},
+			}
+
+			resolved, err := resolvePlugins(catalog, plugins)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved.TrafficManagement).To(Equal([]v1alpha1.Plugin{{
+				Name:     "test/plugin",
+				Version:  "v1.0.0",
+				Location: "https://test-path/v1.0.0",
+				SHA256:   "test-sha256",
+				Rules:    []rbacv1.PolicyRule{{APIGroups: []string{"test.io"}, Resources: []string{"tests"}, Verbs: []string{"get"}}},
+			}}))
+
+			By("leaving plugins with an explicit location untouched")
+			Expect(resolved.Metric).To(Equal(plugins.Metric))
+			Expect(resolved.Step).To(BeNil())
+
+			By("not modifying the plugins of the RolloutManager")
+			Expect(plugins.TrafficManagement[0].Location).To(BeEmpty())
+		})
+
+		It("should resolve the per-architecture locations of a version, leaving the selection of the architecture to resolvePluginArchitectures", func() {
+			architectures := []v1alpha1.PluginArchitecture{
+				{Architecture: "amd64", Location: "https://test-path/v2.0.0-amd64", SHA256: "sha256-amd64"},
+				{Architecture: "arm64", Location: "https://test-path/v2.0.0-arm64", SHA256: "sha256-arm64"},
+			}
+			catalog.Plugins[0].Versions = append(catalog.Plugins[0].Versions, pluginCatalogVersion{Version: "v2.0.0", Architectures: architectures})
+
+			resolved, err := resolvePlugins(catalog, v1alpha1.Plugins{Metric: []v1alpha1.Plugin{{Name: "test/plugin", Version: "v2.0.0"}}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved.Metric).To(Equal([]v1alpha1.Plugin{{Name: "test/plugin", Architectures: architectures}}))
+
+			Expect(resolvePluginArchitecture(resolved.Metric[0], "arm64")).To(Equal(v1alpha1.Plugin{
+				Name:     "test/plugin",
+				Location: "https://test-path/v2.0.0-arm64",
+				SHA256:   "sha256-arm64",
+			}))
+		})
+
+		It("should look up the OpenShift Route plugin from the catalog, unless its location is set", func() {
+			architectures := []v1alpha1.PluginArchitecture{{Architecture: "arm64", Location: "https://test-path/openshift-arm64"}}
+			catalog.Plugins = append(catalog.Plugins, pluginCatalogEntry{Name: OpenShiftRolloutPluginName, Versions: []pluginCatalogVersion{{Version: DefaultOpenShiftRoutePluginVersion, Architectures: architectures}}})
+			rm := *makeTestRolloutManager()
+
+			plugin, err := lookupOpenShiftRoutePlugin(rm, catalog, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugin).To(Equal(&v1alpha1.Plugin{Name: OpenShiftRolloutPluginName, Architectures: architectures}))
+
+			plugin, err = lookupOpenShiftRoutePlugin(rm, catalog, "https://test-path/openshift")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugin).To(Equal(&v1alpha1.Plugin{Name: OpenShiftRolloutPluginName, Location: "https://test-path/openshift"}))
+
+			By("returning nil once the plugin is disabled")
+			rm.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{OpenShiftRoute: &v1alpha1.OpenShiftRouteTrafficRoutingSpec{Enabled: boolPtr(false)}}
+			plugin, err = lookupOpenShiftRoutePlugin(rm, catalog, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(plugin).To(BeNil())
+		})
+
+		It("should return an error for an unknown version", func() {
+			_, err := resolvePlugins(catalog, v1alpha1.Plugins{Step: []v1alpha1.Plugin{{Name: "test/plugin", Version: "v2.0.0"}}})
+			Expect(errors.Is(err, errUnknownPluginVersion)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("plugin test/plugin version v2.0.0 is not part of the plugin catalog")))
+		})
+
+		It("should return an error if a version is set together with a location or an image", func() {
+			_, err := resolvePlugins(catalog, v1alpha1.Plugins{TrafficManagement: []v1alpha1.Plugin{{Name: "test/plugin", Version: "v1.0.0", Location: "https://test-path/v1.0.0"}}})
+			Expect(err).To(MatchError("plugin test/plugin must not set a version together with a location or an image"))
+
+			_, err = resolvePlugins(catalog, v1alpha1.Plugins{Step: []v1alpha1.Plugin{{Name: "test/plugin", Version: "v1.0.0", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v1", Path: "/plugin"}}}})
+			Expect(err).To(MatchError("plugin test/plugin must not set a version together with a location or an image"))
+		})
+
+		It("should return an error if neither location nor version is set", func() {
+			_, err := resolvePlugins(catalog, v1alpha1.Plugins{Metric: []v1alpha1.Plugin{{Name: "test/plugin"}}})
+			Expect(err).To(MatchError("plugin test/plugin must set either a location, a version, an image or architectures"))
+
+			Expect(validatePlugins(v1alpha1.RolloutManager{Spec: v1alpha1.RolloutManagerSpec{
+				Plugins: v1alpha1.Plugins{Metric: []v1alpha1.Plugin{{Name: "test/plugin"}}},
+			}})).To(MatchError("plugin test/plugin must set either a location, a version, an image or architectures"))
+		})
+	})
+
+	Context("reconciling a RolloutManager with catalog plugins", func() {
+
+		var (
+			ctx context.Context
+			rm  *v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			rm = makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.Plugins.TrafficManagement = []v1alpha1.Plugin{{Name: gatewayAPIPluginName, Version: "v0.4.0"}}
+			})
+			r = makeTestReconciler(rm)
+			r.PluginCatalogConfigMap = types.NamespacedName{Namespace: "operator-namespace", Name: "plugin-catalog"}
+			Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+
+			os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+		})
+
+		It("should use the embedded catalog when the catalog ConfigMap does not exist", func() {
+			_, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring(gatewayAPIPluginLocation))
+
+			clusterRole := &rbacv1.ClusterRole{}
+			Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsResourceName, clusterRole)).To(Succeed())
+			Expect(clusterRole.Rules).To(ContainElement(HaveField("APIGroups", ContainElement("gateway.networking.k8s.io"))))
+		})
+
+		It("should resolve plugins from the catalog ConfigMap, and report unknown versions in the status condition", func() {
+			catalogConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: r.PluginCatalogConfigMap.Name, Namespace: r.PluginCatalogConfigMap.Namespace},
+				Data: map[string]string{
+					PluginCatalogConfigMapKey: "plugins:\n- name: argoproj-labs/gatewayAPI\n  versions:\n  - version: v0.4.0\n    location: https://mirror.example.com/gatewayapi-plugin\n",
+				},
+			}
+			Expect(r.Client.Create(ctx, catalogConfigMap)).To(Succeed())
+
+			_, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring("https://mirror.example.com/gatewayapi-plugin"))
+
+			By("referencing a version that is not part of the catalog")
+			rm.Spec.Plugins.TrafficManagement[0].Version = "v99.0.0"
+			res, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).To(HaveOccurred())
+			Expect(res.condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonUnknownPluginVersion))
+			Expect(res.condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(res.condition.Message).To(ContainSubstring("plugin argoproj-labs/gatewayAPI version v99.0.0 is not part of the plugin catalog"))
+		})
+	})
+})
@@ -0,0 +1,135 @@
+package rollouts
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// pluginBinVolumeName is the name of the volume into which the Rollouts controller downloads its plugins.
+	pluginBinVolumeName = "plugin-bin"
+
+	// pluginBinMountPath is the path at which the plugin-bin volume is mounted, in the Rollouts controller container and in the plugin init containers.
+	pluginBinMountPath = "/home/argo-rollouts/plugin-bin"
+
+	// pluginImageInitContainerPrefix is the prefix of the names of the init containers that copy plugin executables from container images.
+	pluginImageInitContainerPrefix = "install-plugin-"
+)
+
+// invalidPluginNameCharacters matches the characters of a plugin name that are not allowed in a container name.
+var invalidPluginNameCharacters = regexp.MustCompile("[^a-z0-9]+")
+
+// getPluginImageName returns a name derived from the plugin name, which is used for both the init container and the copied executable of a plugin with an image source.
+func getPluginImageName(pluginName string) string {
+	name := strings.Trim(invalidPluginNameCharacters.ReplaceAllString(strings.ToLower(pluginName), "-"), "-")
+
+	// Container names are limited to 63 characters
+	if maxLength := 63 - len(pluginImageInitContainerPrefix); len(name) > maxLength {
+		name = strings.TrimRight(name[:maxLength], "-")
+	}
+
+	return pluginImageInitContainerPrefix + name
+}
+
+// getPluginLocation returns the location of the plugin executable that is written to the Rollouts ConfigMap: for plugins with an image source, this is the file copied by the init container.
+func getPluginLocation(plugin rolloutsmanagerv1alpha1.Plugin) string {
+	if plugin.Image != nil {
+		return "file://" + pluginBinMountPath + "/" + getPluginImageName(plugin.Name)
+	}
+	return plugin.Location
+}
+
+// validatePluginImages returns an error if the image sources of the plugins are invalid, or if the init containers of two plugins would have the same name.
+func validatePluginImages(plugins rolloutsmanagerv1alpha1.Plugins) error {
+
+	names := map[string]string{}
+
+	for _, pluginList := range [][]rolloutsmanagerv1alpha1.Plugin{plugins.TrafficManagement, plugins.Metric, plugins.Step} {
+		for _, plugin := range pluginList {
+			if plugin.Image == nil {
+				continue
+			}
+
+			if plugin.Location != "" {
+				return fmt.Errorf("plugin %s must not set both a location and an image", plugin.Name)
+			}
+
+			if plugin.Image.Reference == "" || plugin.Image.Path == "" {
+				return fmt.Errorf("plugin %s must set both the reference and the path of its image", plugin.Name)
+			}
+
+			if !path.IsAbs(plugin.Image.Path) || slices.Contains(strings.Split(plugin.Image.Path, "/"), "..") {
+				return fmt.Errorf("the path of the image of plugin %s must be an absolute path without '..' elements", plugin.Name)
+			}
+
+			name := getPluginImageName(plugin.Name)
+			if otherPlugin, exists := names[name]; exists && otherPlugin != plugin.Name {
+				return fmt.Errorf("plugins %s and %s cannot both use an image, as their names are too similar", otherPlugin, plugin.Name)
+			}
+			names[name] = plugin.Name
+		}
+	}
+
+	return nil
+}
+
+// getPluginImageInitContainers returns the init containers that copy the plugin executables from the image sources of the plugins into the plugin-bin volume.
+func getPluginImageInitContainers(cr rolloutsmanagerv1alpha1.RolloutManager) ([]corev1.Container, error) {
+
+	if err := validatePluginImages(cr.Spec.Plugins); err != nil {
+		return nil, err
+	}
+
+	imagePullPolicy, err := getImagePullPolicy(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	var initContainers []corev1.Container
+	added := map[string]bool{}
+
+	for _, pluginList := range [][]rolloutsmanagerv1alpha1.Plugin{cr.Spec.Plugins.TrafficManagement, cr.Spec.Plugins.Metric, cr.Spec.Plugins.Step} {
+		for _, plugin := range pluginList {
+			// The same plugin may be declared more than once, but is only installed once
+			name := getPluginImageName(plugin.Name)
+			if plugin.Image == nil || added[name] {
+				continue
+			}
+			added[name] = true
+
+			initContainers = append(initContainers, corev1.Container{
+				Name:            name,
+				Image:           plugin.Image.Reference,
+				ImagePullPolicy: imagePullPolicy,
+				Command:         []string{"cp", plugin.Image.Path, pluginBinMountPath + "/" + name},
+				SecurityContext: &corev1.SecurityContext{
+					Capabilities: &corev1.Capabilities{
+						Drop: []corev1.Capability{
+							"ALL",
+						},
+					},
+					AllowPrivilegeEscalation: boolPtr(false),
+					ReadOnlyRootFilesystem:   boolPtr(true),
+					RunAsNonRoot:             boolPtr(true),
+					SeccompProfile: &corev1.SeccompProfile{
+						Type: corev1.SeccompProfileTypeRuntimeDefault,
+					},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						MountPath: pluginBinMountPath,
+						Name:      pluginBinVolumeName,
+					},
+				},
+			})
+		}
+	}
+
+	return initContainers, nil
+}
@@ -0,0 +1,129 @@
+package rollouts
+
+import (
+	"context"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Plugin image tests", func() {
+
+	It("should derive valid container names from plugin names", func() {
+		Expect(getPluginImageName("argoproj-labs/gatewayAPI")).To(Equal("install-plugin-argoproj-labs-gatewayapi"))
+		Expect(getPluginImageName("example.com/My_Plugin")).To(Equal("install-plugin-example-com-my-plugin"))
+		Expect(len(getPluginImageName("argoproj-labs/a-very-long-plugin-name-that-does-not-fit-into-a-container-name"))).To(BeNumerically("<=", 63))
+	})
+
+	It("should set the location of a plugin with an image to the file copied by the init container", func() {
+		plugin := v1alpha1.Plugin{Name: "argoproj-labs/gatewayAPI", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v1", Path: "/bin/plugin"}}
+		Expect(getPluginLocation(plugin)).To(Equal("file:///home/argo-rollouts/plugin-bin/install-plugin-argoproj-labs-gatewayapi"))
+
+		plugin = v1alpha1.Plugin{Name: "argoproj-labs/gatewayAPI", Location: "https://test-path"}
+		Expect(getPluginLocation(plugin)).To(Equal("https://test-path"))
+	})
+
+	DescribeTable("should reject invalid image sources", func(plugins v1alpha1.Plugins, expectedError string) {
+		Expect(validatePluginImages(plugins)).To(MatchError(expectedError))
+		Expect(validatePlugins(v1alpha1.RolloutManager{Spec: v1alpha1.RolloutManagerSpec{Plugins: plugins}})).To(MatchError(expectedError))
+	},
+		Entry("with both a location and an image", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{
+			{Name: "test/plugin", Location: "https://test-path", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v1", Path: "/bin/plugin"}},
+		}}, "plugin test/plugin must not set both a location and an image"),
+		Entry("without a path", v1alpha1.Plugins{Step: []v1alpha1.Plugin{
+			{Name: "test/plugin", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v1"}},
+		}}, "plugin test/plugin must set both the reference and the path of its image"),
+		Entry("with a relative path", v1alpha1.Plugins{Step: []v1alpha1.Plugin{
+			{Name: "test/plugin", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v1", Path: "bin/plugin"}},
+		}}, "the path of the image of plugin test/plugin must be an absolute path without '..' elements"),
+		Entry("with a path that leaves its directory", v1alpha1.Plugins{Metric: []v1alpha1.Plugin{
+			{Name: "test/plugin", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v1", Path: "/bin/../etc/passwd"}},
+		}}, "the path of the image of plugin test/plugin must be an absolute path without '..' elements"),
+		Entry("with similar names", v1alpha1.Plugins{TrafficManagement: []v1alpha1.Plugin{
+			{Name: "test/plugin", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v1", Path: "/bin/plugin"}},
+			{Name: "test.plugin", Image: &v1alpha1.PluginImageSource{Reference: "quay.io/test/plugin:v2", Path: "/bin/plugin"}},
+		}}, "plugins test/plugin and test.plugin cannot both use an image, as their names are too similar"),
+	)
+
+	Context("reconciling a RolloutManager with plugin images", func() {
+
+		var (
+			ctx context.Context
+			a   v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+			sa  *corev1.ServiceAccount
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			a = *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.Plugins = v1alpha1.Plugins{
+					TrafficManagement: []v1alpha1.Plugin{
+						{Name: "argoproj-labs/gatewayAPI", Image: &v1alpha1.PluginImageSource{Reference: "registry.example.com/gatewayapi-plugin:v0.4.0", Path: "/bin/gatewayapi-plugin"}},
+					},
+					Metric: []v1alpha1.Plugin{
+						{Name: "argoproj-labs/sample-prometheus", Location: "https://test-path"},
+					},
+				}
+			})
+			r = makeTestReconciler(&a)
+			Expect(createNamespace(r, a.Namespace)).To(Succeed())
+
+			sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace}}
+			Expect(r.Client.Create(ctx, sa)).To(Succeed())
+		})
+
+		It("should add an init container that copies the plugin into plugin-bin, and write its file location into the ConfigMap", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+
+			initContainers := deployment.Spec.Template.Spec.InitContainers
+			Expect(initContainers).To(HaveLen(1))
+			Expect(initContainers[0].Name).To(Equal("install-plugin-argoproj-labs-gatewayapi"))
+			Expect(initContainers[0].Image).To(Equal("registry.example.com/gatewayapi-plugin:v0.4.0"))
+			Expect(initContainers[0].Command).To(Equal([]string{"cp", "/bin/gatewayapi-plugin", "/home/argo-rollouts/plugin-bin/install-plugin-argoproj-labs-gatewayapi"}))
+			Expect(initContainers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{{Name: "plugin-bin", MountPath: "/home/argo-rollouts/plugin-bin"}}))
+
+			Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+			configMap := &corev1.ConfigMap{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring("location: file:///home/argo-rollouts/plugin-bin/install-plugin-argoproj-labs-gatewayapi"))
+			Expect(configMap.Data[MetricPluginConfigMapKey]).To(ContainSubstring("location: https://test-path"))
+		})
+
+		It("should not update the Deployment when the API server sets the defaults of the init container", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+
+			By("simulating the defaults set by the API server on the init container")
+			deployment.Spec.Template.Spec.InitContainers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+			deployment.Spec.Template.Spec.InitContainers[0].TerminationMessagePolicy = corev1.TerminationMessageReadFile
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+			resourceVersion := deployment.ResourceVersion
+
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.ResourceVersion).To(Equal(resourceVersion))
+		})
+
+		It("should remove the init container when the plugin no longer uses an image", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			a.Spec.Plugins.TrafficManagement[0].Image = nil
+			a.Spec.Plugins.TrafficManagement[0].Location = "https://test-path/gatewayapi"
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.InitContainers).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,367 @@
+package rollouts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// pluginDownloadTimeout is the maximum duration of the download of a plugin executable by the operator.
+	pluginDownloadTimeout = 30 * time.Second
+
+	// pluginVerificationRetryInterval is the duration for which a failed or incomplete verification of a plugin location is cached, before it is retried.
+	pluginVerificationRetryInterval = time.Minute
+
+	// pluginVerificationTTL is the duration for which a successful verification is cached, before the plugin is downloaded again to detect a changed artifact.
+	pluginVerificationTTL = time.Hour
+
+	// defaultMaxPluginSize is the maximum size in bytes of a plugin executable downloaded by the operator.
+	defaultMaxPluginSize = 512 * 1024 * 1024
+)
+
+// errPluginVerificationFailed is returned when the preflight verification of at least one plugin has failed.
+var errPluginVerificationFailed = errors.New("plugin verification failed")
+
+// pluginVerification is the (cached) result of downloading the plugin executable at a location.
+type pluginVerification struct {
+	// sha256 is the checksum of the plugin executable, if it could be downloaded
+	sha256 string
+
+	// reason and message describe why the plugin executable could not be downloaded, when sha256 is empty
+	reason  string
+	message string
+
+	// expiry is the time after which the verification is repeated.
+	expiry time.Time
+}
+
+// pluginVerifier downloads plugin executables, and caches their checksums keyed by location and expected checksum.
+type pluginVerifier struct {
+	httpClient *http.Client
+
+	// k8sClient is used to list the Service CIDRs of the cluster, which the plugin verifier refuses to connect to. Ignored when nil.
+	k8sClient client.Client
+
+	// maxPluginSize is the maximum size in bytes of a downloaded plugin executable
+	maxPluginSize int64
+
+	mutex sync.Mutex
+	cache map[string]pluginVerification
+
+	// serviceCIDRs are the Service CIDRs of the cluster, which are listed again after serviceCIDRsExpiry
+	serviceCIDRs       []netip.Prefix
+	serviceCIDRsExpiry time.Time
+}
+
+func newPluginVerifier(k8sClient client.Client) *pluginVerifier {
+	v := &pluginVerifier{
+		k8sClient:     k8sClient,
+		maxPluginSize: defaultMaxPluginSize,
+		cache:         map[string]pluginVerification{},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect to the location on behalf of the operator, bypassing refuseInternalAddress
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: pluginDownloadTimeout, Control: v.refuseInternalAddress}).DialContext
+	v.httpClient = &http.Client{Transport: transport}
+
+	return v
+}
+
+// refuseInternalAddress prevents the plugin verifier from connecting to the loopback, link-local and private addresses of the operator Pod and the cluster, such as the cloud metadata endpoints
+// and the Services of the cluster, which are otherwise reachable through the location of a plugin.
+func (v *pluginVerifier) refuseInternalAddress(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("connections to %s are not allowed", host)
+	}
+	ip = ip.Unmap()
+
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || ip.IsPrivate() {
+		return fmt.Errorf("connections to %s are not allowed", host)
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	for _, serviceCIDR := range v.serviceCIDRs {
+		if serviceCIDR.Contains(ip) {
+			return fmt.Errorf("connections to %s are not allowed, as it is part of the Service CIDR %s", host, serviceCIDR)
+		}
+	}
+
+	return nil
+}
+
+// refreshServiceCIDRs lists the Service CIDRs of the cluster, from the ServiceCIDRs of Kubernetes 1.33 and later, if they were not listed within pluginVerificationTTL.
+// The address of the kubernetes Service is always included, as the ServiceCIDRs cannot be listed on earlier versions of Kubernetes.
+func (v *pluginVerifier) refreshServiceCIDRs(ctx context.Context) {
+
+	v.mutex.Lock()
+	expired := time.Now().After(v.serviceCIDRsExpiry)
+	v.mutex.Unlock()
+
+	if !expired {
+		return
+	}
+
+	serviceCIDRs := []netip.Prefix{}
+
+	if ip, err := netip.ParseAddr(os.Getenv("KUBERNETES_SERVICE_HOST")); err == nil {
+		serviceCIDRs = append(serviceCIDRs, netip.PrefixFrom(ip.Unmap(), ip.Unmap().BitLen()))
+	}
+
+	if v.k8sClient != nil {
+		serviceCIDRList := &networkingv1.ServiceCIDRList{}
+		if err := v.k8sClient.List(ctx, serviceCIDRList); err != nil {
+			log.Info(fmt.Sprintf("unable to list the ServiceCIDRs of the cluster, only the address of the kubernetes Service is refused by the plugin verifier: %v", err))
+		}
+
+		for _, serviceCIDR := range serviceCIDRList.Items {
+			for _, cidr := range serviceCIDR.Spec.CIDRs {
+				if prefix, err := netip.ParsePrefix(cidr); err == nil {
+					serviceCIDRs = append(serviceCIDRs, prefix.Masked())
+				}
+			}
+		}
+	}
+
+	v.mutex.Lock()
+	v.serviceCIDRs = serviceCIDRs
+	v.serviceCIDRsExpiry = time.Now().Add(pluginVerificationTTL)
+	v.mutex.Unlock()
+}
+
+// verifyLocation returns the checksum of the plugin executable at the location, or the reason it could not be computed.
+// Verifications are cached by location and expected checksum, so that a changed checksum of a plugin is verified again.
+func (v *pluginVerifier) verifyLocation(ctx context.Context, location string, expectedSHA256 string) pluginVerification {
+
+	key := location + "#" + strings.ToLower(expectedSHA256)
+
+	// The lock is not held during the download, so that a slow location does not block the verification of other plugins
+	v.mutex.Lock()
+	cached, exists := v.cache[key]
+	v.mutex.Unlock()
+
+	if exists && time.Now().Before(cached.expiry) {
+		return cached
+	}
+
+	v.refreshServiceCIDRs(ctx)
+
+	verification := v.download(ctx, location)
+	if verification.sha256 == "" {
+		verification.expiry = time.Now().Add(pluginVerificationRetryInterval)
+	} else {
+		verification.expiry = time.Now().Add(pluginVerificationTTL)
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	// Expired verifications are removed, so that the cache does not keep the locations of plugins that are no longer used
+	now := time.Now()
+	for cachedKey, cached := range v.cache {
+		if now.After(cached.expiry) {
+			delete(v.cache, cachedKey)
+		}
+	}
+	v.cache[key] = verification
+
+	return verification
+}
+
+// download computes the checksum of the plugin executable at the location, which must be an http(s):// URL.
+//
+// file:// locations are out of scope of the preflight verification: the file only exists within the Rollouts controller Pod, for example in a volume of .spec.volumes
+// or in the plugin-bin volume of a plugin image, and the operator must not read the files of its own Pod. They are reported as NotVerifiable.
+func (v *pluginVerifier) download(ctx context.Context, location string) pluginVerification {
+
+	if strings.HasPrefix(location, "file://") {
+		return pluginVerification{reason: rolloutsmanagerv1alpha1.PluginReasonNotVerifiable, message: "file:// locations are read by the Rollouts controller, and are not verified by the operator"}
+	}
+
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return pluginVerification{reason: rolloutsmanagerv1alpha1.PluginReasonDownloadFailed, message: "unsupported location, expected an http://, https:// or file:// location"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pluginDownloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return pluginVerification{reason: rolloutsmanagerv1alpha1.PluginReasonDownloadFailed, message: fmt.Sprintf("invalid location: %v", err)}
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		// The operator may not have the same network access as the Rollouts controller, so this does not mean that the plugin is broken
+		return pluginVerification{reason: rolloutsmanagerv1alpha1.PluginReasonNotVerifiable, message: fmt.Sprintf("unable to download the plugin: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return pluginVerification{reason: rolloutsmanagerv1alpha1.PluginReasonDownloadFailed, message: fmt.Sprintf("unexpected HTTP status %d when downloading the plugin", resp.StatusCode)}
+	}
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, io.LimitReader(resp.Body, v.maxPluginSize+1))
+	if err != nil {
+		return pluginVerification{reason: rolloutsmanagerv1alpha1.PluginReasonNotVerifiable, message: fmt.Sprintf("unable to download the plugin: %v", err)}
+	}
+	if size > v.maxPluginSize {
+		return pluginVerification{reason: rolloutsmanagerv1alpha1.PluginReasonDownloadFailed, message: fmt.Sprintf("the plugin exceeds the maximum size of %d bytes", v.maxPluginSize)}
+	}
+
+	return pluginVerification{sha256: hex.EncodeToString(hash.Sum(nil))}
+}
+
+// getPluginVerifier returns the plugin verifier of the reconciler, which is created on first use.
+func (r *RolloutManagerReconciler) getPluginVerifier() *pluginVerifier {
+	r.pluginVerifierOnce.Do(func() {
+		if r.pluginVerifier == nil {
+			r.pluginVerifier = newPluginVerifier(r.Client)
+		}
+	})
+	return r.pluginVerifier
+}
+
+// verifyPlugins verifies each plugin that is added to the Rollouts ConfigMap, when preflight verification is enabled via .spec.plugins.preflight.
+// It returns the status of each plugin, and an error wrapping errPluginVerificationFailed if a plugin is known to be broken.
+func (r *RolloutManagerReconciler) verifyPlugins(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) ([]rolloutsmanagerv1alpha1.RolloutManagerPluginStatus, error) {
+
+	if !cr.Spec.Plugins.Preflight {
+		return []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus{}, nil
+	}
+
+	type pluginWithType struct {
+		pluginType string
+		plugin     rolloutsmanagerv1alpha1.Plugin
+	}
+
+	plugins := []pluginWithType{}
+	openShiftRoutePlugin, err := r.getOpenShiftRoutePlugin(ctx, cr)
+	if err != nil {
+		return []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus{}, err
+	}
+	if openShiftRoutePlugin != nil {
+		plugins = append(plugins, pluginWithType{"trafficManagement", *openShiftRoutePlugin})
+	}
+	for _, plugin := range cr.Spec.Plugins.TrafficManagement {
+		plugins = append(plugins, pluginWithType{"trafficManagement", plugin})
+	}
+	for _, plugin := range cr.Spec.Plugins.Metric {
+		plugins = append(plugins, pluginWithType{"metric", plugin})
+	}
+	for _, plugin := range cr.Spec.Plugins.Step {
+		plugins = append(plugins, pluginWithType{"step", plugin})
+	}
+
+	statuses := []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus{}
+	failedPlugins := []string{}
+
+	for _, p := range plugins {
+
+		status := rolloutsmanagerv1alpha1.RolloutManagerPluginStatus{
+			Name:     p.plugin.Name,
+			Type:     p.pluginType,
+			Location: getPluginLocation(p.plugin),
+		}
+
+		condition := metav1.Condition{Type: rolloutsmanagerv1alpha1.PluginConditionTypeVerified}
+
+		if p.plugin.Image != nil {
+			// The executable is only copied from the image once the Rollouts controller Pod starts
+			condition.Status = metav1.ConditionUnknown
+			condition.Reason = rolloutsmanagerv1alpha1.PluginReasonNotVerifiable
+			condition.Message = fmt.Sprintf("the plugin is copied from image %s by an init container of the Rollouts controller Pod", p.plugin.Image.Reference)
+
+		} else if verification := r.getPluginVerifier().verifyLocation(ctx, status.Location, p.plugin.SHA256); verification.sha256 == "" {
+			condition.Reason = verification.reason
+			condition.Message = verification.message
+			if verification.reason == rolloutsmanagerv1alpha1.PluginReasonNotVerifiable {
+				condition.Status = metav1.ConditionUnknown
+			} else {
+				condition.Status = metav1.ConditionFalse
+				failedPlugins = append(failedPlugins, p.plugin.Name)
+			}
+
+		} else {
+			status.SHA256 = verification.sha256
+			if p.plugin.SHA256 != "" && !strings.EqualFold(p.plugin.SHA256, verification.sha256) {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = rolloutsmanagerv1alpha1.PluginReasonChecksumMismatch
+				condition.Message = fmt.Sprintf("expected SHA256 %s, but the plugin has SHA256 %s", p.plugin.SHA256, verification.sha256)
+				failedPlugins = append(failedPlugins, p.plugin.Name)
+			} else {
+				condition.Status = metav1.ConditionTrue
+				condition.Reason = rolloutsmanagerv1alpha1.PluginReasonVerified
+			}
+		}
+
+		meta.SetStatusCondition(&status.Conditions, condition)
+		statuses = append(statuses, status)
+	}
+
+	if len(failedPlugins) > 0 {
+		return statuses, fmt.Errorf("%w: %s, see .status.plugins for details", errPluginVerificationFailed, strings.Join(failedPlugins, ", "))
+	}
+
+	return statuses, nil
+}
+
+// getPluginStatuses returns the status of each plugin, as reported by verifyPlugins. Plugins verified during the reconciliation of the ConfigMap are returned from the cache.
+func (r *RolloutManagerReconciler) getPluginStatuses(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus {
+	statuses, _ := r.verifyPlugins(ctx, cr)
+	return statuses
+}
+
+// mergePluginStatuses returns the new plugin statuses, preserving the last transition time of the conditions that have not changed since the previous statuses.
+func mergePluginStatuses(previous []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus, statuses []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus) []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus {
+
+	merged := []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus{}
+
+	for _, status := range statuses {
+		conditions := []metav1.Condition{}
+		for _, previousStatus := range previous {
+			if previousStatus.Name == status.Name && previousStatus.Type == status.Type {
+				conditions = append(conditions, previousStatus.Conditions...)
+				break
+			}
+		}
+
+		for _, condition := range status.Conditions {
+			meta.SetStatusCondition(&conditions, condition)
+		}
+
+		status.Conditions = conditions
+		merged = append(merged, status)
+	}
+
+	return merged
+}
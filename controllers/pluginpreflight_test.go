@@ -0,0 +1,300 @@
+package rollouts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logger "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Plugin preflight tests", func() {
+
+	const pluginContent = "test-plugin-binary"
+
+	var (
+		ctx          context.Context
+		server       *httptest.Server
+		requests     atomic.Int32
+		pluginSHA256 string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests.Store(0)
+
+		sum := sha256.Sum256([]byte(pluginContent))
+		pluginSHA256 = hex.EncodeToString(sum[:])
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests.Add(1)
+			if req.URL.Path != "/plugin" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(pluginContent))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	getVerifiedCondition := func(statuses []v1alpha1.RolloutManagerPluginStatus, name string) *metav1.Condition {
+		for _, status := range statuses {
+			if status.Name == name {
+				return meta.FindStatusCondition(status.Conditions, v1alpha1.PluginConditionTypeVerified)
+			}
+		}
+		return nil
+	}
+
+	Context("verifying plugin locations", func() {
+
+		var verifier *pluginVerifier
+
+		BeforeEach(func() {
+			verifier = newPluginVerifier(nil)
+			// The test server listens on a loopback address, which the plugin verifier refuses to connect to
+			verifier.httpClient = server.Client()
+		})
+
+		It("should compute the checksum of a plugin served over HTTP, and cache it", func() {
+			verification := verifier.verifyLocation(ctx, server.URL+"/plugin", "")
+			Expect(verification.sha256).To(Equal(pluginSHA256))
+
+			Expect(verifier.verifyLocation(ctx, server.URL+"/plugin", "")).To(Equal(verification))
+			Expect(requests.Load()).To(Equal(int32(1)))
+
+			By("verifying the plugin again for another expected checksum")
+			Expect(verifier.verifyLocation(ctx, server.URL+"/plugin", pluginSHA256).sha256).To(Equal(pluginSHA256))
+			Expect(requests.Load()).To(Equal(int32(2)))
+		})
+
+		It("should download the plugin again once the cached verification has expired", func() {
+			Expect(verifier.verifyLocation(ctx, server.URL+"/plugin", "").expiry).To(BeTemporally("~", time.Now().Add(pluginVerificationTTL), time.Minute))
+
+			for key, verification := range verifier.cache {
+				verification.expiry = time.Now().Add(-time.Second)
+				verifier.cache[key] = verification
+			}
+
+			Expect(verifier.verifyLocation(ctx, server.URL+"/plugin", "").sha256).To(Equal(pluginSHA256))
+			Expect(requests.Load()).To(Equal(int32(2)))
+		})
+
+		It("should remove the expired verifications of other locations from the cache", func() {
+			verifier.cache["https://test-path/unused-plugin#"] = pluginVerification{sha256: pluginSHA256, expiry: time.Now().Add(-time.Second)}
+			verifier.cache["https://test-path/recent-plugin#"] = pluginVerification{sha256: pluginSHA256, expiry: time.Now().Add(time.Hour)}
+
+			verifier.verifyLocation(ctx, server.URL+"/plugin", "")
+
+			Expect(verifier.cache).ToNot(HaveKey("https://test-path/unused-plugin#"))
+			Expect(verifier.cache).To(HaveKey("https://test-path/recent-plugin#"))
+			Expect(verifier.cache).To(HaveKey(server.URL + "/plugin#"))
+		})
+
+		It("should report a failed download for an unexpected HTTP status", func() {
+			verification := verifier.verifyLocation(ctx, server.URL+"/missing", "")
+			Expect(verification.sha256).To(BeEmpty())
+			Expect(verification.reason).To(Equal(v1alpha1.PluginReasonDownloadFailed))
+			Expect(verification.message).To(ContainSubstring("unexpected HTTP status 404"))
+			Expect(verification.expiry).To(BeTemporally("~", time.Now().Add(pluginVerificationRetryInterval), time.Minute))
+		})
+
+		It("should report a failed download for a plugin that exceeds the maximum size", func() {
+			verifier.maxPluginSize = int64(len(pluginContent)) - 1
+
+			verification := verifier.verifyLocation(ctx, server.URL+"/plugin", "")
+			Expect(verification.sha256).To(BeEmpty())
+			Expect(verification.reason).To(Equal(v1alpha1.PluginReasonDownloadFailed))
+			Expect(verification.message).To(ContainSubstring("exceeds the maximum size"))
+		})
+
+		It("should stop the download when the context is cancelled", func() {
+			cancelledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			verification := verifier.verifyLocation(cancelledCtx, server.URL+"/plugin", "")
+			Expect(verification.sha256).To(BeEmpty())
+			Expect(verification.reason).To(Equal(v1alpha1.PluginReasonNotVerifiable))
+		})
+
+		It("should not read file:// plugins", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "plugin")
+			Expect(os.WriteFile(path, []byte(pluginContent), 0600)).To(Succeed())
+
+			verification := verifier.verifyLocation(ctx, "file://"+path, "")
+			Expect(verification.sha256).To(BeEmpty())
+			Expect(verification.reason).To(Equal(v1alpha1.PluginReasonNotVerifiable))
+			Expect(verification.message).ToNot(ContainSubstring(path))
+		})
+
+		It("should refuse to connect to loopback, link-local and private addresses", func() {
+			verification := newPluginVerifier(nil).verifyLocation(ctx, server.URL+"/plugin", "")
+			Expect(verification.sha256).To(BeEmpty())
+			Expect(verification.message).To(ContainSubstring("are not allowed"))
+			Expect(requests.Load()).To(BeZero())
+
+			verifier := newPluginVerifier(nil)
+			Expect(verifier.refuseInternalAddress("tcp", "169.254.169.254:80", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "[::1]:443", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "[::ffff:127.0.0.1]:443", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "10.0.12.3:443", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "172.30.0.1:443", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "192.168.1.10:80", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "[fd00::1]:443", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "140.82.112.3:443", nil)).To(Succeed())
+		})
+
+		It("should refuse to connect to the Service CIDRs of the cluster", func() {
+			r := makeTestReconciler(&networkingv1.ServiceCIDR{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubernetes"},
+				Spec:       networkingv1.ServiceCIDRSpec{CIDRs: []string{"198.18.0.0/16", "2001:db8:1::/112"}},
+			})
+			GinkgoT().Setenv("KUBERNETES_SERVICE_HOST", "203.0.113.1")
+
+			verifier := newPluginVerifier(r.Client)
+			verifier.refreshServiceCIDRs(ctx)
+
+			Expect(verifier.refuseInternalAddress("tcp", "198.18.4.2:443", nil)).To(MatchError(ContainSubstring("Service CIDR 198.18.0.0/16")))
+			Expect(verifier.refuseInternalAddress("tcp", "[2001:db8:1::a]:443", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "203.0.113.1:443", nil)).To(HaveOccurred())
+			Expect(verifier.refuseInternalAddress("tcp", "203.0.113.2:443", nil)).To(Succeed())
+		})
+
+		It("should not download plugins via the proxy of the operator", func() {
+			transport, ok := newPluginVerifier(nil).httpClient.Transport.(*http.Transport)
+			Expect(ok).To(BeTrue())
+			Expect(transport.Proxy).To(BeNil())
+		})
+
+		It("should report an unsupported location as a failed download", func() {
+			Expect(verifier.verifyLocation(ctx, "ftp://test-path", "").reason).To(Equal(v1alpha1.PluginReasonDownloadFailed))
+		})
+	})
+
+	Context("reconciling a RolloutManager with preflight enabled", func() {
+
+		var (
+			rm *v1alpha1.RolloutManager
+			r  *RolloutManagerReconciler
+		)
+
+		BeforeEach(func() {
+			rm = makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.Plugins = v1alpha1.Plugins{
+					Preflight: true,
+					Metric:    []v1alpha1.Plugin{{Name: "test/metric", Location: server.URL + "/plugin", SHA256: pluginSHA256}},
+				}
+			})
+			r = makeTestReconciler(rm)
+			r.pluginVerifier = newPluginVerifier(r.Client)
+			r.pluginVerifier.httpClient = server.Client()
+			Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+
+			os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+		})
+
+		It("should not verify plugins when preflight is disabled", func() {
+			rm.Spec.Plugins.Preflight = false
+
+			statuses, err := r.verifyPlugins(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statuses).To(BeEmpty())
+			Expect(requests.Load()).To(BeZero())
+		})
+
+		It("should report verified plugins in the status", func() {
+			res, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests.Load()).To(Equal(int32(1)), "the plugin should only be downloaded once per reconciliation")
+
+			Expect(updateStatusConditionOfRolloutManager(ctx, res, rm, r.Client, logger.FromContext(ctx))).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, rm.Name, rm)).To(Succeed())
+
+			Expect(rm.Status.Plugins).To(HaveLen(2))
+			Expect(rm.Status.Plugins[1].Name).To(Equal("test/metric"))
+			Expect(rm.Status.Plugins[1].Type).To(Equal("metric"))
+			Expect(rm.Status.Plugins[1].SHA256).To(Equal(pluginSHA256))
+
+			condition := getVerifiedCondition(rm.Status.Plugins, "test/metric")
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(v1alpha1.PluginReasonVerified))
+
+			By("reporting the OpenShift Route plugin, which is only available within the Rollouts controller Pod, as not verifiable")
+			Expect(rm.Status.Plugins[0].Name).To(Equal(OpenShiftRolloutPluginName))
+			Expect(getVerifiedCondition(rm.Status.Plugins, OpenShiftRolloutPluginName).Status).To(Equal(metav1.ConditionUnknown))
+		})
+
+		It("should refuse to update the ConfigMap with a plugin whose checksum does not match", func() {
+			Expect(r.reconcileConfigMap(ctx, *rm)).To(Succeed())
+
+			rm.Spec.Plugins.Metric = append(rm.Spec.Plugins.Metric, v1alpha1.Plugin{Name: "test/other-metric", Location: server.URL + "/plugin", SHA256: "0000"})
+
+			res, err := r.reconcileRolloutsManager(ctx, *rm)
+			Expect(errors.Is(err, errPluginVerificationFailed)).To(BeTrue())
+			Expect(res.condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(res.condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonPluginVerificationFailed))
+			Expect(res.condition.Message).To(ContainSubstring("test/other-metric"))
+
+			Expect(getVerifiedCondition(res.plugins, "test/metric").Status).To(Equal(metav1.ConditionTrue))
+			Expect(getVerifiedCondition(res.plugins, "test/other-metric").Status).To(Equal(metav1.ConditionFalse))
+			Expect(getVerifiedCondition(res.plugins, "test/other-metric").Reason).To(Equal(v1alpha1.PluginReasonChecksumMismatch))
+
+			By("verifying that the ConfigMap still only contains the previous plugin")
+			configMap := &corev1.ConfigMap{}
+			Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[MetricPluginConfigMapKey]).To(ContainSubstring("test/metric"))
+			Expect(configMap.Data[MetricPluginConfigMapKey]).ToNot(ContainSubstring("test/other-metric"))
+		})
+
+		It("should refuse a plugin that cannot be downloaded, but not a plugin that the operator cannot reach", func() {
+			rm.Spec.Plugins.Step = []v1alpha1.Plugin{{Name: "test/step", Location: "file:///non-existent/plugin"}}
+
+			statuses, err := r.verifyPlugins(ctx, *rm)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(getVerifiedCondition(statuses, "test/step").Status).To(Equal(metav1.ConditionUnknown))
+			Expect(getVerifiedCondition(statuses, "test/step").Reason).To(Equal(v1alpha1.PluginReasonNotVerifiable))
+
+			rm.Spec.Plugins.Step[0].Location = server.URL + "/missing"
+			statuses, err = r.verifyPlugins(ctx, *rm)
+			Expect(err).To(MatchError(ContainSubstring("test/step")))
+			Expect(getVerifiedCondition(statuses, "test/step").Reason).To(Equal(v1alpha1.PluginReasonDownloadFailed))
+		})
+	})
+
+	It("should preserve the last transition time of unchanged plugin conditions", func() {
+		lastTransitionTime := metav1.NewTime(metav1.Now().Add(-3600 * 1e9))
+
+		previous := []v1alpha1.RolloutManagerPluginStatus{{
+			Name: "test/metric", Type: "metric",
+			Conditions: []metav1.Condition{{Type: v1alpha1.PluginConditionTypeVerified, Status: metav1.ConditionTrue, Reason: v1alpha1.PluginReasonVerified, LastTransitionTime: lastTransitionTime}},
+		}}
+		statuses := []v1alpha1.RolloutManagerPluginStatus{{
+			Name: "test/metric", Type: "metric",
+			Conditions: []metav1.Condition{{Type: v1alpha1.PluginConditionTypeVerified, Status: metav1.ConditionTrue, Reason: v1alpha1.PluginReasonVerified, LastTransitionTime: metav1.Now()}},
+		}}
+
+		Expect(mergePluginStatuses(previous, statuses)).To(Equal(previous))
+	})
+})
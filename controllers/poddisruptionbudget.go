@@ -0,0 +1,92 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// validatePodDisruptionBudget returns an error if .spec.ha.podDisruptionBudget of the RolloutManager is invalid.
+func validatePodDisruptionBudget(cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	if cr.Spec.HA == nil || cr.Spec.HA.PodDisruptionBudget == nil {
+		return nil
+	}
+
+	if cr.Spec.HA.PodDisruptionBudget.MinAvailable != nil && cr.Spec.HA.PodDisruptionBudget.MaxUnavailable != nil {
+		return fmt.Errorf("invalid .spec.ha.podDisruptionBudget: only one of minAvailable and maxUnavailable may be set")
+	}
+
+	return nil
+}
+
+// generateDesiredRolloutsPodDisruptionBudget returns the PodDisruptionBudget of the Rollouts controller pods.
+func generateDesiredRolloutsPodDisruptionBudget(cr rolloutsmanagerv1alpha1.RolloutManager) *policyv1.PodDisruptionBudget {
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsResourceName,
+			Namespace: cr.Namespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName,
+				},
+			},
+		},
+	}
+	setRolloutsLabelsAndAnnotationsToObject(&pdb.ObjectMeta, cr)
+
+	if spec := cr.Spec.HA.PodDisruptionBudget; spec != nil && (spec.MinAvailable != nil || spec.MaxUnavailable != nil) {
+		pdb.Spec.MinAvailable = spec.MinAvailable
+		pdb.Spec.MaxUnavailable = spec.MaxUnavailable
+	} else {
+		// By default, at least one of the HA replicas must remain available
+		minAvailable := intstr.FromInt32(1)
+		pdb.Spec.MinAvailable = &minAvailable
+	}
+
+	return pdb
+}
+
+// reconcileRolloutsPodDisruptionBudget reconciles the PodDisruptionBudget of the Rollouts controller, which exists only while HA is enabled.
+func (r *RolloutManagerReconciler) reconcileRolloutsPodDisruptionBudget(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	if cr.Spec.HA == nil || !cr.Spec.HA.Enabled {
+		return r.deleteRolloutsPodDisruptionBudget(ctx, cr)
+	}
+
+	if err := validatePodDisruptionBudget(cr); err != nil {
+		return err
+	}
+
+	desired := generateDesiredRolloutsPodDisruptionBudget(cr)
+
+	if err := controllerutil.SetControllerReference(&cr, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on PodDisruptionBudget %s: %w", desired.Name, err)
+	}
+
+	return r.applyObject(ctx, desired)
+}
+
+// deleteRolloutsPodDisruptionBudget deletes the PodDisruptionBudget of the Rollouts controller, if it exists.
+func (r *RolloutManagerReconciler) deleteRolloutsPodDisruptionBudget(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	existing := &policyv1.PodDisruptionBudget{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get PodDisruptionBudget %s: %w", DefaultArgoRolloutsResourceName, err)
+	}
+
+	log.Info(fmt.Sprintf("Deleting PodDisruptionBudget %s because HA is disabled", existing.Name))
+	return r.Client.Delete(ctx, existing)
+}
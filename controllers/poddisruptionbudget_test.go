@@ -0,0 +1,81 @@
+package rollouts
+
+import (
+	"context"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("PodDisruptionBudget tests", func() {
+
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true}
+		})
+		r = makeTestReconciler(&a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+	})
+
+	It("should create a PodDisruptionBudget with minAvailable 1 by default when HA is enabled, and delete it when HA is disabled", func() {
+		Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+
+		pdb := &policyv1.PodDisruptionBudget{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).To(Succeed())
+		Expect(pdb.Spec.MinAvailable).To(Equal(&intstr.IntOrString{Type: intstr.Int, IntVal: 1}))
+		Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName}))
+		Expect(pdb.OwnerReferences).To(HaveLen(1))
+
+		a.Spec.HA.Enabled = false
+		Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+		err := fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should use the configured maxUnavailable, and repair drift", func() {
+		maxUnavailable := intstr.FromString("50%")
+		a.Spec.HA.PodDisruptionBudget = &v1alpha1.RolloutManagerPodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable}
+		Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+
+		pdb := &policyv1.PodDisruptionBudget{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).To(Succeed())
+		Expect(pdb.Spec.MaxUnavailable).To(Equal(&maxUnavailable))
+		Expect(pdb.Spec.MinAvailable).To(BeNil())
+		resourceVersion := pdb.ResourceVersion
+
+		By("not updating the PodDisruptionBudget when it is up to date")
+		Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).To(Succeed())
+		Expect(pdb.ResourceVersion).To(Equal(resourceVersion))
+
+		By("reverting a modification of the PodDisruptionBudget")
+		modifiedMaxUnavailable := intstr.FromInt32(0)
+		pdb.Spec.MaxUnavailable = &modifiedMaxUnavailable
+		Expect(r.Client.Update(ctx, pdb)).To(Succeed())
+
+		Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).To(Succeed())
+		Expect(pdb.Spec.MaxUnavailable).To(Equal(&maxUnavailable))
+		Expect(pdb.Spec.MinAvailable).To(BeNil())
+	})
+
+	It("should reject a PodDisruptionBudget that sets both minAvailable and maxUnavailable", func() {
+		value := intstr.FromInt32(1)
+		a.Spec.HA.PodDisruptionBudget = &v1alpha1.RolloutManagerPodDisruptionBudgetSpec{MinAvailable: &value, MaxUnavailable: &value}
+
+		Expect(validatePodDisruptionBudget(a)).To(MatchError("invalid .spec.ha.podDisruptionBudget: only one of minAvailable and maxUnavailable may be set"))
+		Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).ToNot(Succeed())
+	})
+})
@@ -2,8 +2,10 @@ package rollouts
 
 import (
 	"context"
+	"errors"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -19,6 +21,36 @@ type reconcileStatusResult struct {
 
 	// phase: if non-nil, .status.phase will be set to this value, after call to reconcileRolloutsManager
 	phase *rolloutsmanagerv1alpha1.RolloutControllerPhase
+
+	// dashboard: if non-nil, .status.dashboard will be set to this value, after call to reconcileRolloutsManager
+	dashboard *rolloutsmanagerv1alpha1.RolloutControllerPhase
+
+	// controller: if non-nil, .status.controller will be set to this value, after call to reconcileRolloutsManager
+	controller *rolloutsmanagerv1alpha1.RolloutManagerControllerSpec
+
+	// policyRules: if non-nil, .status.policyRules will be set to this value, after call to reconcileRolloutsManager
+	policyRules []rbacv1.PolicyRule
+
+	// plugins: if non-nil, .status.plugins will be set to this value, after call to reconcileRolloutsManager
+	plugins []rolloutsmanagerv1alpha1.RolloutManagerPluginStatus
+
+	// leader: if non-nil, .status.leader will be set to this value, after call to reconcileRolloutsManager. An empty value removes .status.leader.
+	leader *rolloutsmanagerv1alpha1.RolloutManagerLeaderStatus
+
+	// workload: if non-nil, the image, scope and replicas of .status will be set to this value, after call to reconcileRolloutsManager
+	workload *rolloutsmanagerv1alpha1.RolloutManagerWorkloadStatus
+
+	// plannedChanges: if non-nil, .status.plannedChanges will be set to this value, after call to reconcileRolloutsManager. An empty value removes .status.plannedChanges.
+	plannedChanges []rolloutsmanagerv1alpha1.RolloutManagerPlannedChange
+
+	// conditions: conditions other than the Reconciled condition, to be set on RolloutManager's .status.conditions
+	conditions []metav1.Condition
+
+	// removedConditions: types of the conditions to be removed from RolloutManager's .status.conditions
+	removedConditions []string
+
+	// generationNotReconciled: if true, .status.observedGeneration is left unchanged, as the resources were not reconciled against the current .spec (for example, while the RolloutManager is paused)
+	generationNotReconciled bool
 }
 
 func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (reconcileStatusResult, error) {
@@ -51,62 +83,114 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
-	log.Info("reconciling Rollouts ServiceAccount")
-	sa, err := r.reconcileRolloutsServiceAccount(ctx, cr)
+	log.Info("resolving plugins from the plugin catalog")
+	catalog, err := r.getPluginCatalog(ctx)
 	if err != nil {
-		log.Error(err, "failed to reconcile Rollout's ServiceAccount.")
+		log.Error(err, "failed to get the plugin catalog.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
-
-	var role *rbacv1.Role
-	var clusterRole *rbacv1.ClusterRole
-
-	if cr.Spec.NamespaceScoped {
-		log.Info("reconciling Rollouts Roles")
-		role, err = r.reconcileRolloutsRole(ctx, cr)
-		if err != nil {
-			log.Error(err, "failed to reconcile Rollout's Role.")
-			return wrapCondition(createCondition(err.Error())), err
-		}
-	} else {
-		log.Info("reconciling Rollouts ClusterRoles")
-		clusterRole, err = r.reconcileRolloutsClusterRole(ctx, cr)
-		if err != nil {
-			log.Error(err, "failed to reconcile Rollout's ClusterRoles.")
-			return wrapCondition(createCondition(err.Error())), err
+	if cr.Spec.Plugins, err = resolvePlugins(catalog, cr.Spec.Plugins); err != nil {
+		log.Error(err, "failed to resolve plugins from the plugin catalog.")
+		if errors.Is(err, errUnknownPluginVersion) {
+			return wrapCondition(createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonUnknownPluginVersion)), err
 		}
+		return wrapCondition(createCondition(err.Error())), err
 	}
 
-	log.Info("reconciling aggregate-to-admin ClusterRole")
-	if err := r.reconcileRolloutsAggregateToAdminClusterRole(ctx, cr); err != nil {
-		log.Error(err, "failed to reconcile Rollout's aggregate-to-admin ClusterRoles.")
+	openShiftRoutePlugin, err := lookupOpenShiftRoutePlugin(cr, catalog, r.OpenShiftRoutePluginLocation)
+	if err != nil {
+		log.Error(err, "failed to resolve the OpenShift Route plugin from the plugin catalog.")
+		if errors.Is(err, errUnknownPluginVersion) {
+			return wrapCondition(createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonUnknownPluginVersion)), err
+		}
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
-	log.Info("reconciling aggregate-to-edit ClusterRole")
-	if err := r.reconcileRolloutsAggregateToEditClusterRole(ctx, cr); err != nil {
-		log.Error(err, "failed to reconcile Rollout's aggregate-to-edit ClusterRoles.")
+	log.Info("resolving plugin architectures")
+	if arch, err := getPluginArchitecture(cr, openShiftRoutePlugin); err != nil {
+		log.Error(err, "failed to resolve plugin architectures.")
+		if errors.Is(err, errUnsupportedPluginArchitecture) {
+			return wrapCondition(createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonUnsupportedPluginArchitecture)), err
+		}
 		return wrapCondition(createCondition(err.Error())), err
+	} else if arch != "" {
+		cr = resolvePluginArchitectures(cr, arch)
 	}
 
-	log.Info("reconciling aggregate-to-view ClusterRole")
-	if err := r.reconcileRolloutsAggregateToViewClusterRole(ctx, cr); err != nil {
-		log.Error(err, "failed to reconcile Rollout's aggregate-to-view ClusterRoles.")
-		return wrapCondition(createCondition(err.Error())), err
+	log.Info("validating overrides")
+	if err := validateOverrides(cr); err != nil {
+		log.Error(err, "invalid overrides.")
+		return wrapCondition(createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidOverride)), err
 	}
 
-	if cr.Spec.NamespaceScoped {
-		log.Info("reconciling Rollouts RoleBindings")
-		if err := r.reconcileRolloutsRoleBinding(ctx, cr, role, sa); err != nil {
-			log.Error(err, "failed to reconcile Rollout's RoleBindings.")
+	// Collect the conflicts with other field managers that are found while applying the resources below
+	ctx, conflicts := withApplyConflicts(ctx)
+
+	var sa *corev1.ServiceAccount
+	if isComponentUnmanaged(cr, rolloutsmanagerv1alpha1.ComponentRBAC) {
+		log.Info("skipping Rollouts ServiceAccount and RBAC, which are unmanaged")
+
+		// The Deployment only references the ServiceAccount by its name
+		sa = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: cr.Namespace}}
+	} else {
+		var err error
+		var role *rbacv1.Role
+		var clusterRole *rbacv1.ClusterRole
+
+		log.Info("reconciling Rollouts ServiceAccount")
+		sa, err = r.reconcileRolloutsServiceAccount(ctx, cr)
+		if err != nil {
+			log.Error(err, "failed to reconcile Rollout's ServiceAccount.")
 			return wrapCondition(createCondition(err.Error())), err
 		}
-	} else {
-		log.Info("reconciling Rollouts ClusterRoleBinding")
-		if err := r.reconcileRolloutsClusterRoleBinding(ctx, clusterRole, sa, cr); err != nil {
-			log.Error(err, "failed to reconcile Rollout's ClusterRoleBinding.")
+
+		if cr.Spec.NamespaceScoped {
+			log.Info("reconciling Rollouts Roles")
+			role, err = r.reconcileRolloutsRole(ctx, cr)
+			if err != nil {
+				log.Error(err, "failed to reconcile Rollout's Role.")
+				return wrapCondition(createCondition(err.Error())), err
+			}
+		} else {
+			log.Info("reconciling Rollouts ClusterRoles")
+			clusterRole, err = r.reconcileRolloutsClusterRole(ctx, cr)
+			if err != nil {
+				log.Error(err, "failed to reconcile Rollout's ClusterRoles.")
+				return wrapCondition(createCondition(err.Error())), err
+			}
+		}
+
+		log.Info("reconciling aggregate-to-admin ClusterRole")
+		if err := r.reconcileRolloutsAggregateToAdminClusterRole(ctx, cr); err != nil {
+			log.Error(err, "failed to reconcile Rollout's aggregate-to-admin ClusterRoles.")
+			return wrapCondition(createCondition(err.Error())), err
+		}
+
+		log.Info("reconciling aggregate-to-edit ClusterRole")
+		if err := r.reconcileRolloutsAggregateToEditClusterRole(ctx, cr); err != nil {
+			log.Error(err, "failed to reconcile Rollout's aggregate-to-edit ClusterRoles.")
+			return wrapCondition(createCondition(err.Error())), err
+		}
+
+		log.Info("reconciling aggregate-to-view ClusterRole")
+		if err := r.reconcileRolloutsAggregateToViewClusterRole(ctx, cr); err != nil {
+			log.Error(err, "failed to reconcile Rollout's aggregate-to-view ClusterRoles.")
 			return wrapCondition(createCondition(err.Error())), err
 		}
+
+		if cr.Spec.NamespaceScoped {
+			log.Info("reconciling Rollouts RoleBindings")
+			if err := r.reconcileRolloutsRoleBinding(ctx, cr, role, sa); err != nil {
+				log.Error(err, "failed to reconcile Rollout's RoleBindings.")
+				return wrapCondition(createCondition(err.Error())), err
+			}
+		} else {
+			log.Info("reconciling Rollouts ClusterRoleBinding")
+			if err := r.reconcileRolloutsClusterRoleBinding(ctx, clusterRole, sa, cr); err != nil {
+				log.Error(err, "failed to reconcile Rollout's ClusterRoleBinding.")
+				return wrapCondition(createCondition(err.Error())), err
+			}
+		}
 	}
 
 	log.Info("reconciling Rollouts Secret")
@@ -115,27 +199,70 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
-	log.Info("reconciling ConfigMap for plugins")
-	if err := r.reconcileConfigMap(ctx, cr); err != nil {
-		log.Error(err, "failed to reconcile Rollout's ConfigMap.")
+	log.Info("reconciling Rollouts notifications")
+	if err := r.reconcileRolloutsNotifications(ctx, cr); err != nil {
+		log.Error(err, "failed to reconcile Rollout's notifications.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
-	log.Info("reconciling Rollouts Deployment")
-	if err := r.reconcileRolloutsDeployment(ctx, cr, *sa); err != nil {
-		log.Error(err, "failed to reconcile Rollout's Deployment.")
-		return wrapCondition(createCondition(err.Error())), err
+	if isComponentUnmanaged(cr, rolloutsmanagerv1alpha1.ComponentConfigMap) {
+		log.Info("skipping ConfigMap for plugins, which is unmanaged")
+	} else {
+		log.Info("reconciling ConfigMap for plugins")
+		if err := r.reconcileConfigMap(ctx, cr); err != nil {
+			log.Error(err, "failed to reconcile Rollout's ConfigMap.")
+			if errors.Is(err, errPluginVerificationFailed) {
+				rr := wrapCondition(createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonPluginVerificationFailed))
+				rr.plugins = r.getPluginStatuses(ctx, cr)
+				return rr, err
+			}
+			return wrapCondition(createCondition(err.Error())), err
+		}
 	}
 
-	log.Info("reconciling Rollouts Metrics Service")
-	if err := r.reconcileRolloutsMetricsServiceAndMonitor(ctx, cr); err != nil {
-		log.Error(err, "failed to reconcile Rollout's Metrics Service.")
-		return wrapCondition(createCondition(err.Error())), err
+	if isComponentUnmanaged(cr, rolloutsmanagerv1alpha1.ComponentDeployment) {
+		log.Info("skipping Rollouts Deployment, which is unmanaged")
+	} else {
+		log.Info("reconciling Rollouts Deployment")
+		if err := r.reconcileRolloutsDeployment(ctx, cr, *sa); err != nil {
+			log.Error(err, "failed to reconcile Rollout's Deployment.")
+			return wrapCondition(createCondition(err.Error())), err
+		}
+	}
+
+	if isComponentUnmanaged(cr, rolloutsmanagerv1alpha1.ComponentDeployment) {
+		log.Info("skipping Rollouts PodDisruptionBudget, as the Deployment is unmanaged")
+	} else {
+		log.Info("reconciling Rollouts PodDisruptionBudget")
+		if err := r.reconcileRolloutsPodDisruptionBudget(ctx, cr); err != nil {
+			log.Error(err, "failed to reconcile Rollout's PodDisruptionBudget.")
+			return wrapCondition(createCondition(err.Error())), err
+		}
+	}
+
+	if isComponentUnmanaged(cr, rolloutsmanagerv1alpha1.ComponentMetricsService) {
+		log.Info("skipping Rollouts Metrics Service, which is unmanaged")
+	} else {
+		log.Info("reconciling Rollouts Metrics Service")
+		if err := r.reconcileRolloutsMetricsServiceAndMonitor(ctx, cr); err != nil {
+			log.Error(err, "failed to reconcile Rollout's Metrics Service.")
+			return wrapCondition(createCondition(err.Error())), err
+		}
+	}
+
+	if isComponentUnmanaged(cr, rolloutsmanagerv1alpha1.ComponentNetworkPolicy) {
+		log.Info("skipping Rollouts NetworkPolicy, which is unmanaged")
+	} else {
+		log.Info("reconciling Rollouts NetworkPolicy")
+		if err := r.reconcileRolloutsNetworkPolicy(ctx, cr); err != nil {
+			log.Error(err, "failed to reconcile Rollout's NetworkPolicy.")
+			return wrapCondition(createCondition(err.Error())), err
+		}
 	}
 
-	log.Info("reconciling Rollouts NetworkPolicy")
-	if err := r.reconcileRolloutsNetworkPolicy(ctx, cr); err != nil {
-		log.Error(err, "failed to reconcile Rollout's NetworkPolicy.")
+	log.Info("reconciling Rollouts Dashboard")
+	if err := r.reconcileRolloutsDashboard(ctx, cr); err != nil {
+		log.Error(err, "failed to reconcile Rollout's Dashboard.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
@@ -147,5 +274,28 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 	}
 	rr.condition = createCondition("") // success
 
+	// The command arguments were already validated when reconciling the Deployment, above
+	if args, err := getRolloutsCommandArgs(cr); err == nil {
+		rr.controller = getEffectiveControllerSettings(args)
+	}
+
+	rr.policyRules = getPolicyRules(cr)
+	rr.plugins = r.getPluginStatuses(ctx, cr)
+	rr.conditions = append(rr.conditions, conflicts.getFieldManagerConflictCondition())
+
+	// A suspended Rollouts controller does not renew the leader election Lease
+	if isHAEnabled(cr) && !isSuspended(cr) {
+		leader, leaderCondition, err := r.getLeaderStatus(ctx, cr)
+		if err != nil {
+			log.Error(err, "failed to determine the leader of the Rollouts controller.")
+			return wrapCondition(createCondition(err.Error())), err
+		}
+		rr.leader = leader
+		rr.conditions = append(rr.conditions, leaderCondition)
+	} else {
+		rr.leader = &rolloutsmanagerv1alpha1.RolloutManagerLeaderStatus{}
+		rr.removedConditions = append(rr.removedConditions, rolloutsmanagerv1alpha1.RolloutManagerConditionTypeLeaderLeaseStale)
+	}
+
 	return rr, nil
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -28,39 +30,19 @@ func (r *RolloutManagerReconciler) reconcileRolloutsServiceAccount(ctx context.C
 	}
 	setRolloutsLabelsAndAnnotationsToObject(&expectedServiceAccount.ObjectMeta, cr)
 
-	liveServiceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: expectedServiceAccount.Name, Namespace: expectedServiceAccount.Namespace}}
-	if err := fetchObject(ctx, r.Client, cr.Namespace, liveServiceAccount.Name, liveServiceAccount); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to get the ServiceAccount associated with %s: %w", liveServiceAccount.Name, err)
-		}
-
-		if err := controllerutil.SetControllerReference(&cr, expectedServiceAccount, r.Scheme); err != nil {
-			return nil, err
-		}
-
-		log.Info(fmt.Sprintf("Creating ServiceAccount %s", expectedServiceAccount.Name))
-		return expectedServiceAccount, r.Client.Create(ctx, expectedServiceAccount)
+	if err := applyOverrides(cr, overrideKindServiceAccount, expectedServiceAccount.Name, expectedServiceAccount); err != nil {
+		return nil, err
 	}
 
-	updateNeeded := false
-
-	normalizedLiveServiceAccount := liveServiceAccount.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveServiceAccount.ObjectMeta, cr)
-
-	if !areStringMapsEqual(normalizedLiveServiceAccount.Labels, expectedServiceAccount.Labels) || !areStringMapsEqual(normalizedLiveServiceAccount.Annotations, expectedServiceAccount.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of ServiceAccount %s do not match the expected state, hence updating it", liveServiceAccount.Name))
-
-		liveServiceAccount.Labels = combineStringMaps(liveServiceAccount.Labels, expectedServiceAccount.Labels)
-		liveServiceAccount.Annotations = combineStringMaps(liveServiceAccount.Annotations, expectedServiceAccount.Annotations)
+	if err := controllerutil.SetControllerReference(&cr, expectedServiceAccount, r.Scheme); err != nil {
+		return nil, err
 	}
 
-	if updateNeeded {
-		// Update if the Role already exists and needs to be modified
-		return liveServiceAccount, r.Client.Update(ctx, liveServiceAccount)
+	if err := r.applyObject(ctx, expectedServiceAccount); err != nil {
+		return nil, err
 	}
 
-	return liveServiceAccount, nil
+	return expectedServiceAccount, nil
 }
 
 // Reconciles Rollouts Role.
@@ -74,58 +56,35 @@ func (r *RolloutManagerReconciler) reconcileRolloutsRole(ctx context.Context, cr
 		}
 	}
 
-	expectedPolicyRules := GetPolicyRules()
+	if err := validatePluginPolicyRules(cr); err != nil {
+		return nil, err
+	}
+
 	expectedRole := &rbacv1.Role{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      DefaultArgoRolloutsResourceName,
 			Namespace: cr.Namespace,
 		},
+		Rules: getPolicyRules(cr),
 	}
 	setRolloutsLabelsAndAnnotationsToObject(&expectedRole.ObjectMeta, cr)
 
-	liveRole := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: expectedRole.Name, Namespace: expectedRole.Namespace}}
-
-	if err := fetchObject(ctx, r.Client, cr.Namespace, liveRole.Name, liveRole); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to reconcile the Role for the ServiceAccount associated with %s: %w", liveRole.Name, err)
-		}
-
-		if err = controllerutil.SetControllerReference(&cr, expectedRole, r.Scheme); err != nil {
-			return nil, err
-		}
-
-		log.Info(fmt.Sprintf("Creating Role %s", expectedRole.Name))
-		expectedRole.Rules = expectedPolicyRules
-		return expectedRole, r.Client.Create(ctx, expectedRole)
+	if err := applyOverrides(cr, overrideKindRole, expectedRole.Name, expectedRole); err != nil {
+		return nil, err
 	}
-
-	updateNeeded := false
-
-	if !reflect.DeepEqual(liveRole.Rules, expectedPolicyRules) {
-		updateNeeded = true
-
-		log.Info(fmt.Sprintf("PolicyRules of Role %s do not match the expected state, hence updating it", liveRole.Name))
-		liveRole.Rules = expectedPolicyRules
+	if err := validateOverriddenPolicyRules(overrideKindRole, getPolicyRules(cr), expectedRole.Rules, true); err != nil {
+		return nil, err
 	}
 
-	normalizedLiveRole := liveRole.DeepCopy()
-
-	removeUserLabelsAndAnnotations(&normalizedLiveRole.ObjectMeta, cr)
-
-	if !areStringMapsEqual(normalizedLiveRole.Labels, expectedRole.Labels) || !areStringMapsEqual(normalizedLiveRole.Annotations, expectedRole.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of Role %s do not match the expected state, hence updating it", liveRole.Name))
-
-		liveRole.Labels = combineStringMaps(liveRole.Labels, expectedRole.Labels)
-		liveRole.Annotations = combineStringMaps(liveRole.Annotations, expectedRole.Annotations)
+	if err := controllerutil.SetControllerReference(&cr, expectedRole, r.Scheme); err != nil {
+		return nil, err
 	}
 
-	if updateNeeded {
-		// Update if the Role already exists and needs to be modified
-		return liveRole, r.Client.Update(ctx, liveRole)
+	if err := r.applyObject(ctx, expectedRole); err != nil {
+		return nil, err
 	}
 
-	return liveRole, nil
+	return expectedRole, nil
 }
 
 // Reconciles Rollouts ClusterRole.
@@ -138,47 +97,31 @@ func (r *RolloutManagerReconciler) reconcileRolloutsClusterRole(ctx context.Cont
 			return nil, fmt.Errorf("failed to delete existing Role %s for Namespace %s: %w", liveRole.Name, liveRole.Namespace, err)
 		}
 	}
-	expectedPolicyRules := GetPolicyRules()
+
+	if err := validatePluginPolicyRules(cr); err != nil {
+		return nil, err
+	}
+
 	expectedClusterRole := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: DefaultArgoRolloutsResourceName,
 		},
+		Rules: getPolicyRules(cr),
 	}
 	setRolloutsLabelsAndAnnotationsToObject(&expectedClusterRole.ObjectMeta, cr)
-	liveClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: expectedClusterRole.Name, Namespace: expectedClusterRole.Namespace}}
-	if err := fetchObject(ctx, r.Client, "", liveClusterRole.Name, liveClusterRole); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to Reconcile the ClusterRole for the ServiceAccount associated with %s: %w", liveClusterRole.Name, err)
-		}
-		log.Info(fmt.Sprintf("Creating ClusterRole %s", liveClusterRole.Name))
-		expectedClusterRole.Rules = expectedPolicyRules
-		return expectedClusterRole, r.Client.Create(ctx, expectedClusterRole)
-	}
 
-	updateNeeded := false
-
-	if !reflect.DeepEqual(liveClusterRole.Rules, expectedPolicyRules) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("PolicyRules of ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
-		liveClusterRole.Rules = expectedPolicyRules
+	if err := applyOverrides(cr, overrideKindClusterRole, expectedClusterRole.Name, expectedClusterRole); err != nil {
+		return nil, err
 	}
-
-	normalizedLiveClusterRole := liveClusterRole.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, cr)
-
-	if !areStringMapsEqual(normalizedLiveClusterRole.Labels, expectedClusterRole.Labels) || !areStringMapsEqual(normalizedLiveClusterRole.Annotations, expectedClusterRole.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of Role %s do not match the expected state, hence updating it", liveClusterRole.Name))
-
-		liveClusterRole.Labels = combineStringMaps(liveClusterRole.Labels, expectedClusterRole.Labels)
-		liveClusterRole.Annotations = combineStringMaps(liveClusterRole.Annotations, expectedClusterRole.Annotations)
+	if err := validateOverriddenPolicyRules(overrideKindClusterRole, getPolicyRules(cr), expectedClusterRole.Rules, false); err != nil {
+		return nil, err
 	}
 
-	if updateNeeded {
-		// Update if the ClusterRole already exists and needs to be modified
-		return liveClusterRole, r.Client.Update(ctx, liveClusterRole)
+	if err := r.applyObject(ctx, expectedClusterRole); err != nil {
+		return nil, err
 	}
-	return liveClusterRole, nil
+
+	return expectedClusterRole, nil
 }
 
 // Reconcile Rollouts RoleBinding.
@@ -222,49 +165,11 @@ func (r *RolloutManagerReconciler) reconcileRolloutsRoleBinding(ctx context.Cont
 		},
 	}
 
-	// Fetch the RoleBinding if exists and store that in actualRoleBinding.
-	liveRoleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: expectedRoleBinding.Name, Namespace: expectedRoleBinding.Namespace}}
-	if err := fetchObject(ctx, r.Client, cr.Namespace, liveRoleBinding.Name, liveRoleBinding); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to get the RoleBinding associated with %s: %w", expectedRoleBinding.Name, err)
-		}
-
-		if err := controllerutil.SetControllerReference(&cr, expectedRoleBinding, r.Scheme); err != nil {
-			return err
-		}
-
-		log.Info(fmt.Sprintf("Creating RoleBinding %s", expectedRoleBinding.Name))
-		return r.Client.Create(ctx, expectedRoleBinding)
-	}
-
-	updateNeeded := false
-
-	// Reconcile if the RoleBinding already exists and modified.
-	if !reflect.DeepEqual(expectedRoleBinding.Subjects, liveRoleBinding.Subjects) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Subjects of RoleBinding %s do not match the expected state, hence updating it", liveRoleBinding.Name))
-		liveRoleBinding.Subjects = expectedRoleBinding.Subjects
-
-	}
-
-	normalizedLiveRoleBinding := liveRoleBinding.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveRoleBinding.ObjectMeta, cr)
-	if !areStringMapsEqual(normalizedLiveRoleBinding.Labels, expectedRoleBinding.Labels) || !areStringMapsEqual(normalizedLiveRoleBinding.Annotations, expectedRoleBinding.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of RoleBinding %s do not match the expected state, hence updating it", liveRoleBinding.Name))
-
-		liveRoleBinding.Labels = combineStringMaps(liveRoleBinding.Labels, expectedRoleBinding.Labels)
-		liveRoleBinding.Annotations = combineStringMaps(liveRoleBinding.Annotations, expectedRoleBinding.Annotations)
-	}
-
-	if updateNeeded {
-		// Update if the RoleBinding already exists and needs to be modified
-		if err := r.Client.Update(ctx, liveRoleBinding); err != nil {
-			return err
-		}
+	if err := controllerutil.SetControllerReference(&cr, expectedRoleBinding, r.Scheme); err != nil {
+		return err
 	}
 
-	return nil
+	return r.applyObject(ctx, expectedRoleBinding)
 }
 
 // Reconcile Rollouts ClusterRoleBinding.
@@ -307,43 +212,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsClusterRoleBinding(ctx conte
 		},
 	}
 
-	// Fetch the ClusterRoleBinding if exists and store that in actualClusterRoleBinding.
-	liveClusterRoleBinding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: expectedClusterRoleBinding.Name}}
-	if err := fetchObject(ctx, r.Client, "", liveClusterRoleBinding.Name, liveClusterRoleBinding); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to get the ClusterRoleBinding associated with %s: %w", expectedClusterRoleBinding.Name, err)
-		}
-
-		log.Info(fmt.Sprintf("Creating ClusterRoleBinding %s", expectedClusterRoleBinding.Name))
-		return r.Client.Create(ctx, expectedClusterRoleBinding)
-	}
-
-	updateNeeded := false
-
-	if !reflect.DeepEqual(expectedClusterRoleBinding.Subjects, liveClusterRoleBinding.Subjects) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Subjects of ClusterRoleBinding %s do not match the expected state, hence updating it", expectedClusterRoleBinding.Name))
-		liveClusterRoleBinding.Subjects = expectedClusterRoleBinding.Subjects
-	}
-
-	normalizedLiveClusterRoleBinding := liveClusterRoleBinding.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveClusterRoleBinding.ObjectMeta, cr)
-	if !areStringMapsEqual(normalizedLiveClusterRoleBinding.Labels, expectedClusterRoleBinding.Labels) || !areStringMapsEqual(normalizedLiveClusterRoleBinding.Annotations, expectedClusterRoleBinding.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of ClusterRoleBinding %s do not match the expected state, hence updating it", liveClusterRoleBinding.Name))
-
-		liveClusterRoleBinding.Labels = combineStringMaps(liveClusterRoleBinding.Labels, expectedClusterRoleBinding.Labels)
-		liveClusterRoleBinding.Annotations = combineStringMaps(liveClusterRoleBinding.Annotations, expectedClusterRoleBinding.Annotations)
-	}
-
-	if updateNeeded {
-		// Update if the ClusterRoleBinding already exists and needs to be modified
-		if err := r.Client.Update(ctx, liveClusterRoleBinding); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return r.applyObject(ctx, expectedClusterRoleBinding)
 }
 
 // removeClusterScopedResourcesIfApplicable will remove the ClusterRole and ClusterRoleBinding that are created when a cluster-scoped RolloutManager is created.
@@ -421,6 +290,12 @@ func (r *RolloutManagerReconciler) removeClusterScopedResourcesIfApplicable(ctx
 		}
 	}
 
+	// Delete the Rollouts Dashboard ClusterRole/ClusterRoleBinding, if they exist.
+	if err := r.deleteRolloutsDashboardClusterRoleAndClusterRoleBinding(ctx); err != nil {
+		log.Error(err, "error on deleting Rollouts Dashboard ClusterRole/ClusterRoleBinding")
+		return err
+	}
+
 	return nil
 }
 
@@ -430,50 +305,16 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToAdminClusterRole(
 	var aggregationType string = "aggregate-to-admin"
 	name := fmt.Sprintf("%s-%s", DefaultArgoRolloutsResourceName, aggregationType)
 
-	expectedPolicyRules := GetAggregateToAdminPolicyRules()
-
 	expectedClusterRole := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
+		Rules: GetAggregateToAdminPolicyRules(),
 	}
 	setRolloutsAggregatedClusterRoleLabels(&expectedClusterRole.ObjectMeta, name, aggregationType)
 	setAdditionalRolloutsLabelsAndAnnotationsToObject(&expectedClusterRole.ObjectMeta, cr)
 
-	liveClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: expectedClusterRole.Name}}
-	if err := fetchObject(ctx, r.Client, "", liveClusterRole.Name, liveClusterRole); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to reconcile the aggregated ClusterRole %s: %w", liveClusterRole.Name, err)
-		}
-
-		log.Info(fmt.Sprintf("Creating aggregated ClusterRole %s", liveClusterRole.Name))
-		expectedClusterRole.Rules = expectedPolicyRules
-		return r.Client.Create(ctx, expectedClusterRole)
-	}
-
-	updateNeeded := false
-
-	if !reflect.DeepEqual(liveClusterRole.Rules, expectedPolicyRules) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("PolicyRules of ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
-		liveClusterRole.Rules = expectedPolicyRules
-	}
-
-	normalizedLiveClusterRole := liveClusterRole.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, cr)
-	if !areStringMapsEqual(normalizedLiveClusterRole.Labels, expectedClusterRole.Labels) || !areStringMapsEqual(normalizedLiveClusterRole.Annotations, expectedClusterRole.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of aggregated ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
-
-		liveClusterRole.Labels = combineStringMaps(liveClusterRole.Labels, expectedClusterRole.Labels)
-		liveClusterRole.Annotations = combineStringMaps(liveClusterRole.Annotations, expectedClusterRole.Annotations)
-	}
-
-	if updateNeeded {
-		// Update if the aggregated ClusterRole already exists and needs to be modified
-		return r.Client.Update(ctx, liveClusterRole)
-	}
-	return nil
+	return r.applyObject(ctx, expectedClusterRole)
 }
 
 // Reconciles aggregate-to-edit ClusterRole.
@@ -482,50 +323,16 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToEditClusterRole(c
 	var aggregationType string = "aggregate-to-edit"
 	name := fmt.Sprintf("%s-%s", DefaultArgoRolloutsResourceName, aggregationType)
 
-	expectedPolicyRules := GetAggregateToEditPolicyRules()
-
 	expectedClusterRole := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
+		Rules: GetAggregateToEditPolicyRules(),
 	}
 	setRolloutsAggregatedClusterRoleLabels(&expectedClusterRole.ObjectMeta, name, aggregationType)
 	setAdditionalRolloutsLabelsAndAnnotationsToObject(&expectedClusterRole.ObjectMeta, cr)
 
-	liveClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: expectedClusterRole.Name}}
-	if err := fetchObject(ctx, r.Client, "", liveClusterRole.Name, liveClusterRole); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to reconcile the aggregated ClusterRole %s: %w", liveClusterRole.Name, err)
-		}
-
-		log.Info(fmt.Sprintf("Creating aggregated ClusterRole %s", expectedClusterRole.Name))
-		expectedClusterRole.Rules = expectedPolicyRules
-		return r.Client.Create(ctx, expectedClusterRole)
-	}
-
-	updateNeeded := false
-
-	if !reflect.DeepEqual(liveClusterRole.Rules, expectedPolicyRules) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("PolicyRules of ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
-		liveClusterRole.Rules = expectedPolicyRules
-	}
-
-	normalizedLiveClusterRole := liveClusterRole.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, cr)
-	if !areStringMapsEqual(normalizedLiveClusterRole.Labels, expectedClusterRole.Labels) || !areStringMapsEqual(normalizedLiveClusterRole.Annotations, expectedClusterRole.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of aggregated ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
-
-		liveClusterRole.Labels = combineStringMaps(liveClusterRole.Labels, expectedClusterRole.Labels)
-		liveClusterRole.Annotations = combineStringMaps(liveClusterRole.Annotations, expectedClusterRole.Annotations)
-	}
-
-	if updateNeeded {
-		// Update if the aggregated ClusterRole already exists and needs to be modified
-		return r.Client.Update(ctx, liveClusterRole)
-	}
-	return nil
+	return r.applyObject(ctx, expectedClusterRole)
 }
 
 // Reconciles aggregate-to-view ClusterRole.
@@ -534,52 +341,16 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToViewClusterRole(c
 	var aggregationType string = "aggregate-to-view"
 	name := fmt.Sprintf("%s-%s", DefaultArgoRolloutsResourceName, aggregationType)
 
-	expectedPolicyRules := GetAggregateToViewPolicyRules()
-
 	expectedClusterRole := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
+		Rules: GetAggregateToViewPolicyRules(),
 	}
 	setRolloutsAggregatedClusterRoleLabels(&expectedClusterRole.ObjectMeta, name, aggregationType)
 	setAdditionalRolloutsLabelsAndAnnotationsToObject(&expectedClusterRole.ObjectMeta, cr)
 
-	liveClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: expectedClusterRole.Name, Namespace: expectedClusterRole.Namespace}}
-	if err := fetchObject(ctx, r.Client, "", liveClusterRole.Name, liveClusterRole); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to reconcile the aggregated ClusterRole %s: %w", liveClusterRole.Name, err)
-		}
-
-		log.Info(fmt.Sprintf("Creating aggregated ClusterRole %s", expectedClusterRole.Name))
-		expectedClusterRole.Rules = expectedPolicyRules
-		return r.Client.Create(ctx, expectedClusterRole)
-	}
-
-	updateNeeded := false
-
-	if !reflect.DeepEqual(liveClusterRole.Rules, expectedPolicyRules) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("PolicyRules of ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
-		liveClusterRole.Rules = expectedPolicyRules
-	}
-
-	normalizedLiveClusterRole := liveClusterRole.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, cr)
-	if !areStringMapsEqual(normalizedLiveClusterRole.Labels, expectedClusterRole.Labels) || !areStringMapsEqual(normalizedLiveClusterRole.Annotations, expectedClusterRole.Annotations) {
-
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of aggregated ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
-
-		liveClusterRole.Labels = combineStringMaps(liveClusterRole.Labels, expectedClusterRole.Labels)
-		liveClusterRole.Annotations = combineStringMaps(liveClusterRole.Annotations, expectedClusterRole.Annotations)
-	}
-
-	if updateNeeded {
-		// Update if the aggregated ClusterRole already exists and needs to be modified
-		return r.Client.Update(ctx, liveClusterRole)
-	}
-
-	return nil
+	return r.applyObject(ctx, expectedClusterRole)
 }
 
 // reconcileRolloutsMetricsServiceAndMonitor reconciles the Rollouts Metrics Service and ServiceMonitor
@@ -605,47 +376,31 @@ func (r *RolloutManagerReconciler) reconcileRolloutsMetricsServiceAndMonitor(ctx
 	}
 
 	// Create ServiceMonitor for Rollouts metrics
-	existingServiceMonitor := &monitoringv1.ServiceMonitor{}
-	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, existingServiceMonitor); err != nil {
-		if apierrors.IsNotFound(err) {
-			if err := r.createServiceMonitorIfAbsent(ctx, cr.Namespace, cr, DefaultArgoRolloutsResourceName, reconciledSvc.Name); err != nil {
-				return err
-			}
-			return nil
-
-		} else {
-			log.Error(err, "Error querying for ServiceMonitor", "Namespace", cr.Namespace, "Name", reconciledSvc.Name)
-			return err
-		}
-
-	} else {
-		log.Info("A ServiceMonitor instance already exists",
-			"Namespace", existingServiceMonitor.Namespace, "Name", existingServiceMonitor.Name)
-
-		// Check if existing ServiceMonitor matches expected content
-		if !serviceMonitorMatches(existingServiceMonitor, reconciledSvc.Name) {
-			log.Info("Updating existing ServiceMonitor instance",
-				"Namespace", existingServiceMonitor.Namespace, "Name", existingServiceMonitor.Name)
-
-			// Update ServiceMonitor with expected content
-			existingServiceMonitor.Spec.Selector.MatchLabels = map[string]string{
-				"app.kubernetes.io/name": reconciledSvc.Name,
-			}
-			existingServiceMonitor.Spec.Endpoints = []monitoringv1.Endpoint{
+	expectedServiceMonitor := &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsResourceName,
+			Namespace: cr.Namespace,
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app.kubernetes.io/name": reconciledSvc.Name,
+				},
+			},
+			Endpoints: []monitoringv1.Endpoint{
 				{
 					Port: "metrics",
 				},
-			}
+			},
+		},
+	}
 
-			if err := r.Client.Update(ctx, existingServiceMonitor); err != nil {
-				log.Error(err, "Error updating existing ServiceMonitor instance",
-					"Namespace", existingServiceMonitor.Namespace, "Name", existingServiceMonitor.Name)
-				return err
-			}
-		}
-		return nil
+	// Set the RolloutManager instance as the owner and controller
+	if err := controllerutil.SetControllerReference(&cr, expectedServiceMonitor, r.Scheme); err != nil {
+		return err
 	}
 
+	return r.applyObject(ctx, expectedServiceMonitor)
 }
 
 func (r *RolloutManagerReconciler) deleteRolloutsNetworkPolicies(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
@@ -715,71 +470,15 @@ func (r *RolloutManagerReconciler) reconcileRolloutsNetworkPolicy(ctx context.Co
 		},
 	}
 
-	existing := &networkingv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      desired.Name,
-			Namespace: desired.Namespace,
-		},
+	if err := applyOverrides(cr, overrideKindNetworkPolicy, desired.Name, desired); err != nil {
+		return err
 	}
 
-	if err := fetchObject(ctx, r.Client, cr.Namespace, existing.Name, existing); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to get NetworkPolicy %s: %w", existing.Name, err)
-		}
-		if err := controllerutil.SetControllerReference(&cr, desired, r.Scheme); err != nil {
-			log.Error(err, "Failed to set controller reference on rollouts network policy")
-			return fmt.Errorf("failed to set controller reference on rollouts network policy: %w", err)
-		}
-		log.Info(fmt.Sprintf("Creating NetworkPolicy %s", desired.Name))
-		if err := r.Client.Create(ctx, desired); err != nil {
-			log.Error(err, "Failed to create network policy", "name", desired.Name, "namespace", cr.Namespace)
-			return fmt.Errorf("failed to create network policy %s in namespace %s: %w", desired.Name, cr.Namespace, err)
-		}
-		return nil
+	if err := controllerutil.SetControllerReference(&cr, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on rollouts network policy: %w", err)
 	}
-	updateNeeded := false
-	explanation := ""
-	if !reflect.DeepEqual(existing.Spec.PodSelector, desired.Spec.PodSelector) {
-		existing.Spec.PodSelector = desired.Spec.PodSelector
-		explanation = "pod selector"
-		updateNeeded = true
-	}
-	if !reflect.DeepEqual(existing.Spec.PolicyTypes, desired.Spec.PolicyTypes) {
-		existing.Spec.PolicyTypes = desired.Spec.PolicyTypes
-		if updateNeeded {
-			explanation += ", "
-		}
-		explanation += "policy types"
-		updateNeeded = true
-	}
-	if !reflect.DeepEqual(existing.Spec.Ingress, desired.Spec.Ingress) {
-		existing.Spec.Ingress = desired.Spec.Ingress
-		if updateNeeded {
-			explanation += ", "
-		}
-		explanation += "ingress rules"
-		updateNeeded = true
-	}
-	normalizedExisting := existing.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedExisting.ObjectMeta, cr)
-	if !areStringMapsEqual(normalizedExisting.Labels, desired.Labels) || !areStringMapsEqual(normalizedExisting.Annotations, desired.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of NetworkPolicy %s do not match the expected state, hence updating it", existing.Name))
-		existing.Labels = combineStringMaps(existing.Labels, desired.Labels)
-		existing.Annotations = combineStringMaps(existing.Annotations, desired.Annotations)
-		if explanation != "" {
-			explanation += ", "
-		}
-		explanation += "labels/annotations"
-	}
-	if updateNeeded {
-		log.Info(fmt.Sprintf("NetworkPolicy %s does not match the expected state, updating %s", existing.Name, explanation))
-		if err := r.Client.Update(ctx, existing); err != nil {
-			log.Error(err, "Failed to update network policy", "name", existing.Name, "namespace", cr.Namespace)
-			return fmt.Errorf("failed to update network policy %s in namespace %s: %w", existing.Name, cr.Namespace, err)
-		}
-	}
-	return nil
+
+	return r.applyObject(ctx, desired)
 }
 
 // reconcileRolloutsMetricsService reconciles the Service which is used to gather metrics from Rollouts install
@@ -809,54 +508,19 @@ func (r *RolloutManagerReconciler) reconcileRolloutsMetricsService(ctx context.C
 		DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName,
 	}
 
-	liveService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: expectedSvc.Name, Namespace: expectedSvc.Namespace}}
-	if err := fetchObject(ctx, r.Client, cr.Namespace, liveService.Name, liveService); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to get the Service %s: %w", expectedSvc.Name, err)
-		}
-
-		if err := controllerutil.SetControllerReference(&cr, expectedSvc, r.Scheme); err != nil {
-			return nil, err
-		}
-
-		log.Info(fmt.Sprintf("Creating Service %s", expectedSvc.Name))
-		if err := r.Client.Create(ctx, expectedSvc); err != nil {
-			log.Error(err, "Error creating Service", "Name", expectedSvc.Name)
-			return nil, err
-		}
-		liveService = expectedSvc
-
-	}
-
-	updateNeeded := false
-
-	if !reflect.DeepEqual(liveService.Spec.Ports, expectedSvc.Spec.Ports) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Ports of metrics Service %s do not match the expected state, hence updating it", liveService.Name))
-		liveService.Spec.Ports = expectedSvc.Spec.Ports
+	if err := applyOverrides(cr, overrideKindService, expectedSvc.Name, expectedSvc); err != nil {
+		return nil, err
 	}
 
-	normalizedLiveService := liveService.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveService.ObjectMeta, cr)
-	if !areStringMapsEqual(normalizedLiveService.Labels, expectedSvc.Labels) || !areStringMapsEqual(normalizedLiveService.Annotations, expectedSvc.Annotations) {
-
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of metrics Service %s do not match the expected state, hence updating it", liveService.Name))
-
-		liveService.Labels = combineStringMaps(liveService.Labels, expectedSvc.Labels)
-		liveService.Annotations = combineStringMaps(liveService.Annotations, expectedSvc.Annotations)
+	if err := controllerutil.SetControllerReference(&cr, expectedSvc, r.Scheme); err != nil {
+		return nil, err
 	}
 
-	if updateNeeded {
-		// Update if the Service already exists and needs to be modified
-		if err := r.Client.Update(ctx, liveService); err != nil {
-			log.Error(err, "Error updating Ports of metrics Service", "Name", liveService.Name)
-			return liveService, err
-		}
+	if err := r.applyObject(ctx, expectedSvc); err != nil {
+		return nil, err
 	}
 
-	return liveService, nil
-
+	return expectedSvc, nil
 }
 
 // Reconciles Secrets for Rollouts controller
@@ -875,6 +539,13 @@ func (r *RolloutManagerReconciler) reconcileRolloutsSecrets(ctx context.Context,
 		setCustomLabels(&expectedSecret.ObjectMeta, r.ResourceLabels)
 	}
 
+	// The secret values referenced by .spec.notifications are applied with the Secret
+	notificationValues, err := r.getNotificationSecretValues(ctx, cr)
+	if err != nil {
+		return err
+	}
+	expectedSecret.Data = notificationValues
+
 	// If the Secret doesn't exist (or an unrelated error occurred)....
 	liveSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: expectedSecret.Name, Namespace: expectedSecret.Namespace}}
 	if err := fetchObject(ctx, r.Client, cr.Namespace, liveSecret.Name, liveSecret); err != nil {
@@ -893,16 +564,16 @@ func (r *RolloutManagerReconciler) reconcileRolloutsSecrets(ctx context.Context,
 		}
 
 		log.Info(fmt.Sprintf("Creating Secret %s", expectedSecret.Name))
-		return r.Client.Create(ctx, expectedSecret)
-
+		return r.applyObject(ctx, expectedSecret)
 	}
 
+	ownedByController := metav1.IsControlledBy(liveSecret, &cr)
+
 	// If SkipNotificationSecretDeployment is true, and the secret exists (and is owned by us), delete it
 	if cr.Spec.SkipNotificationSecretDeployment {
 
 		// If the controller created/owns the Secret, delete it
-		controller := metav1.GetControllerOf(liveSecret)
-		if controller != nil && controller.Name == cr.Name {
+		if ownedByController {
 			log.Info(fmt.Sprintf("SkipNotificationSecretDeployment has been set to true, deleting secret %s", liveSecret.Name))
 			return r.Client.Delete(ctx, liveSecret)
 		}
@@ -911,41 +582,145 @@ func (r *RolloutManagerReconciler) reconcileRolloutsSecrets(ctx context.Context,
 		return nil
 	}
 
-	// Otherwise, the Secret exists, so update it if the labels/annotations are inconsistent
+	// Otherwise, the Secret exists, so apply its labels/annotations. The type of an existing Secret is immutable, and a Secret created by a user is not taken over by the controller.
+	if ownedByController {
+		if err := controllerutil.SetControllerReference(&cr, expectedSecret, r.Scheme); err != nil {
+			return err
+		}
+	} else {
+		expectedSecret.Type = ""
+	}
 
-	updateNeeded := false
+	return r.applyObject(ctx, expectedSecret)
+}
 
-	normalizedLiveSecret := liveSecret.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveSecret.ObjectMeta, cr)
+func setRolloutsAggregatedClusterRoleLabels(obj *metav1.ObjectMeta, name string, aggregationType string) {
 
-	if !areStringMapsEqual(normalizedLiveSecret.Labels, expectedSecret.Labels) || !areStringMapsEqual(normalizedLiveSecret.Annotations, expectedSecret.Annotations) {
-		updateNeeded = true
-		log.Info(fmt.Sprintf("Labels/Annotations of Secret %s do not match the expected state, hence updating it", liveSecret.Name))
+	obj.Labels = map[string]string{}
+	obj.Labels["app.kubernetes.io/component"] = "aggregate-cluster-role"
+	obj.Labels["app.kubernetes.io/name"] = name
+	obj.Labels["app.kubernetes.io/part-of"] = DefaultArgoRolloutsResourceName
+	obj.Labels["rbac.authorization.k8s.io/"+aggregationType] = "true"
+}
 
-		liveSecret.Labels = combineStringMaps(liveSecret.Labels, expectedSecret.Labels)
-		liveSecret.Annotations = combineStringMaps(liveSecret.Annotations, expectedSecret.Annotations)
+// getPolicyRules returns the policy rules for the Argo Rollouts Role/ClusterRole of the given RolloutManager.
+// In least-privilege mode, only the core rules and the rules of the declared traffic routing providers are returned.
+// The additional rules declared by plugins are then added to them.
+func getPolicyRules(cr rolloutsmanagerv1alpha1.RolloutManager) []rbacv1.PolicyRule {
+	policyRules := GetPolicyRules()
+	if cr.Spec.RBAC.IsLeastPrivilege() {
+		policyRules = getLeastPrivilegePolicyRules(cr.Spec.TrafficRouting)
 	}
+	return appendPluginPolicyRules(policyRules, cr.Spec.Plugins)
+}
 
-	if updateNeeded {
-		// Update if the Secret already exists and needs to be modified
-		return r.Client.Update(ctx, liveSecret)
+// appendPluginPolicyRules appends the rules declared by the plugins to policyRules, skipping rules that are already present.
+func appendPluginPolicyRules(policyRules []rbacv1.PolicyRule, plugins rolloutsmanagerv1alpha1.Plugins) []rbacv1.PolicyRule {
+	for _, pluginList := range [][]rolloutsmanagerv1alpha1.Plugin{plugins.TrafficManagement, plugins.Metric, plugins.Step} {
+		for _, plugin := range pluginList {
+			for _, rule := range plugin.Rules {
+				if !containsPolicyRule(policyRules, rule) {
+					policyRules = append(policyRules, rule)
+				}
+			}
+		}
 	}
+	return policyRules
+}
 
-	// secret found, do nothing
+// containsPolicyRule returns true if the rule is already present in policyRules.
+func containsPolicyRule(policyRules []rbacv1.PolicyRule, rule rbacv1.PolicyRule) bool {
+	for _, policyRule := range policyRules {
+		if reflect.DeepEqual(policyRule, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// forbiddenDelegatedVerbs are the verbs that cannot be granted to the Rollouts controller by a plugin, as they allow privilege escalation.
+	forbiddenDelegatedVerbs = []string{"escalate", "bind", "impersonate"}
+
+	// forbiddenDelegatedAPIGroups are the API groups whose resources cannot be granted to the Rollouts controller by a plugin.
+	forbiddenDelegatedAPIGroups = []string{rbacv1.GroupName}
+
+	// forbiddenDelegatedCoreResources are the resources of the core API group that cannot be granted to the Rollouts controller by a plugin,
+	// as they expose credentials or allow running commands in pods.
+	forbiddenDelegatedCoreResources = []string{"secrets", "pods/exec", "pods/attach", "serviceaccounts/token"}
+)
+
+// validatePluginPolicyRules returns an error if a rule declared by a plugin cannot be added to the Role/ClusterRole of the Rollouts controller.
+func validatePluginPolicyRules(cr rolloutsmanagerv1alpha1.RolloutManager) error {
+	for _, pluginList := range [][]rolloutsmanagerv1alpha1.Plugin{cr.Spec.Plugins.TrafficManagement, cr.Spec.Plugins.Metric, cr.Spec.Plugins.Step} {
+		for _, plugin := range pluginList {
+			for _, rule := range plugin.Rules {
+				if err := validateDelegatedPolicyRule(rule, cr.Spec.NamespaceScoped); err != nil {
+					return fmt.Errorf("invalid rules of plugin %s: %w", plugin.Name, err)
+				}
+			}
+		}
+	}
 	return nil
 }
 
-func setRolloutsAggregatedClusterRoleLabels(obj *metav1.ObjectMeta, name string, aggregationType string) {
+// validateDelegatedPolicyRule returns an error if a rule that is not part of GetPolicyRules cannot be granted to the Rollouts controller:
+// wildcards, and rules that would allow privilege escalation or access to credentials, are rejected.
+func validateDelegatedPolicyRule(rule rbacv1.PolicyRule, namespaceScoped bool) error {
 
-	obj.Labels = map[string]string{}
-	obj.Labels["app.kubernetes.io/component"] = "aggregate-cluster-role"
-	obj.Labels["app.kubernetes.io/name"] = name
-	obj.Labels["app.kubernetes.io/part-of"] = DefaultArgoRolloutsResourceName
-	obj.Labels["rbac.authorization.k8s.io/"+aggregationType] = "true"
+	if len(rule.Verbs) == 0 {
+		return fmt.Errorf("verbs must not be empty")
+	}
+	if len(rule.Resources) == 0 && len(rule.NonResourceURLs) == 0 {
+		return fmt.Errorf("either resources or nonResourceURLs must be set")
+	}
+	if namespaceScoped && len(rule.NonResourceURLs) > 0 {
+		return fmt.Errorf("nonResourceURLs are not supported by a namespace-scoped RolloutManager")
+	}
+
+	for _, verb := range rule.Verbs {
+		if verb == rbacv1.VerbAll || slices.Contains(forbiddenDelegatedVerbs, strings.ToLower(verb)) {
+			return fmt.Errorf("verb '%s' is not allowed", verb)
+		}
+	}
+
+	for _, apiGroup := range rule.APIGroups {
+		if apiGroup == rbacv1.APIGroupAll || slices.Contains(forbiddenDelegatedAPIGroups, apiGroup) {
+			return fmt.Errorf("API group '%s' is not allowed", apiGroup)
+		}
+	}
+
+	for _, resource := range rule.Resources {
+		if strings.Contains(resource, rbacv1.ResourceAll) {
+			return fmt.Errorf("resource '%s' is not allowed", resource)
+		}
+		if slices.Contains(rule.APIGroups, "") && slices.Contains(forbiddenDelegatedCoreResources, strings.ToLower(resource)) {
+			return fmt.Errorf("resource '%s' is not allowed", resource)
+		}
+	}
+
+	for _, url := range rule.NonResourceURLs {
+		if strings.Contains(url, rbacv1.NonResourceAll) {
+			return fmt.Errorf("non-resource URL '%s' is not allowed", url)
+		}
+	}
+
+	return nil
 }
 
-// getPolicyRules returns the policy rules for Argo Rollouts Role.
+// GetPolicyRules returns the policy rules for Argo Rollouts Role.
 func GetPolicyRules() []rbacv1.PolicyRule {
+
+	policyRules := getCorePolicyRules()
+	for _, provider := range trafficRoutingProviders {
+		policyRules = append(policyRules, trafficRoutingPolicyRules[provider]...)
+	}
+
+	return policyRules
+}
+
+// getCorePolicyRules returns the policy rules for Argo Rollouts Role that are required whatever the traffic routing providers in use.
+func getCorePolicyRules() []rbacv1.PolicyRule {
 	return []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
@@ -1145,151 +920,6 @@ func GetPolicyRules() []rbacv1.PolicyRule {
 				"delete",
 			},
 		},
-		{
-			APIGroups: []string{
-				"networking.istio.io",
-			},
-			Resources: []string{
-				"virtualservices",
-				"destinationrules",
-			},
-			Verbs: []string{
-				"watch",
-				"get",
-				"update",
-				"patch",
-				"list",
-			},
-		},
-		{
-			APIGroups: []string{
-				"split.smi-spec.io",
-			},
-			Resources: []string{
-				"trafficsplits",
-			},
-			Verbs: []string{
-				"create",
-				"watch",
-				"get",
-				"update",
-				"patch",
-			},
-		},
-		{
-			APIGroups: []string{
-				"getambassador.io",
-				"x.getambassador.io",
-			},
-			Resources: []string{
-				"mappings",
-				"ambassadormappings",
-			},
-			Verbs: []string{
-				"create",
-				"watch",
-				"get",
-				"update",
-				"list",
-				"delete",
-			},
-		},
-		{
-			APIGroups: []string{
-				"",
-			},
-			Resources: []string{
-				"endpoints",
-			},
-			Verbs: []string{
-				"get",
-			},
-		},
-		{
-			APIGroups: []string{
-				"elbv2.k8s.aws",
-				"eks.amazonaws.com",
-			},
-			Resources: []string{
-				"targetgroupbindings",
-			},
-			Verbs: []string{
-				"list",
-				"get",
-			},
-		},
-		{
-			APIGroups: []string{
-				"appmesh.k8s.aws",
-			},
-			Resources: []string{
-				"virtualservices",
-			},
-			Verbs: []string{
-				"watch",
-				"get",
-				"list",
-			},
-		},
-		{
-			APIGroups: []string{
-				"appmesh.k8s.aws",
-			},
-			Resources: []string{
-				"virtualnodes",
-				"virtualrouters",
-			},
-			Verbs: []string{
-				"watch",
-				"get",
-				"list",
-				"update",
-				"patch",
-			},
-		},
-		{
-			APIGroups: []string{
-				"traefik.containo.us",
-				"traefik.io",
-			},
-			Resources: []string{
-				"traefikservices",
-			},
-			Verbs: []string{
-				"watch",
-				"get",
-				"update",
-			},
-		},
-		{
-			APIGroups: []string{
-				"apisix.apache.org",
-			},
-			Resources: []string{
-				"apisixroutes",
-			},
-			Verbs: []string{
-				"watch",
-				"get",
-				"update",
-			},
-		},
-		{
-			APIGroups: []string{
-				"route.openshift.io",
-			},
-			Resources: []string{
-				"routes",
-			},
-			Verbs: []string{
-				"create",
-				"watch",
-				"get",
-				"update",
-				"patch",
-				"list",
-			},
-		},
 	}
 }
 
@@ -1376,62 +1006,3 @@ func GetAggregateToViewPolicyRules() []rbacv1.PolicyRule {
 		},
 	}
 }
-
-func (r *RolloutManagerReconciler) createServiceMonitorIfAbsent(ctx context.Context, namespace string, rolloutManager rolloutsmanagerv1alpha1.RolloutManager, name, serviceMonitorLabel string) error {
-	serviceMonitor := &monitoringv1.ServiceMonitor{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
-		},
-		Spec: monitoringv1.ServiceMonitorSpec{
-			Selector: metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app.kubernetes.io/name": serviceMonitorLabel,
-				},
-			},
-			Endpoints: []monitoringv1.Endpoint{
-				{
-					Port: "metrics",
-				},
-			},
-		},
-	}
-	log.Info("Creating a new ServiceMonitor instance",
-		"Namespace", serviceMonitor.Namespace, "Name", serviceMonitor.Name)
-
-	// Set the RolloutManager instance as the owner and controller
-	if err := controllerutil.SetControllerReference(&rolloutManager, serviceMonitor, r.Scheme); err != nil {
-		log.Error(err, "Error setting read role owner ref",
-			"Namespace", serviceMonitor.Namespace, "Name", serviceMonitor.Name, "RolloutManager Name", rolloutManager.Name)
-		return err
-	}
-
-	err := r.Client.Create(ctx, serviceMonitor)
-	if err != nil {
-		log.Error(err, "Error creating a new ServiceMonitor instance",
-			"Namespace", serviceMonitor.Namespace, "Name", serviceMonitor.Name)
-		return err
-	}
-
-	return nil
-
-}
-
-func serviceMonitorMatches(sm *monitoringv1.ServiceMonitor, matchLabel string) bool {
-	// Check if labels match
-	labels := sm.Spec.Selector.MatchLabels
-	if val, ok := labels["app.kubernetes.io/name"]; ok {
-		if val != matchLabel {
-			return false
-		}
-	} else {
-		return false
-	}
-
-	// Check if endpoints match
-	if len(sm.Spec.Endpoints) == 0 || sm.Spec.Endpoints[0].Port != "metrics" {
-		return false
-	}
-
-	return true
-}
@@ -1268,6 +1268,102 @@ var _ = Describe("Resource creation and cleanup tests", func() {
 			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(clusterRole), clusterRole)).ToNot(Succeed())
 		})
 	})
+
+	Context("Verify RBAC policy rules declared by plugins", func() {
+		var (
+			ctx         context.Context
+			a           v1alpha1.RolloutManager
+			r           *RolloutManagerReconciler
+			gatewayRule rbacv1.PolicyRule
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			gatewayRule = rbacv1.PolicyRule{
+				APIGroups: []string{"gateway.networking.k8s.io"},
+				Resources: []string{"httproutes"},
+				Verbs:     []string{"get", "list", "watch", "update", "patch"},
+			}
+			a = *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.Plugins.TrafficManagement = []v1alpha1.Plugin{
+					{Name: "argoproj-labs/gatewayAPI", Location: "https://test-path", Rules: []rbacv1.PolicyRule{gatewayRule}},
+				}
+			})
+			r = makeTestReconciler(&a)
+			Expect(createNamespace(r, a.Namespace)).To(Succeed())
+		})
+
+		It("should merge the rules of the plugins into the ClusterRole, without duplicates, and remove them when the plugin is removed", func() {
+			By("declaring the same rule in a second plugin, and a rule that is already part of GetPolicyRules")
+			a.Spec.Plugins.Metric = []v1alpha1.Plugin{
+				{Name: "argoproj-labs/sample-prometheus", Location: "https://test-path", Rules: []rbacv1.PolicyRule{gatewayRule, GetPolicyRules()[0]}},
+			}
+
+			clusterRole, err := r.reconcileRolloutsClusterRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterRole.Rules).To(Equal(append(GetPolicyRules(), gatewayRule)))
+
+			By("removing the plugins")
+			a.Spec.Plugins = v1alpha1.Plugins{}
+			clusterRole, err = r.reconcileRolloutsClusterRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterRole.Rules).To(Equal(GetPolicyRules()))
+		})
+
+		It("should merge the rules of the plugins into the Role", func() {
+			a.Spec.NamespaceScoped = true
+
+			role, err := r.reconcileRolloutsRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role.Rules).To(Equal(append(GetPolicyRules(), gatewayRule)))
+
+			By("least-privilege mode should keep the rules of the plugins")
+			a.Spec.RBAC = &v1alpha1.RolloutManagerRBACSpec{LeastPrivilege: true}
+			role, err = r.reconcileRolloutsRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role.Rules).To(Equal(append(getLeastPrivilegePolicyRules(nil), gatewayRule)))
+		})
+
+		DescribeTable("should reject invalid rules", func(namespaceScoped bool, rule rbacv1.PolicyRule, expectedError string) {
+			a.Spec.NamespaceScoped = namespaceScoped
+			a.Spec.Plugins.TrafficManagement[0].Rules = []rbacv1.PolicyRule{rule}
+
+			Expect(validatePluginPolicyRules(a)).To(MatchError(expectedError))
+
+			if namespaceScoped {
+				_, err := r.reconcileRolloutsRole(ctx, a)
+				Expect(err).To(MatchError(expectedError))
+			} else {
+				_, err := r.reconcileRolloutsClusterRole(ctx, a)
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+			Entry("without verbs", false, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: verbs must not be empty"),
+			Entry("without resources", false, rbacv1.PolicyRule{APIGroups: []string{""}, Verbs: []string{"get"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: either resources or nonResourceURLs must be set"),
+			Entry("with nonResourceURLs when namespace-scoped", true, rbacv1.PolicyRule{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: nonResourceURLs are not supported by a namespace-scoped RolloutManager"),
+			Entry("with a wildcard verb", false, rbacv1.PolicyRule{APIGroups: []string{"gateway.networking.k8s.io"}, Resources: []string{"httproutes"}, Verbs: []string{"*"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: verb '*' is not allowed"),
+			Entry("with a wildcard API group", false, rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"httproutes"}, Verbs: []string{"get"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: API group '*' is not allowed"),
+			Entry("with a wildcard resource", false, rbacv1.PolicyRule{APIGroups: []string{"gateway.networking.k8s.io"}, Resources: []string{"httproutes/*"}, Verbs: []string{"get"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: resource 'httproutes/*' is not allowed"),
+			Entry("with the bind verb", false, rbacv1.PolicyRule{APIGroups: []string{"gateway.networking.k8s.io"}, Resources: []string{"httproutes"}, Verbs: []string{"get", "bind"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: verb 'bind' is not allowed"),
+			Entry("with RBAC resources", false, rbacv1.PolicyRule{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"clusterroles"}, Verbs: []string{"create"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: API group 'rbac.authorization.k8s.io' is not allowed"),
+			Entry("with secrets", true, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"create"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: resource 'secrets' is not allowed"),
+			Entry("with pods/exec", false, rbacv1.PolicyRule{APIGroups: []string{"", "apps"}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: resource 'pods/exec' is not allowed"),
+			Entry("with serviceaccounts/token", false, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"serviceaccounts/token"}, Verbs: []string{"create"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: resource 'serviceaccounts/token' is not allowed"),
+			Entry("with a wildcard non-resource URL", false, rbacv1.PolicyRule{NonResourceURLs: []string{"*"}, Verbs: []string{"get"}},
+				"invalid rules of plugin argoproj-labs/gatewayAPI: non-resource URL '*' is not allowed"),
+		)
+	})
 })
 
 func serviceMonitor() *monitoringv1.ServiceMonitor {
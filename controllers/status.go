@@ -8,7 +8,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
-// determineStatusPhase calculates and returns RolloutManager's current .status.phase and .status.rolloutcontroller, both based on Deployment status.
+// determineStatusPhase calculates and returns RolloutManager's current .status.phase and .status.rolloutcontroller, both based on Deployment status, and its ControllerHealthy condition.
 func (r *RolloutManagerReconciler) determineStatusPhase(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (reconcileStatusResult, error) {
 
 	status := rolloutsmanagerv1alpha1.PhaseUnknown
@@ -17,6 +17,7 @@ func (r *RolloutManagerReconciler) determineStatusPhase(ctx context.Context, cr
 	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, deploy); err != nil {
 		if apierrors.IsNotFound(err) {
 			status = rolloutsmanagerv1alpha1.PhaseFailure
+			deploy = nil
 		} else {
 			log.Error(err, "error retrieving Deployment")
 			return reconcileStatusResult{}, err
@@ -25,7 +26,9 @@ func (r *RolloutManagerReconciler) determineStatusPhase(ctx context.Context, cr
 
 		// Deployment exists
 
-		if deploy.Spec.Replicas != nil {
+		if isDeploymentSuspended(cr, deploy) {
+			status = rolloutsmanagerv1alpha1.PhaseSuspended
+		} else if deploy.Spec.Replicas != nil {
 			status = rolloutsmanagerv1alpha1.PhasePending
 			if deploy.Status.ReadyReplicas == *deploy.Spec.Replicas {
 				status = rolloutsmanagerv1alpha1.PhaseAvailable
@@ -35,13 +38,61 @@ func (r *RolloutManagerReconciler) determineStatusPhase(ctx context.Context, cr
 
 	var res reconcileStatusResult
 
+	controllerHealthyCondition, err := r.getControllerHealthyCondition(ctx, cr, deploy)
+	if err != nil {
+		log.Error(err, "error diagnosing the pods of the Rollouts controller")
+		return reconcileStatusResult{}, err
+	}
+	res.conditions = append(res.conditions, controllerHealthyCondition)
+
 	if cr.Status.RolloutController != status {
 		res.rolloutController = &status
 	}
 
-	if cr.Status.Phase != status {
-		res.phase = &status
+	// The Dashboard status is empty when the Dashboard is not enabled
+	var dashboardStatus rolloutsmanagerv1alpha1.RolloutControllerPhase
+	if cr.Spec.Dashboard.IsEnabled() {
+		if dashboardStatus, err = r.determineDashboardStatusPhase(ctx, cr); err != nil {
+			return reconcileStatusResult{}, err
+		}
+	}
+
+	if cr.Status.Dashboard != dashboardStatus {
+		res.dashboard = &dashboardStatus
+	}
+
+	// The RolloutManager is only Available once all of its components are Available
+	phase := status
+	if phase == rolloutsmanagerv1alpha1.PhaseAvailable && cr.Spec.Dashboard.IsEnabled() {
+		phase = dashboardStatus
+	}
+
+	if cr.Status.Phase != phase {
+		res.phase = &phase
 	}
 
 	return res, nil
 }
+
+// determineDashboardStatusPhase calculates and returns the phase of the Rollouts Dashboard, based on the Dashboard Deployment status.
+func (r *RolloutManagerReconciler) determineDashboardStatusPhase(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (rolloutsmanagerv1alpha1.RolloutControllerPhase, error) {
+
+	deploy := &appsv1.Deployment{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsDashboardResourceName, deploy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return rolloutsmanagerv1alpha1.PhaseFailure, nil
+		}
+		log.Error(err, "error retrieving Dashboard Deployment")
+		return rolloutsmanagerv1alpha1.PhaseUnknown, err
+	}
+
+	if deploy.Spec.Replicas == nil {
+		return rolloutsmanagerv1alpha1.PhaseUnknown, nil
+	}
+
+	if deploy.Status.ReadyReplicas == *deploy.Spec.Replicas {
+		return rolloutsmanagerv1alpha1.PhaseAvailable, nil
+	}
+
+	return rolloutsmanagerv1alpha1.PhasePending, nil
+}
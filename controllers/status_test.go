@@ -65,6 +65,9 @@ var _ = Describe("RolloutManager Test", func() {
 
 		Expect(*rr.rolloutController).To(Equal(rolloutsmanagerv1alpha1.PhaseAvailable))
 		Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhaseAvailable))
+		Expect(rr.conditions).To(HaveLen(1))
+		Expect(rr.conditions[0].Type).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy))
+		Expect(rr.conditions[0].Status).To(Equal(metav1.ConditionTrue))
 
 	})
 })
@@ -0,0 +1,304 @@
+package rollouts
+
+import (
+	"fmt"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// trafficRoutingProvider identifies a traffic routing provider of .spec.trafficRouting that requires specific RBAC rules.
+type trafficRoutingProvider string
+
+const (
+	trafficRoutingProviderIstio          trafficRoutingProvider = "istio"
+	trafficRoutingProviderSMI            trafficRoutingProvider = "smi"
+	trafficRoutingProviderAmbassador     trafficRoutingProvider = "ambassador"
+	trafficRoutingProviderALB            trafficRoutingProvider = "alb"
+	trafficRoutingProviderAppMesh        trafficRoutingProvider = "appMesh"
+	trafficRoutingProviderTraefik        trafficRoutingProvider = "traefik"
+	trafficRoutingProviderAPISIX         trafficRoutingProvider = "apisix"
+	trafficRoutingProviderOpenShiftRoute trafficRoutingProvider = "openShiftRoute"
+)
+
+// trafficRoutingProviders are the traffic routing providers with specific RBAC rules, in the order in which their rules are added by GetPolicyRules.
+var trafficRoutingProviders = []trafficRoutingProvider{
+	trafficRoutingProviderIstio,
+	trafficRoutingProviderSMI,
+	trafficRoutingProviderAmbassador,
+	trafficRoutingProviderALB,
+	trafficRoutingProviderAppMesh,
+	trafficRoutingProviderTraefik,
+	trafficRoutingProviderAPISIX,
+	trafficRoutingProviderOpenShiftRoute,
+}
+
+// trafficRoutingPolicyRules maps each traffic routing provider to the rules of GetPolicyRules that are only required by that provider.
+// Endpoints are read by the ALB provider to verify the registered targets of a target group.
+var trafficRoutingPolicyRules = map[trafficRoutingProvider][]rbacv1.PolicyRule{
+	trafficRoutingProviderIstio: {
+		{
+			APIGroups: []string{
+				"networking.istio.io",
+			},
+			Resources: []string{
+				"virtualservices",
+				"destinationrules",
+			},
+			Verbs: []string{
+				"watch",
+				"get",
+				"update",
+				"patch",
+				"list",
+			},
+		},
+	},
+	trafficRoutingProviderSMI: {
+		{
+			APIGroups: []string{
+				"split.smi-spec.io",
+			},
+			Resources: []string{
+				"trafficsplits",
+			},
+			Verbs: []string{
+				"create",
+				"watch",
+				"get",
+				"update",
+				"patch",
+			},
+		},
+	},
+	trafficRoutingProviderAmbassador: {
+		{
+			APIGroups: []string{
+				"getambassador.io",
+				"x.getambassador.io",
+			},
+			Resources: []string{
+				"mappings",
+				"ambassadormappings",
+			},
+			Verbs: []string{
+				"create",
+				"watch",
+				"get",
+				"update",
+				"list",
+				"delete",
+			},
+		},
+	},
+	trafficRoutingProviderALB: {
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"endpoints",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
+		{
+			APIGroups: []string{
+				"elbv2.k8s.aws",
+				"eks.amazonaws.com",
+			},
+			Resources: []string{
+				"targetgroupbindings",
+			},
+			Verbs: []string{
+				"list",
+				"get",
+			},
+		},
+	},
+	trafficRoutingProviderAppMesh: {
+		{
+			APIGroups: []string{
+				"appmesh.k8s.aws",
+			},
+			Resources: []string{
+				"virtualservices",
+			},
+			Verbs: []string{
+				"watch",
+				"get",
+				"list",
+			},
+		},
+		{
+			APIGroups: []string{
+				"appmesh.k8s.aws",
+			},
+			Resources: []string{
+				"virtualnodes",
+				"virtualrouters",
+			},
+			Verbs: []string{
+				"watch",
+				"get",
+				"list",
+				"update",
+				"patch",
+			},
+		},
+	},
+	trafficRoutingProviderTraefik: {
+		{
+			APIGroups: []string{
+				"traefik.containo.us",
+				"traefik.io",
+			},
+			Resources: []string{
+				"traefikservices",
+			},
+			Verbs: []string{
+				"watch",
+				"get",
+				"update",
+			},
+		},
+	},
+	trafficRoutingProviderAPISIX: {
+		{
+			APIGroups: []string{
+				"apisix.apache.org",
+			},
+			Resources: []string{
+				"apisixroutes",
+			},
+			Verbs: []string{
+				"watch",
+				"get",
+				"update",
+			},
+		},
+	},
+	trafficRoutingProviderOpenShiftRoute: {
+		{
+			APIGroups: []string{
+				"route.openshift.io",
+			},
+			Resources: []string{
+				"routes",
+			},
+			Verbs: []string{
+				"create",
+				"watch",
+				"get",
+				"update",
+				"patch",
+				"list",
+			},
+		},
+	},
+}
+
+// isTrafficRoutingProviderEnabled returns true if the given traffic routing provider is declared in .spec.trafficRouting.
+func isTrafficRoutingProviderEnabled(trafficRouting *rolloutsmanagerv1alpha1.RolloutManagerTrafficRoutingSpec, provider trafficRoutingProvider) bool {
+
+	if provider == trafficRoutingProviderOpenShiftRoute {
+		return trafficRouting.IsOpenShiftRouteEnabled()
+	}
+
+	if trafficRouting == nil {
+		return false
+	}
+
+	switch provider {
+	case trafficRoutingProviderIstio:
+		return trafficRouting.Istio != nil
+	case trafficRoutingProviderSMI:
+		return trafficRouting.SMI != nil
+	case trafficRoutingProviderAmbassador:
+		return trafficRouting.Ambassador != nil
+	case trafficRoutingProviderALB:
+		return trafficRouting.ALB != nil
+	case trafficRoutingProviderAppMesh:
+		return trafficRouting.AppMesh != nil
+	case trafficRoutingProviderTraefik:
+		return trafficRouting.Traefik != nil
+	case trafficRoutingProviderAPISIX:
+		return trafficRouting.APISIX != nil
+	}
+
+	return false
+}
+
+// getLeastPrivilegePolicyRules returns the core rules of GetPolicyRules, plus the rules required by the traffic routing providers declared in .spec.trafficRouting.
+// Ingresses are part of the core rule set, as the Rollouts controller always watches them.
+func getLeastPrivilegePolicyRules(trafficRouting *rolloutsmanagerv1alpha1.RolloutManagerTrafficRoutingSpec) []rbacv1.PolicyRule {
+
+	policyRules := getCorePolicyRules()
+	for _, provider := range trafficRoutingProviders {
+		if isTrafficRoutingProviderEnabled(trafficRouting, provider) {
+			policyRules = append(policyRules, trafficRoutingPolicyRules[provider]...)
+		}
+	}
+
+	return policyRules
+}
+
+// getTrafficRoutingArgs returns the Rollouts controller command arguments for the traffic routing providers declared in .spec.trafficRouting, or an error if it is invalid.
+func getTrafficRoutingArgs(trafficRouting *rolloutsmanagerv1alpha1.RolloutManagerTrafficRoutingSpec) ([]string, error) {
+
+	if trafficRouting == nil {
+		return nil, nil
+	}
+
+	args := []string{}
+
+	// appendIfSet appends the flag and value to args, if a value is set
+	appendIfSet := func(flag string, value string) {
+		if value != "" {
+			args = append(args, flag, value)
+		}
+	}
+
+	if alb := trafficRouting.ALB; alb != nil {
+		for _, ingressClass := range alb.IngressClasses {
+			if ingressClass == "" {
+				return nil, fmt.Errorf("invalid .spec.trafficRouting.alb.ingressClasses: ingress class must not be empty")
+			}
+			args = append(args, "--alb-ingress-classes", ingressClass)
+		}
+		if alb.VerifyTargetGroup {
+			args = append(args, "--aws-verify-target-group")
+		}
+		appendIfSet("--aws-target-group-binding-api-version", alb.TargetGroupBindingAPIVersion)
+		appendIfSet("--alb-tag-key-resource-id", alb.TagKeyResourceID)
+	}
+
+	if ambassador := trafficRouting.Ambassador; ambassador != nil {
+		appendIfSet("--ambassador-api-version", ambassador.APIVersion)
+	}
+
+	if appMesh := trafficRouting.AppMesh; appMesh != nil {
+		appendIfSet("--appmesh-crd-version", appMesh.CRDVersion)
+	}
+
+	if istio := trafficRouting.Istio; istio != nil {
+		appendIfSet("--istio-api-version", istio.APIVersion)
+	}
+
+	if nginx := trafficRouting.Nginx; nginx != nil {
+		for _, ingressClass := range nginx.IngressClasses {
+			if ingressClass == "" {
+				return nil, fmt.Errorf("invalid .spec.trafficRouting.nginx.ingressClasses: ingress class must not be empty")
+			}
+			args = append(args, "--nginx-ingress-classes", ingressClass)
+		}
+	}
+
+	if traefik := trafficRouting.Traefik; traefik != nil {
+		appendIfSet("--traefik-api-group", traefik.APIGroup)
+		appendIfSet("--traefik-version", traefik.Version)
+	}
+
+	return args, nil
+}
@@ -0,0 +1,253 @@
+package rollouts
+
+import (
+	"context"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logger "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Traffic routing tests", func() {
+
+	It("should not render any arguments when .spec.trafficRouting is not set", func() {
+		args, err := getTrafficRoutingArgs(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args).To(BeEmpty())
+	})
+
+	It("should render the options of the declared providers as command arguments", func() {
+		args, err := getTrafficRoutingArgs(&v1alpha1.RolloutManagerTrafficRoutingSpec{
+			ALB: &v1alpha1.ALBTrafficRoutingSpec{
+				IngressClasses:               []string{"alb", "alb-internal"},
+				VerifyTargetGroup:            true,
+				TargetGroupBindingAPIVersion: "elbv2.k8s.aws/v1beta1",
+				TagKeyResourceID:             "ingress.k8s.aws/resource",
+			},
+			Ambassador: &v1alpha1.AmbassadorTrafficRoutingSpec{APIVersion: "getambassador.io/v3alpha1"},
+			APISIX:     &v1alpha1.APISIXTrafficRoutingSpec{},
+			AppMesh:    &v1alpha1.AppMeshTrafficRoutingSpec{CRDVersion: "v1beta2"},
+			Istio:      &v1alpha1.IstioTrafficRoutingSpec{APIVersion: "v1beta1"},
+			Nginx:      &v1alpha1.NginxTrafficRoutingSpec{IngressClasses: []string{"nginx-public"}},
+			SMI:        &v1alpha1.SMITrafficRoutingSpec{},
+			Traefik:    &v1alpha1.TraefikTrafficRoutingSpec{APIGroup: "traefik.io", Version: "traefik.io/v1alpha1"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args).To(Equal([]string{
+			"--alb-ingress-classes", "alb",
+			"--alb-ingress-classes", "alb-internal",
+			"--aws-verify-target-group",
+			"--aws-target-group-binding-api-version", "elbv2.k8s.aws/v1beta1",
+			"--alb-tag-key-resource-id", "ingress.k8s.aws/resource",
+			"--ambassador-api-version", "getambassador.io/v3alpha1",
+			"--appmesh-crd-version", "v1beta2",
+			"--istio-api-version", "v1beta1",
+			"--nginx-ingress-classes", "nginx-public",
+			"--traefik-api-group", "traefik.io",
+			"--traefik-version", "traefik.io/v1alpha1",
+		}))
+	})
+
+	It("should not render arguments for providers without options", func() {
+		args, err := getTrafficRoutingArgs(&v1alpha1.RolloutManagerTrafficRoutingSpec{
+			Istio: &v1alpha1.IstioTrafficRoutingSpec{},
+			SMI:   &v1alpha1.SMITrafficRoutingSpec{},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args).To(BeEmpty())
+	})
+
+	DescribeTable("should reject empty ingress classes", func(trafficRouting v1alpha1.RolloutManagerTrafficRoutingSpec, expectedError string) {
+		_, err := getTrafficRoutingArgs(&trafficRouting)
+		Expect(err).To(MatchError(expectedError))
+	},
+		Entry("alb", v1alpha1.RolloutManagerTrafficRoutingSpec{ALB: &v1alpha1.ALBTrafficRoutingSpec{IngressClasses: []string{""}}},
+			"invalid .spec.trafficRouting.alb.ingressClasses: ingress class must not be empty"),
+		Entry("nginx", v1alpha1.RolloutManagerTrafficRoutingSpec{Nginx: &v1alpha1.NginxTrafficRoutingSpec{IngressClasses: []string{"nginx", ""}}},
+			"invalid .spec.trafficRouting.nginx.ingressClasses: ingress class must not be empty"),
+	)
+
+	It("should include the traffic routing arguments in the Rollouts command, and detect conflicts with ExtraCommandArgs", func() {
+		cr := *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{
+				ALB: &v1alpha1.ALBTrafficRoutingSpec{VerifyTargetGroup: true},
+			}
+		})
+
+		args, err := getRolloutsCommandArgs(cr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(args).To(ContainElement("--aws-verify-target-group"))
+
+		cr.Spec.ExtraCommandArgs = []string{"--aws-verify-target-group=false"}
+		_, err = getRolloutsCommandArgs(cr)
+		Expect(err).To(MatchError(ContainSubstring("--aws-verify-target-group=false is already part of the default command arguments")))
+	})
+
+	Context("OpenShift Route traffic router plugin", func() {
+
+		var (
+			ctx context.Context
+			a   v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			a = *makeTestRolloutManager()
+			r = makeTestReconciler(&a)
+			Expect(createNamespace(r, a.Namespace)).To(Succeed())
+		})
+
+		It("should be enabled by default", func() {
+			Expect(a.Spec.TrafficRouting.IsOpenShiftRouteEnabled()).To(BeTrue())
+
+			a.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{OpenShiftRoute: &v1alpha1.OpenShiftRouteTrafficRoutingSpec{}}
+			Expect(a.Spec.TrafficRouting.IsOpenShiftRouteEnabled()).To(BeTrue())
+		})
+
+		It("should be removed from the Rollouts ConfigMap when disabled, and added back when re-enabled", func() {
+			Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring(OpenShiftRolloutPluginName))
+
+			By("disabling the OpenShift Route plugin")
+			a.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{
+				OpenShiftRoute: &v1alpha1.OpenShiftRouteTrafficRoutingSpec{Enabled: boolPtr(false)},
+			}
+			Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).ToNot(ContainSubstring(OpenShiftRolloutPluginName))
+
+			By("verifying that the plugin location is not required when the plugin is disabled")
+			r.OpenShiftRoutePluginLocation = ""
+			Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+			By("re-enabling the OpenShift Route plugin")
+			r.OpenShiftRoutePluginLocation = "file://non-empty-test-url"
+			a.Spec.TrafficRouting.OpenShiftRoute.Enabled = boolPtr(true)
+			Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, configMap)).To(Succeed())
+			Expect(configMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring(OpenShiftRolloutPluginName))
+		})
+	})
+
+	Context("least-privilege RBAC", func() {
+
+		var (
+			ctx context.Context
+			a   v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+		)
+
+		// hasRuleForAPIGroup returns true if one of the rules grants access to the given API group
+		hasRuleForAPIGroup := func(rules []rbacv1.PolicyRule, apiGroup string) bool {
+			for _, rule := range rules {
+				for _, group := range rule.APIGroups {
+					if group == apiGroup {
+						return true
+					}
+				}
+			}
+			return false
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			a = *makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Spec.NamespaceScoped = true
+				rm.Spec.RBAC = &v1alpha1.RolloutManagerRBACSpec{LeastPrivilege: true}
+			})
+			r = makeTestReconciler(&a)
+			Expect(createNamespace(r, a.Namespace)).To(Succeed())
+		})
+
+		It("should return all the rules of GetPolicyRules when least-privilege mode is not enabled", func() {
+			a.Spec.RBAC = nil
+			Expect(getPolicyRules(a)).To(Equal(GetPolicyRules()))
+
+			a.Spec.RBAC = &v1alpha1.RolloutManagerRBACSpec{}
+			Expect(getPolicyRules(a)).To(Equal(GetPolicyRules()))
+		})
+
+		It("should only return the core rules, and the rules of the OpenShift Route plugin, when no provider is declared", func() {
+			rules := getPolicyRules(a)
+
+			for _, apiGroup := range []string{"argoproj.io", "apps", "coordination.k8s.io", "networking.k8s.io", "batch", "route.openshift.io"} {
+				Expect(hasRuleForAPIGroup(rules, apiGroup)).To(BeTrue(), apiGroup)
+			}
+
+			for _, apiGroup := range []string{"networking.istio.io", "split.smi-spec.io", "getambassador.io", "elbv2.k8s.aws", "appmesh.k8s.aws", "traefik.containo.us", "apisix.apache.org"} {
+				Expect(hasRuleForAPIGroup(rules, apiGroup)).To(BeFalse(), apiGroup)
+			}
+			Expect(rules).ToNot(ContainElement(HaveField("Resources", ContainElement("endpoints"))))
+
+			By("disabling the OpenShift Route plugin")
+			a.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{
+				OpenShiftRoute: &v1alpha1.OpenShiftRouteTrafficRoutingSpec{Enabled: boolPtr(false)},
+			}
+			Expect(hasRuleForAPIGroup(getPolicyRules(a), "route.openshift.io")).To(BeFalse())
+		})
+
+		It("should add the rules of the declared providers, in the order of GetPolicyRules", func() {
+			a.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{
+				ALB:   &v1alpha1.ALBTrafficRoutingSpec{},
+				Istio: &v1alpha1.IstioTrafficRoutingSpec{},
+			}
+			rules := getPolicyRules(a)
+
+			Expect(hasRuleForAPIGroup(rules, "networking.istio.io")).To(BeTrue())
+			Expect(hasRuleForAPIGroup(rules, "elbv2.k8s.aws")).To(BeTrue())
+			Expect(rules).To(ContainElement(HaveField("Resources", ContainElement("endpoints"))))
+			Expect(hasRuleForAPIGroup(rules, "split.smi-spec.io")).To(BeFalse())
+
+			By("declaring every provider")
+			a.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{
+				ALB:        &v1alpha1.ALBTrafficRoutingSpec{},
+				Ambassador: &v1alpha1.AmbassadorTrafficRoutingSpec{},
+				APISIX:     &v1alpha1.APISIXTrafficRoutingSpec{},
+				AppMesh:    &v1alpha1.AppMeshTrafficRoutingSpec{},
+				Istio:      &v1alpha1.IstioTrafficRoutingSpec{},
+				Nginx:      &v1alpha1.NginxTrafficRoutingSpec{},
+				SMI:        &v1alpha1.SMITrafficRoutingSpec{},
+				Traefik:    &v1alpha1.TraefikTrafficRoutingSpec{},
+			}
+			Expect(getPolicyRules(a)).To(Equal(GetPolicyRules()))
+
+			By("verifying that every provider grants its own rules")
+			for _, provider := range trafficRoutingProviders {
+				Expect(trafficRoutingPolicyRules[provider]).ToNot(BeEmpty(), string(provider))
+				Expect(isTrafficRoutingProviderEnabled(a.Spec.TrafficRouting, provider)).To(BeTrue(), string(provider))
+			}
+		})
+
+		It("should update the Role when a provider is declared, and report the effective rules in status", func() {
+			role, err := r.reconcileRolloutsRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role.Rules).To(Equal(getLeastPrivilegePolicyRules(nil)))
+			Expect(hasRuleForAPIGroup(role.Rules, "networking.istio.io")).To(BeFalse())
+
+			By("declaring the Istio provider")
+			a.Spec.TrafficRouting = &v1alpha1.RolloutManagerTrafficRoutingSpec{Istio: &v1alpha1.IstioTrafficRoutingSpec{}}
+			role, err = r.reconcileRolloutsRole(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hasRuleForAPIGroup(role.Rules, "networking.istio.io")).To(BeTrue())
+
+			By("reporting the effective rules in status")
+			Expect(updateStatusConditionOfRolloutManager(ctx, reconcileStatusResult{
+				condition:   createCondition(""),
+				policyRules: getPolicyRules(a),
+			}, &a, r.Client, logger.FromContext(ctx))).To(Succeed())
+
+			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&a), &a)).To(Succeed())
+			Expect(a.Status.PolicyRules).To(Equal(role.Rules))
+		})
+	})
+})
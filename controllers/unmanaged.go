@@ -0,0 +1,60 @@
+package rollouts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// isComponentUnmanaged returns true if the resources of the given component should not be created, updated or deleted by the operator.
+func isComponentUnmanaged(cr rolloutsmanagerv1alpha1.RolloutManager, component rolloutsmanagerv1alpha1.RolloutManagerComponent) bool {
+	return slices.Contains(cr.Spec.UnmanagedComponents, component)
+}
+
+// getPausedStatusResult returns the status of a RolloutManager whose reconciliation is paused: the Reconciled condition of the last reconciliation is kept as is,
+// and .status.observedGeneration is not updated, as the resources are not reconciled against the current .spec.
+func getPausedStatusResult(cr rolloutsmanagerv1alpha1.RolloutManager) reconcileStatusResult {
+
+	res := wrapCondition(createCondition(""))
+	if condition := meta.FindStatusCondition(cr.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerConditionType); condition != nil {
+		res = wrapCondition(*condition)
+	}
+
+	res.generationNotReconciled = true
+	return res
+}
+
+// getUnmanagedCondition returns the Unmanaged condition of the RolloutManager, or nil if its reconciliation is not paused and all its components are managed.
+func getUnmanagedCondition(cr rolloutsmanagerv1alpha1.RolloutManager) *metav1.Condition {
+
+	if cr.Spec.Paused {
+		return &metav1.Condition{
+			Type:    rolloutsmanagerv1alpha1.RolloutManagerConditionTypeUnmanaged,
+			Status:  metav1.ConditionTrue,
+			Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonReconciliationPaused,
+			Message: "reconciliation of the RolloutManager is paused: its resources are not modified until .spec.paused is removed",
+		}
+	}
+
+	if len(cr.Spec.UnmanagedComponents) == 0 {
+		return nil
+	}
+
+	components := []string{}
+	for _, component := range cr.Spec.UnmanagedComponents {
+		if !slices.Contains(components, string(component)) {
+			components = append(components, string(component))
+		}
+	}
+
+	return &metav1.Condition{
+		Type:    rolloutsmanagerv1alpha1.RolloutManagerConditionTypeUnmanaged,
+		Status:  metav1.ConditionTrue,
+		Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonComponentsUnmanaged,
+		Message: fmt.Sprintf("the resources of the following components are not modified by the operator: %s", strings.Join(components, ", ")),
+	}
+}
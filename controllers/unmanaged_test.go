@@ -0,0 +1,178 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("Paused and unmanaged components tests", func() {
+
+	var (
+		ctx context.Context
+		rm  *v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+		req ctrl.Request
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rm = makeTestRolloutManager()
+		r = makeTestReconciler(rm)
+		Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+		os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Name: rm.Name, Namespace: rm.Namespace}}
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+	})
+
+	reconcileWith := func(update func(rm *v1alpha1.RolloutManager)) {
+		Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+		update(rm)
+		Expect(r.Client.Update(ctx, rm)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+	}
+
+	It("should not revert the changes to the resources while the reconciliation is paused", func() {
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {})
+		Expect(meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeUnmanaged)).To(BeNil())
+
+		By("pausing the reconciliation, and hot-patching the image of the Deployment")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Paused = true
+		})
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		deployment.Spec.Template.Spec.Containers[0].Image = "quay.io/my/argo-rollouts:hotfix"
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Version = "v1.8.0"
+		})
+
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("quay.io/my/argo-rollouts:hotfix"))
+
+		condition := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeUnmanaged)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonReconciliationPaused))
+
+		reconciled := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionType)
+		Expect(reconciled.Reason).To(Equal(v1alpha1.RolloutManagerReasonSuccess), "the Reconciled condition of the last reconciliation should be kept")
+
+		By("resuming the reconciliation, which should apply the desired state again")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Paused = false
+		})
+
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(HaveSuffix(":v1.8.0"))
+		Expect(meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeUnmanaged)).To(BeNil())
+	})
+
+	It("should neither create nor update the resources of the unmanaged components", func() {
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.UnmanagedComponents = []v1alpha1.RolloutManagerComponent{v1alpha1.ComponentDeployment, v1alpha1.ComponentNetworkPolicy}
+		})
+
+		err := fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &appsv1.Deployment{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		err = fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &networkingv1.NetworkPolicy{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &corev1.ServiceAccount{})).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsMetricsServiceName, &corev1.Service{})).To(Succeed())
+
+		condition := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeUnmanaged)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonComponentsUnmanaged))
+		Expect(condition.Message).To(HaveSuffix(": Deployment, NetworkPolicy"))
+
+		By("hot-patching the ClusterRole, while RBAC is unmanaged")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.UnmanagedComponents = []v1alpha1.RolloutManagerComponent{v1alpha1.ComponentRBAC}
+		})
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &appsv1.Deployment{})).To(Succeed())
+
+		clusterRole := &rbacv1.ClusterRole{}
+		Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsResourceName, clusterRole)).To(Succeed())
+		clusterRole.Rules = append(clusterRole.Rules, rbacv1.PolicyRule{APIGroups: []string{"example.com"}, Resources: []string{"widgets"}, Verbs: []string{"get"}})
+		Expect(r.Client.Update(ctx, clusterRole)).To(Succeed())
+
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {})
+		Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsResourceName, clusterRole)).To(Succeed())
+		Expect(clusterRole.Rules).To(ContainElement(HaveField("APIGroups", []string{"example.com"})))
+
+		By("hot-patching the ServiceAccount, and enabling the Dashboard, while RBAC is unmanaged")
+		sa := &corev1.ServiceAccount{}
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		sa.Labels["app.kubernetes.io/name"] = "hot-patched"
+		Expect(r.Client.Update(ctx, sa)).To(Succeed())
+
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.Dashboard = &v1alpha1.RolloutManagerDashboardSpec{Enabled: true}
+		})
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		Expect(sa.Labels).To(HaveKeyWithValue("app.kubernetes.io/name", "hot-patched"))
+
+		dashboardDeployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsDashboardResourceName, dashboardDeployment)).To(Succeed())
+		Expect(dashboardDeployment.Spec.Template.Spec.ServiceAccountName).To(Equal(DefaultArgoRolloutsDashboardResourceName))
+		err = fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsDashboardResourceName, &corev1.ServiceAccount{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		err = fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, &rbacv1.ClusterRole{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		err = fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, &rbacv1.ClusterRoleBinding{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("managing all the components again")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.UnmanagedComponents = nil
+		})
+		Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsResourceName, clusterRole)).To(Succeed())
+		Expect(clusterRole.Rules).ToNot(ContainElement(HaveField("APIGroups", []string{"example.com"})))
+		Expect(meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeUnmanaged)).To(BeNil())
+
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, sa)).To(Succeed())
+		Expect(sa.Labels).To(HaveKeyWithValue("app.kubernetes.io/name", DefaultArgoRolloutsResourceName))
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsDashboardResourceName, &corev1.ServiceAccount{})).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, &rbacv1.ClusterRoleBinding{})).To(Succeed())
+	})
+
+	It("should not create the PodDisruptionBudget while the Deployment is unmanaged", func() {
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.HA = &v1alpha1.RolloutManagerHASpec{Enabled: true}
+			rm.Spec.UnmanagedComponents = []v1alpha1.RolloutManagerComponent{v1alpha1.ComponentDeployment}
+		})
+
+		err := fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &policyv1.PodDisruptionBudget{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("managing the Deployment again")
+		reconcileWith(func(rm *v1alpha1.RolloutManager) {
+			rm.Spec.UnmanagedComponents = nil
+		})
+		Expect(fetchObject(ctx, r.Client, rm.Namespace, DefaultArgoRolloutsResourceName, &policyv1.PodDisruptionBudget{})).To(Succeed())
+	})
+})
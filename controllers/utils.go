@@ -30,6 +30,9 @@ type pluginItem struct {
 	Name     string `json:"name" yaml:"name"`
 	Location string `json:"location" yaml:"location"`
 	Sha256   string `json:"sha256" yaml:"sha256"`
+
+	// Args is omitted when empty, so that the generated plugin configuration of plugins which do not use it is unchanged.
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
 }
 
 func setRolloutsLabelsAndAnnotationsToObject(obj *metav1.ObjectMeta, cr rolloutsmanagerv1alpha1.RolloutManager) {
@@ -98,30 +101,6 @@ func appendStringMap(src map[string]string, add map[string]string) map[string]st
 	return res
 }
 
-// In go, reflect.DeepEqual(a, b), on maps a and b, will return false even when len(a) == 0 and len(b) == 0. This is due to a nil map being considered different from an empty map.
-// This function handles this case better: if maps both have a length of 0, we consider them always equal.
-func areStringMapsEqual(one map[string]string, two map[string]string) bool {
-
-	isOneEmpty := false
-
-	isTwoEmpty := false
-
-	if len(one) == 0 {
-		isOneEmpty = true
-	}
-
-	if len(two) == 0 {
-		isTwoEmpty = true
-	}
-
-	if isOneEmpty && isTwoEmpty {
-		return true
-	}
-
-	return reflect.DeepEqual(one, two)
-
-}
-
 // combineStringMaps will combine multiple maps: maps defined earlier in the 'maps' slice may have their values overriden by maps defined later in the 'maps' slice.
 func combineStringMaps(maps ...map[string]string) map[string]string {
 
@@ -222,13 +201,31 @@ func contains(s []string, g string) bool {
 	return false
 }
 
+// isFlagArg returns true if the command argument is a flag (for example '--flag' or '--flag=value'), rather than a value.
+func isFlagArg(arg string) bool {
+	return len(arg) > 2 && arg[:2] == "--"
+}
+
+// flagName returns the name of the flag of a command argument, for example '--flag' for both '--flag' and '--flag=value'.
+func flagName(arg string) string {
+	name, _, _ := strings.Cut(arg, "=")
+	return name
+}
+
 // isMergable returns error if any of the extraArgs is already part of the default command Arguments.
+// Flags are compared by name, so that '--flag=value' conflicts with '--flag', and vice versa.
 func isMergable(extraArgs []string, cmd []string) error {
 	if len(extraArgs) > 0 {
+		cmdFlags := []string{}
+		for _, arg := range cmd {
+			if isFlagArg(arg) {
+				cmdFlags = append(cmdFlags, flagName(arg))
+			}
+		}
 		for _, arg := range extraArgs {
-			if len(arg) > 2 && arg[:2] == "--" {
-				if ok := contains(cmd, arg); ok {
-					err := errors.New("duplicate argument error")
+			if isFlagArg(arg) {
+				if ok := contains(cmdFlags, flagName(arg)); ok {
+					err := fmt.Errorf("duplicate argument error: %s is already part of the default command arguments", arg)
 					log.Error(err, fmt.Sprintf("Arg %s is already part of the default command arguments", arg))
 					return err
 				}
@@ -369,6 +366,20 @@ func updateStatusConditionOfRolloutManager(ctx context.Context, rr reconcileStat
 
 	changed, newConditions := insertOrUpdateConditionsInSlice(rr.condition, rm.Status.Conditions)
 
+	for _, condition := range rr.conditions {
+		conditionChanged := false
+		if conditionChanged, newConditions = insertOrUpdateConditionsInSlice(condition, newConditions); conditionChanged {
+			changed = true
+		}
+	}
+
+	for _, conditionType := range rr.removedConditions {
+		conditionRemoved := false
+		if conditionRemoved, newConditions = removeConditionsFromSlice(conditionType, newConditions); conditionRemoved {
+			changed = true
+		}
+	}
+
 	if rr.phase != nil && *rr.phase != rm.Status.Phase {
 		rm.Status.Phase = *rr.phase
 		changed = true
@@ -379,6 +390,58 @@ func updateStatusConditionOfRolloutManager(ctx context.Context, rr reconcileStat
 		changed = true
 	}
 
+	if rr.dashboard != nil && *rr.dashboard != rm.Status.Dashboard {
+		rm.Status.Dashboard = *rr.dashboard
+		changed = true
+	}
+
+	if rr.controller != nil && !reflect.DeepEqual(rr.controller, rm.Status.Controller) {
+		rm.Status.Controller = rr.controller
+		changed = true
+	}
+
+	if rr.policyRules != nil && !reflect.DeepEqual(rr.policyRules, rm.Status.PolicyRules) {
+		rm.Status.PolicyRules = rr.policyRules
+		changed = true
+	}
+
+	if rr.plugins != nil {
+		// Preserve the last transition time of the plugin conditions, and treat nil and empty as equal
+		if plugins := mergePluginStatuses(rm.Status.Plugins, rr.plugins); (len(plugins) != 0 || len(rm.Status.Plugins) != 0) && !reflect.DeepEqual(plugins, rm.Status.Plugins) {
+			rm.Status.Plugins = plugins
+			changed = true
+		}
+	}
+
+	if rr.workload != nil && *rr.workload != rm.Status.RolloutManagerWorkloadStatus {
+		rm.Status.RolloutManagerWorkloadStatus = *rr.workload
+		changed = true
+	}
+
+	if !rr.generationNotReconciled && rm.Status.ObservedGeneration != rm.Generation {
+		rm.Status.ObservedGeneration = rm.Generation
+		changed = true
+	}
+
+	if rr.leader != nil {
+		leader := rr.leader
+		if reflect.DeepEqual(*leader, rolloutsmanagerv1alpha1.RolloutManagerLeaderStatus{}) {
+			leader = nil
+		}
+		if !reflect.DeepEqual(leader, rm.Status.Leader) {
+			rm.Status.Leader = leader
+			changed = true
+		}
+	}
+
+	if rr.plannedChanges != nil && (len(rr.plannedChanges) != 0 || len(rm.Status.PlannedChanges) != 0) && !reflect.DeepEqual(rr.plannedChanges, rm.Status.PlannedChanges) {
+		rm.Status.PlannedChanges = rr.plannedChanges
+		if len(rr.plannedChanges) == 0 {
+			rm.Status.PlannedChanges = nil
+		}
+		changed = true
+	}
+
 	if changed {
 		rm.Status.Conditions = newConditions
 
@@ -476,50 +539,12 @@ func createCondition(message string, reason ...string) metav1.Condition {
 	}
 }
 
-// removeUserLabelsAndAnnotations will remove any miscellaneous labels/annotations from obj, that are not used or expected by argo-rollouts-manager. For example, if a user added a label, "my-key": "my-value", to annotations of a Role that is created by our operator, this function would remove that label from 'obj'.
-func removeUserLabelsAndAnnotations(obj *metav1.ObjectMeta, cr rolloutsmanagerv1alpha1.RolloutManager) {
-
-	defaultLabelsAndAnnotations := metav1.ObjectMeta{}
-	setRolloutsLabelsAndAnnotationsToObject(&defaultLabelsAndAnnotations, cr)
-
-	for objectLabelKey := range obj.Labels {
-
-		// We add this label to the aggregated cluster roles we generate, so we should not process it as a user label.
-		if strings.Contains(objectLabelKey, "rbac.authorization.k8s.io/") && strings.Contains(objectLabelKey, "aggregate") {
-			continue
-		}
-
-		existsInDefault := false
-
-		for defaultLabelKey := range defaultLabelsAndAnnotations.Labels {
-
-			if defaultLabelKey == objectLabelKey {
-				existsInDefault = true
-				break
-			}
-		}
-
-		if !existsInDefault {
-			delete(obj.Labels, objectLabelKey)
-		}
-
-	}
-
-	for objectAnnotationKey := range obj.Annotations {
-
-		existsInDefault := false
-
-		for defaultAnnotationKey := range defaultLabelsAndAnnotations.Annotations {
-
-			if defaultAnnotationKey == objectAnnotationKey {
-				existsInDefault = true
-				break
-			}
-		}
-
-		if !existsInDefault {
-			delete(obj.Annotations, objectAnnotationKey)
-		}
+// createConditionOfType returns a condition of the given type, with the given status, reason and message.
+func createConditionOfType(conditionType string, status metav1.ConditionStatus, reason string, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
 	}
-
 }
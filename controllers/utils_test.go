@@ -12,10 +12,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	logger "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -65,6 +67,27 @@ var _ = Describe("updateStatusConditionOfRolloutManager tests", func() {
 		})
 	})
 
+	When("the generation of the RolloutManager was not reconciled", func() {
+		It("should only set the observed generation once it is reconciled", func() {
+
+			rolloutsManager.Generation = 2
+			Expect(k8sClient.Create(ctx, &rolloutsManager)).To(Succeed())
+
+			rsr := reconcileStatusResult{
+				generationNotReconciled: true,
+			}
+			Expect(updateStatusConditionOfRolloutManager(ctx, rsr, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
+			Expect(rolloutsManager.Status.ObservedGeneration).To(BeZero())
+
+			Expect(updateStatusConditionOfRolloutManager(ctx, reconcileStatusResult{}, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
+			Expect(rolloutsManager.Status.ObservedGeneration).To(Equal(int64(2)))
+		})
+	})
+
 	When("reconcileStatusResult has a non-nil rolloutController", func() {
 		It("should set the phase on the RolloutManager status", func() {
 
@@ -417,99 +440,6 @@ var _ = Describe("validateRolloutsScope tests", func() {
 	})
 })
 
-var _ = Describe("removeUserLabelsAndAnnotations tests", func() {
-	var (
-		obj       metav1.ObjectMeta
-		cr        rolloutsmanagerv1alpha1.RolloutManager
-		ctx       context.Context
-		k8sClient client.WithWatch
-	)
-
-	BeforeEach(func() {
-		s := scheme.Scheme
-		Expect(rolloutsmanagerv1alpha1.AddToScheme(s)).To(Succeed())
-
-		ctx = context.Background()
-		log = logger.FromContext(ctx)
-		k8sClient = fake.NewClientBuilder().WithStatusSubresource(&rolloutsmanagerv1alpha1.RolloutManager{}).WithScheme(s).Build()
-
-		cr = rolloutsmanagerv1alpha1.RolloutManager{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test-rm-1",
-				Namespace: "test-ns-1",
-			},
-			Spec: rolloutsmanagerv1alpha1.RolloutManagerSpec{
-				NamespaceScoped: false,
-			},
-		}
-	})
-
-	DescribeTable("should correctly remove user labels and annotations",
-		func(initialLabels, initialAnnotations, expectedLabels, expectedAnnotations map[string]string) {
-			obj = metav1.ObjectMeta{
-				Labels:      initialLabels,
-				Annotations: initialAnnotations,
-			}
-
-			Expect(k8sClient.Create(ctx, &cr)).To(Succeed())
-
-			removeUserLabelsAndAnnotations(&obj, cr)
-
-			Expect(obj.Labels).To(Equal(expectedLabels))
-			Expect(obj.Annotations).To(Equal(expectedAnnotations))
-		},
-		Entry("when no user-defined labels or annotations exist",
-			map[string]string{},
-			map[string]string{},
-			map[string]string{},
-			map[string]string{},
-		),
-		Entry("when user-defined labels and annotations are present",
-			map[string]string{"user-label": "value"},
-			map[string]string{"user-annotation": "value"},
-			map[string]string{},
-			map[string]string{},
-		),
-		Entry("when user-defined labels are present and annotations are empty",
-			map[string]string{"user-label": "value"},
-			map[string]string{},
-			map[string]string{},
-			map[string]string{},
-		),
-		Entry("when user-defined labels are empty and annotations are present",
-			map[string]string{},
-			map[string]string{"user-annotation": "value"},
-			map[string]string{},
-			map[string]string{},
-		),
-		Entry("when both user and non-user-defined labels are present, and annotations are empty",
-			map[string]string{"user-label": "value",
-				"app.kubernetes.io/name":      DefaultArgoRolloutsResourceName,
-				"app.kubernetes.io/part-of":   DefaultArgoRolloutsResourceName,
-				"app.kubernetes.io/component": DefaultArgoRolloutsResourceName},
-			map[string]string{},
-			map[string]string{"app.kubernetes.io/name": DefaultArgoRolloutsResourceName,
-				"app.kubernetes.io/part-of":   DefaultArgoRolloutsResourceName,
-				"app.kubernetes.io/component": DefaultArgoRolloutsResourceName},
-			map[string]string{},
-		),
-		Entry("rbac aggregate-to- labels should not be removed, as we add these to ClusterRoles",
-			map[string]string{
-				"rbac.authorization.k8s.io/aggregate-to-admin": "true",
-				"app.kubernetes.io/name":                       DefaultArgoRolloutsResourceName,
-				"app.kubernetes.io/part-of":                    DefaultArgoRolloutsResourceName,
-				"app.kubernetes.io/component":                  DefaultArgoRolloutsResourceName},
-			map[string]string{},
-			map[string]string{
-				"rbac.authorization.k8s.io/aggregate-to-admin": "true",
-				"app.kubernetes.io/name":                       DefaultArgoRolloutsResourceName,
-				"app.kubernetes.io/part-of":                    DefaultArgoRolloutsResourceName,
-				"app.kubernetes.io/component":                  DefaultArgoRolloutsResourceName},
-			map[string]string{},
-		),
-	)
-})
-
 var _ = Describe("insertOrUpdateConditionsInSlice tests", func() {
 	var (
 		existingConditions []metav1.Condition
@@ -609,6 +539,9 @@ var _ = Describe("isMergable tests", func() {
 		Entry("extraArgs with no `--` args", []string{"arg1", "arg2"}, []string{"--cmd1", "--cmd2"}, false),
 		Entry("extraArgs with `--` args but no duplicates", []string{"--arg1", "--arg2"}, []string{"--cmd1", "--cmd2"}, false),
 		Entry("extraArgs with duplicate `--` args", []string{"--arg1", "--cmd1"}, []string{"--cmd1", "--cmd2"}, true),
+		Entry("extraArgs with duplicate `--flag=value` args", []string{"--cmd1=value"}, []string{"--cmd1", "value", "--cmd2"}, true),
+		Entry("extraArgs duplicating a `--flag=value` arg", []string{"--cmd2", "value"}, []string{"--cmd1", "--cmd2=value"}, true),
+		Entry("extraArgs whose value matches a default arg value", []string{"--arg1", "value"}, []string{"--cmd1", "value"}, false),
 	)
 })
 
@@ -691,23 +624,6 @@ var _ = Describe("setAdditionalRolloutsLabelsAndAnnotationsToObject tests", func
 	})
 })
 
-var _ = Describe("areStringMapsEqual tests", func() {
-	DescribeTable("areStringMapsEqual should match reflect.DeepEqual(), except when comparing nil with empty map", func(one map[string]string, two map[string]string, expectedRes bool) {
-
-		res := areStringMapsEqual(one, two)
-		Expect(res).To(Equal(expectedRes))
-	},
-		Entry("both nil", nil, nil, true),
-		Entry("one nil, one empty", map[string]string{}, nil, true),
-		Entry("one empty, one nil", nil, map[string]string{}, true),
-		Entry("both empty", map[string]string{}, map[string]string{}, true),
-		Entry("one empty, one has value", map[string]string{}, map[string]string{"key": "value"}, false),
-		Entry("one has value, one nil", map[string]string{"key": "value"}, nil, false),
-		Entry("equal values", map[string]string{"key": "value"}, map[string]string{"key": "value"}, true),
-	)
-
-})
-
 var _ = Describe("envMerge tests", func() {
 	DescribeTable("merges two slices of EnvVar entries",
 		func(existing, merge, expected []corev1.EnvVar, override bool) {
@@ -789,7 +705,7 @@ func makeTestReconciler(obj ...client.Object) *RolloutManagerReconciler {
 	err = crdv1.AddToScheme(s)
 	Expect(err).ToNot(HaveOccurred())
 
-	cl := fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(obj...).WithObjects(obj...).Build()
+	cl := newTestClient(s, obj...)
 
 	return &RolloutManagerReconciler{
 		Client:                       cl,
@@ -810,7 +726,7 @@ func makeTestReconcilerWithCustomLabels(resourceLabels map[string]string, obj ..
 	err = crdv1.AddToScheme(s)
 	Expect(err).ToNot(HaveOccurred())
 
-	cl := fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(obj...).WithObjects(obj...).Build()
+	cl := newTestClient(s, obj...)
 
 	return &RolloutManagerReconciler{
 		Client:                       cl,
@@ -820,6 +736,21 @@ func makeTestReconcilerWithCustomLabels(resourceLabels map[string]string, obj ..
 	}
 }
 
+// newTestClient returns a fake client which returns the managed fields of the objects, as the operator relies on them to server-side apply resources.
+func newTestClient(s *runtime.Scheme, obj ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(obj...).WithObjects(obj...).WithReturnManagedFields().
+		WithInterceptorFuncs(interceptor.Funcs{
+			// The fake client does not support dry-run apply requests, which are sent in Observe mode: they are not sent to the fake client
+			Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+				applyOptions := &client.ApplyOptions{}
+				if len(applyOptions.ApplyOptions(opts).DryRun) > 0 {
+					return nil
+				}
+				return c.Apply(ctx, obj, opts...)
+			},
+		}).Build()
+}
+
 func createNamespace(r *RolloutManagerReconciler, n string) error {
 	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: n}}
 	return r.Client.Create(context.Background(), ns)
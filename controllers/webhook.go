@@ -0,0 +1,168 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-argoproj-io-v1alpha1-rolloutmanager,mutating=false,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=rolloutmanagers,verbs=create;update,versions=v1alpha1,name=vrolloutmanager.kb.io,admissionReviewVersions=v1
+
+// RolloutManagerValidator is a validating admission webhook for RolloutManager. It rejects RolloutManagers that would otherwise only fail during reconciliation.
+type RolloutManagerValidator struct {
+	Client client.Client
+
+	// NamespaceScopedArgoRolloutsController should have the same value as the corresponding field of RolloutManagerReconciler
+	NamespaceScopedArgoRolloutsController bool
+
+	// PluginCatalogConfigMap should have the same value as the corresponding field of RolloutManagerReconciler
+	PluginCatalogConfigMap types.NamespacedName
+
+	// OpenShiftRoutePluginLocation should have the same value as the corresponding field of RolloutManagerReconciler
+	OpenShiftRoutePluginLocation string
+}
+
+// blank assignment to verify that RolloutManagerValidator implements admission.CustomValidator
+var _ admission.CustomValidator = &RolloutManagerValidator{}
+
+// SetupWebhookWithManager registers the RolloutManager validating webhook with the Manager's webhook server.
+func (v *RolloutManagerValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&rolloutsmanagerv1alpha1.RolloutManager{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *RolloutManagerValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cr, ok := obj.(*rolloutsmanagerv1alpha1.RolloutManager)
+	if !ok {
+		return nil, fmt.Errorf("expected a RolloutManager but got %T", obj)
+	}
+	return nil, v.validateRolloutManager(ctx, *cr, nil)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *RolloutManagerValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	cr, ok := newObj.(*rolloutsmanagerv1alpha1.RolloutManager)
+	if !ok {
+		return nil, fmt.Errorf("expected a RolloutManager but got %T", newObj)
+	}
+	oldCR, ok := oldObj.(*rolloutsmanagerv1alpha1.RolloutManager)
+	if !ok {
+		return nil, fmt.Errorf("expected a RolloutManager but got %T", oldObj)
+	}
+
+	// Don't block the removal of finalizers/deletion of a RolloutManager that is already being deleted
+	if cr.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	return nil, v.validateRolloutManager(ctx, *cr, oldCR)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *RolloutManagerValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateRolloutManager returns an error if the RolloutManager would fail to reconcile due to its .spec.
+//
+// It calls the same functions that are called by reconcileRolloutsManager, so that the webhook and the reconciler always agree on whether a RolloutManager is valid.
+// oldCR is the RolloutManager before an update, or nil on creation.
+func (v *RolloutManagerValidator) validateRolloutManager(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, oldCR *rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	if _, err := validateRolloutsScope(cr, v.NamespaceScopedArgoRolloutsController); err != nil {
+		return err
+	}
+
+	// Only check for other cluster-scoped RolloutManagers when the RolloutManager becomes cluster-scoped, so that a duplicate RolloutManager can still be fixed or scaled down
+	if oldCR == nil || oldCR.Spec.NamespaceScoped {
+		if _, err := checkForExistingRolloutManager(ctx, v.Client, cr); err != nil {
+			return err
+		}
+	}
+
+	catalog, err := loadPluginCatalog(ctx, v.Client, v.PluginCatalogConfigMap)
+	if err != nil {
+		return err
+	}
+	if cr.Spec.Plugins, err = resolvePlugins(catalog, cr.Spec.Plugins); err != nil {
+		return err
+	}
+
+	openShiftRoutePlugin, err := lookupOpenShiftRoutePlugin(cr, catalog, v.OpenShiftRoutePluginLocation)
+	if err != nil {
+		return err
+	}
+
+	arch, err := getPluginArchitecture(cr, openShiftRoutePlugin)
+	if err != nil {
+		return err
+	}
+	if arch != "" {
+		cr = resolvePluginArchitectures(cr, arch)
+	}
+
+	if err := validatePlugins(cr); err != nil {
+		return err
+	}
+
+	if err := validatePluginPolicyRules(cr); err != nil {
+		return err
+	}
+
+	if err := validateOverrides(cr); err != nil {
+		return err
+	}
+
+	if err := validateAdditionalVolumesAndContainers(cr); err != nil {
+		return err
+	}
+
+	if err := validatePodDisruptionBudget(cr); err != nil {
+		return err
+	}
+
+	if err := validateDeploymentSpec(cr); err != nil {
+		return err
+	}
+
+	if err := validateNotifications(cr); err != nil {
+		return fmt.Errorf("invalid .spec.notifications: %w", err)
+	}
+
+	if _, err := getControllerTuningArgs(cr.Spec.Controller); err != nil {
+		return err
+	}
+
+	if _, err := getTrafficRoutingArgs(cr.Spec.TrafficRouting); err != nil {
+		return err
+	}
+
+	if _, err := getRolloutsCommandArgs(cr); err != nil {
+		return fmt.Errorf("invalid .spec.extraCommandArgs: %w", err)
+	}
+
+	if _, err := getRolloutsContainerImage(cr); err != nil {
+		return err
+	}
+
+	if _, err := getImagePullPolicy(cr); err != nil {
+		return err
+	}
+
+	if cr.Spec.Dashboard.IsEnabled() {
+		if _, err := getRolloutsDashboardContainerImage(cr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
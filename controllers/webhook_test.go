@@ -0,0 +1,179 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("RolloutManagerValidator tests", func() {
+
+	var (
+		ctx       context.Context
+		rm        *rolloutsmanagerv1alpha1.RolloutManager
+		validator *RolloutManagerValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		rm = makeTestRolloutManager()
+
+		By("Set Env variable.")
+		os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+
+		r := makeTestReconciler()
+		validator = &RolloutManagerValidator{
+			Client:                                r.Client,
+			NamespaceScopedArgoRolloutsController: false,
+		}
+	})
+
+	AfterEach(func() {
+		By("Unset Env variable.")
+		os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+	})
+
+	It("should accept a valid RolloutManager on create and update", func() {
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = validator.ValidateUpdate(ctx, rm, rm)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should reject a cluster-scoped RolloutManager in a namespace that is not in CLUSTER_SCOPED_ARGO_ROLLOUTS_NAMESPACES", func() {
+		os.Setenv(ClusterScopedArgoRolloutsNamespaces, "some-other-namespace")
+
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(UnsupportedRolloutManagerClusterScopedNamespace))
+	})
+
+	It("should reject a namespace-scoped RolloutManager when only cluster-scoped Rollouts are allowed", func() {
+		rm.Spec.NamespaceScoped = true
+
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(UnsupportedRolloutManagerNamespaceScoped))
+	})
+
+	It("should reject a second cluster-scoped RolloutManager", func() {
+		existing := makeTestRolloutManager()
+		existing.Name = "existing-rollouts"
+		Expect(validator.Client.Create(ctx, existing)).To(Succeed())
+
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(UnsupportedRolloutManagerConfiguration))
+	})
+
+	It("should allow updates to a duplicate cluster-scoped RolloutManager, but not a switch to cluster scope", func() {
+		existing := makeTestRolloutManager()
+		existing.Name = "existing-rollouts"
+		Expect(validator.Client.Create(ctx, existing)).To(Succeed())
+
+		updated := rm.DeepCopy()
+		updated.Spec.Version = "v1.8.0"
+		_, err := validator.ValidateUpdate(ctx, rm, updated)
+		Expect(err).ToNot(HaveOccurred())
+
+		namespaceScoped := rm.DeepCopy()
+		namespaceScoped.Spec.NamespaceScoped = true
+		_, err = validator.ValidateUpdate(ctx, namespaceScoped, updated)
+		Expect(err).To(MatchError(UnsupportedRolloutManagerConfiguration))
+	})
+
+	It("should reject plugins that are not part of the plugin catalog, or that do not share an architecture", func() {
+		rm.Spec.Plugins.Metric = []rolloutsmanagerv1alpha1.Plugin{{Name: "argoproj-labs/sample-prometheus", Version: "v9.9.9"}}
+
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).To(MatchError(errUnknownPluginVersion))
+
+		rm.Spec.Plugins.Metric = []rolloutsmanagerv1alpha1.Plugin{
+			{Name: "argoproj-labs/metric-a", Architectures: []rolloutsmanagerv1alpha1.PluginArchitecture{{Architecture: "amd64", Location: "https://example.com/a-amd64"}}},
+			{Name: "argoproj-labs/metric-b", Architectures: []rolloutsmanagerv1alpha1.PluginArchitecture{{Architecture: "arm64", Location: "https://example.com/b-arm64"}}},
+		}
+
+		_, err = validator.ValidateCreate(ctx, rm)
+		Expect(err).To(MatchError(errUnsupportedPluginArchitecture))
+	})
+
+	It("should reject .spec.extraCommandArgs that conflict with the default arguments", func() {
+		rm.Spec.HA = &rolloutsmanagerv1alpha1.RolloutManagerHASpec{Enabled: true}
+		rm.Spec.ExtraCommandArgs = []string{"--leader-elect", "false"}
+
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("--leader-elect is already part of the default command arguments"))
+	})
+
+	It("should reject invalid .spec.controller fields, and .spec.extraCommandArgs that conflict with them", func() {
+		rolloutThreads := int32(0)
+		rm.Spec.Controller = &rolloutsmanagerv1alpha1.RolloutManagerControllerSpec{RolloutThreads: &rolloutThreads}
+
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).To(MatchError("invalid .spec.controller.rolloutThreads: 0 must be at least 1"))
+
+		rolloutThreads = 20
+		rm.Spec.ExtraCommandArgs = []string{"--rollout-threads=10"}
+
+		_, err = validator.ValidateCreate(ctx, rm)
+		Expect(err).To(MatchError(ContainSubstring("invalid .spec.extraCommandArgs: duplicate argument error: --rollout-threads=10 is already part of the default command arguments")))
+	})
+
+	It("should reject an invalid .spec.image and .spec.version combination", func() {
+		rm.Spec.Image = "quay.io/argoproj/Argo-Rollouts"
+		rm.Spec.Version = "v1.0.0"
+
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid image 'quay.io/argoproj/Argo-Rollouts:v1.0.0'"))
+	})
+
+	It("should reject the reserved OpenShift Route plugin name", func() {
+		rm.Spec.Plugins.TrafficManagement = []rolloutsmanagerv1alpha1.Plugin{
+			{
+				Name:     OpenShiftRolloutPluginName,
+				Location: "https://example.com/plugin",
+			},
+		}
+
+		_, err := validator.ValidateUpdate(ctx, rm, rm)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("cannot be modified or added through the RolloutManager CR"))
+	})
+
+	It("should reject plugin RBAC rules without verbs", func() {
+		rm.Spec.Plugins.TrafficManagement = []rolloutsmanagerv1alpha1.Plugin{
+			{
+				Name:     "argoproj-labs/gatewayAPI",
+				Location: "https://example.com/plugin",
+				Rules:    []rbacv1.PolicyRule{{APIGroups: []string{"gateway.networking.k8s.io"}, Resources: []string{"httproutes"}}},
+			},
+		}
+
+		_, err := validator.ValidateCreate(ctx, rm)
+		Expect(err).To(MatchError("invalid rules of plugin argoproj-labs/gatewayAPI: verbs must not be empty"))
+	})
+
+	It("should not block updates to a RolloutManager that is being deleted", func() {
+		rm.Spec.NamespaceScoped = true
+		now := metav1.Now()
+		rm.DeletionTimestamp = &now
+
+		_, err := validator.ValidateUpdate(ctx, rm, rm)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should always allow deletion", func() {
+		rm.Spec.NamespaceScoped = true
+
+		_, err := validator.ValidateDelete(ctx, rm)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
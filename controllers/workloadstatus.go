@@ -0,0 +1,182 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	"github.com/distribution/reference"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// deploymentProgressDeadlineExceededReason is the reason of the Progressing condition of a Deployment that exceeded its .spec.progressDeadlineSeconds.
+	deploymentProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
+)
+
+// setWorkloadStatus sets the image, scope and replicas of the Rollouts controller, and the Available, Progressing and Degraded conditions, on the reconcileStatusResult.
+// It is called whether or not the reconciliation succeeded, so that the Degraded condition also reports reconciliation failures.
+func (r *RolloutManagerReconciler) setWorkloadStatus(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, rr *reconcileStatusResult) error {
+
+	deploy := &appsv1.Deployment{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, deploy); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get Deployment %s: %w", DefaultArgoRolloutsResourceName, err)
+		}
+		deploy = nil
+	}
+
+	workload := rolloutsmanagerv1alpha1.RolloutManagerWorkloadStatus{
+		Scope: rolloutsmanagerv1alpha1.ScopeCluster,
+	}
+	if cr.Spec.NamespaceScoped {
+		workload.Scope = rolloutsmanagerv1alpha1.ScopeNamespace
+	}
+
+	// The image is only reported when it is valid: an invalid image is reported by the Degraded condition instead
+	if image, err := getRolloutsContainerImage(cr); err == nil {
+		workload.Image = image
+		workload.Version = getImageVersion(image)
+	}
+
+	if deploy != nil {
+		if deploy.Spec.Replicas != nil {
+			workload.Replicas = *deploy.Spec.Replicas
+		}
+		workload.ReadyReplicas = deploy.Status.ReadyReplicas
+		workload.UpdatedReplicas = deploy.Status.UpdatedReplicas
+		workload.AvailableReplicas = deploy.Status.AvailableReplicas
+	}
+
+	rr.workload = &workload
+
+	rr.conditions = append(rr.conditions,
+		getAvailableCondition(cr, deploy, *rr),
+		getProgressingCondition(deploy),
+		getDegradedCondition(cr, deploy, *rr))
+
+	return nil
+}
+
+// getAvailableCondition returns the Available condition of the RolloutManager, based on the Available condition of the Rollouts controller Deployment, and on the phase of the Dashboard.
+func getAvailableCondition(cr rolloutsmanagerv1alpha1.RolloutManager, deploy *appsv1.Deployment, rr reconcileStatusResult) metav1.Condition {
+
+	if deploy == nil {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeAvailable, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonDeploymentNotFound,
+			fmt.Sprintf("the Deployment %s does not exist", DefaultArgoRolloutsResourceName))
+	}
+
+	// A Deployment scaled to zero is reported as available by Kubernetes, but no Rollouts controller is running
+	if isDeploymentSuspended(cr, deploy) {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeAvailable, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonSuspended,
+			"the Rollouts controller is suspended by .spec.suspend")
+	}
+
+	available := getDeploymentCondition(*deploy, appsv1.DeploymentAvailable)
+	if available == nil || available.Status != corev1.ConditionTrue {
+		message := fmt.Sprintf("the Deployment %s does not have minimum availability", DefaultArgoRolloutsResourceName)
+		if available != nil && available.Message != "" {
+			message = available.Message
+		}
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeAvailable, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonMinimumReplicasUnavailable, message)
+	}
+
+	if cr.Spec.Dashboard.IsEnabled() {
+		dashboard := cr.Status.Dashboard
+		if rr.dashboard != nil {
+			dashboard = *rr.dashboard
+		}
+		if dashboard != rolloutsmanagerv1alpha1.PhaseAvailable {
+			return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeAvailable, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonDashboardUnavailable,
+				fmt.Sprintf("the Dashboard is %s", dashboard))
+		}
+	}
+
+	return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeAvailable, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonMinimumReplicasAvailable, "")
+}
+
+// getProgressingCondition returns the Progressing condition of the RolloutManager, based on the rollout state of the Rollouts controller Deployment.
+func getProgressingCondition(deploy *appsv1.Deployment) metav1.Condition {
+
+	if deploy == nil {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeProgressing, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonDeploymentNotFound,
+			fmt.Sprintf("the Deployment %s does not exist", DefaultArgoRolloutsResourceName))
+	}
+
+	if progressing := getDeploymentCondition(*deploy, appsv1.DeploymentProgressing); progressing != nil && progressing.Reason == deploymentProgressDeadlineExceededReason {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeProgressing, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonProgressDeadlineExceeded, progressing.Message)
+	}
+
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+
+	// The same checks as 'kubectl rollout status'
+	if deploy.Generation > deploy.Status.ObservedGeneration || deploy.Status.UpdatedReplicas < replicas ||
+		deploy.Status.Replicas > deploy.Status.UpdatedReplicas || deploy.Status.AvailableReplicas < deploy.Status.UpdatedReplicas {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeProgressing, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonRolloutInProgress,
+			fmt.Sprintf("%d of %d pods of the Rollouts controller are updated, and %d are available", deploy.Status.UpdatedReplicas, replicas, deploy.Status.AvailableReplicas))
+	}
+
+	return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeProgressing, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonRolloutComplete, "")
+}
+
+// getDegradedCondition returns the Degraded condition of the RolloutManager, based on the result of the reconciliation, the rollout state of the Rollouts controller Deployment, and the ControllerHealthy condition.
+func getDegradedCondition(cr rolloutsmanagerv1alpha1.RolloutManager, deploy *appsv1.Deployment, rr reconcileStatusResult) metav1.Condition {
+
+	if rr.condition.Type == rolloutsmanagerv1alpha1.RolloutManagerConditionType && rr.condition.Status == metav1.ConditionFalse {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeDegraded, metav1.ConditionTrue, rr.condition.Reason, rr.condition.Message)
+	}
+
+	if deploy != nil {
+		if progressing := getDeploymentCondition(*deploy, appsv1.DeploymentProgressing); progressing != nil && progressing.Reason == deploymentProgressDeadlineExceededReason {
+			return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeDegraded, metav1.ConditionTrue, rolloutsmanagerv1alpha1.RolloutManagerReasonProgressDeadlineExceeded, progressing.Message)
+		}
+	}
+
+	// The ControllerHealthy condition is only computed when the reconciliation succeeds
+	controllerHealthy := meta.FindStatusCondition(rr.conditions, rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy)
+	if controllerHealthy == nil {
+		controllerHealthy = meta.FindStatusCondition(cr.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerConditionTypeControllerHealthy)
+	}
+
+	// Pods which are not ready yet, without a known failure, are reported by the Progressing condition instead
+	if controllerHealthy != nil && controllerHealthy.Status == metav1.ConditionFalse && controllerHealthy.Reason != rolloutsmanagerv1alpha1.RolloutManagerReasonControllerNotReady {
+		return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeDegraded, metav1.ConditionTrue, controllerHealthy.Reason, controllerHealthy.Message)
+	}
+
+	return createConditionOfType(rolloutsmanagerv1alpha1.RolloutManagerConditionTypeDegraded, metav1.ConditionFalse, rolloutsmanagerv1alpha1.RolloutManagerReasonAsExpected, "")
+}
+
+// getDeploymentCondition returns the condition of the Deployment with the given type, or nil if the Deployment does not have this condition.
+func getDeploymentCondition(deploy appsv1.Deployment, conditionType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for i := range deploy.Status.Conditions {
+		if deploy.Status.Conditions[i].Type == conditionType {
+			return &deploy.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// getImageVersion returns the tag, or the digest, of the image reference, or an empty string if the image has neither.
+func getImageVersion(image string) string {
+
+	ref, err := reference.Parse(image)
+	if err != nil {
+		return ""
+	}
+
+	if tagged, ok := ref.(reference.Tagged); ok {
+		return tagged.Tag()
+	}
+	if digested, ok := ref.(reference.Digested); ok {
+		return digested.Digest().String()
+	}
+
+	return ""
+}
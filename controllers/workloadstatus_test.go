@@ -0,0 +1,153 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("Workload status tests", func() {
+
+	int32Ptr := func(val int32) *int32 { return &val }
+
+	makeDeployment := func(replicas int32, status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(replicas)},
+			Status:     status,
+		}
+	}
+
+	DescribeTable("should return the tag or digest of an image", func(image string, expectedVersion string) {
+		Expect(getImageVersion(image)).To(Equal(expectedVersion))
+	},
+		Entry("with a tag", "quay.io/argoproj/argo-rollouts:v1.7.2", "v1.7.2"),
+		Entry("with a digest", "quay.io/argoproj/argo-rollouts@sha256:a9ca3d1a9f6d0d3a06c4a2d7ff0e77e7a1d8d1e5a3ff8b1d4b0e0b3d0b1d5b2c", "sha256:a9ca3d1a9f6d0d3a06c4a2d7ff0e77e7a1d8d1e5a3ff8b1d4b0e0b3d0b1d5b2c"),
+		Entry("without a tag", "quay.io/argoproj/argo-rollouts", ""),
+	)
+
+	DescribeTable("should report the rollout state of the Deployment in the Progressing condition", func(deploy *appsv1.Deployment, expectedStatus metav1.ConditionStatus, expectedReason string) {
+		condition := getProgressingCondition(deploy)
+		Expect(condition.Type).To(Equal(v1alpha1.RolloutManagerConditionTypeProgressing))
+		Expect(condition.Status).To(Equal(expectedStatus))
+		Expect(condition.Reason).To(Equal(expectedReason))
+	},
+		Entry("when the Deployment does not exist", nil, metav1.ConditionFalse, v1alpha1.RolloutManagerReasonDeploymentNotFound),
+		Entry("when the Deployment was not observed yet", makeDeployment(1, appsv1.DeploymentStatus{
+			ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1,
+		}), metav1.ConditionTrue, v1alpha1.RolloutManagerReasonRolloutInProgress),
+		Entry("when old pods are still running", makeDeployment(2, appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2,
+		}), metav1.ConditionTrue, v1alpha1.RolloutManagerReasonRolloutInProgress),
+		Entry("when the rollout is complete", makeDeployment(2, appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2,
+		}), metav1.ConditionFalse, v1alpha1.RolloutManagerReasonRolloutComplete),
+		Entry("when the rollout exceeded its progress deadline", makeDeployment(1, appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1,
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"}},
+		}), metav1.ConditionFalse, v1alpha1.RolloutManagerReasonProgressDeadlineExceeded),
+	)
+
+	It("should report the Dashboard in the Available condition, when it is enabled", func() {
+		deploy := makeDeployment(1, appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+		})
+		cr := *makeTestRolloutManager()
+
+		condition := getAvailableCondition(cr, deploy, reconcileStatusResult{})
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonMinimumReplicasAvailable))
+
+		cr.Spec.Dashboard = &v1alpha1.RolloutManagerDashboardSpec{Enabled: true}
+		pending := v1alpha1.PhasePending
+		condition = getAvailableCondition(cr, deploy, reconcileStatusResult{dashboard: &pending})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonDashboardUnavailable))
+		Expect(condition.Message).To(Equal("the Dashboard is Pending"))
+	})
+
+	It("should report reconciliation failures, and failing pods, in the Degraded condition", func() {
+		cr := *makeTestRolloutManager()
+		deploy := makeDeployment(1, appsv1.DeploymentStatus{})
+
+		condition := getDegradedCondition(cr, deploy, wrapCondition(createCondition("")))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonAsExpected))
+
+		condition = getDegradedCondition(cr, deploy, wrapCondition(createCondition("invalid override", v1alpha1.RolloutManagerReasonInvalidOverride)))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonInvalidOverride))
+		Expect(condition.Message).To(Equal("invalid override"))
+
+		By("ignoring pods which are not ready yet")
+		rr := wrapCondition(createCondition(""))
+		rr.conditions = []metav1.Condition{createConditionOfType(v1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionFalse, v1alpha1.RolloutManagerReasonControllerNotReady, "0 of 1 pods of the Rollouts controller are ready")}
+		condition = getDegradedCondition(cr, deploy, rr)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+		By("reporting the ControllerHealthy condition of the previous reconciliation, if it was not computed")
+		cr.Status.Conditions = []metav1.Condition{createConditionOfType(v1alpha1.RolloutManagerConditionTypeControllerHealthy, metav1.ConditionFalse, v1alpha1.RolloutManagerReasonControllerCrashLooping, "container argo-rollouts of pod argo-rollouts-abc is crash looping")}
+		condition = getDegradedCondition(cr, deploy, wrapCondition(createCondition("")))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonControllerCrashLooping))
+	})
+
+	Context("reconciling the RolloutManager", func() {
+
+		var (
+			ctx context.Context
+			rm  *v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			rm = makeTestRolloutManager(func(rm *v1alpha1.RolloutManager) {
+				rm.Generation = 3
+				rm.Spec.Version = "v1.7.2"
+			})
+			r = makeTestReconciler(rm)
+			Expect(createNamespace(r, rm.Namespace)).To(Succeed())
+			os.Setenv(ClusterScopedArgoRolloutsNamespaces, rm.Namespace)
+		})
+
+		AfterEach(func() {
+			os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)
+		})
+
+		It("should set the observed generation, the image, scope and replicas, and the Available, Progressing and Degraded conditions", func() {
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: rm.Name, Namespace: rm.Namespace}}
+			_, err := r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(r.Client.Get(ctx, req.NamespacedName, rm)).To(Succeed())
+			Expect(rm.Status.ObservedGeneration).To(Equal(rm.Generation))
+			Expect(rm.Status.Image).To(Equal(DefaultArgoRolloutsImage + ":v1.7.2"))
+			Expect(rm.Status.Version).To(Equal("v1.7.2"))
+			Expect(rm.Status.Scope).To(Equal(v1alpha1.ScopeCluster))
+			Expect(rm.Status.Replicas).To(Equal(int32(1)))
+
+			available := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeAvailable)
+			Expect(available).ToNot(BeNil())
+			Expect(available.Status).To(Equal(metav1.ConditionFalse))
+			Expect(available.Reason).To(Equal(v1alpha1.RolloutManagerReasonMinimumReplicasUnavailable))
+
+			progressing := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeProgressing)
+			Expect(progressing).ToNot(BeNil())
+			Expect(progressing.Status).To(Equal(metav1.ConditionTrue))
+
+			degraded := meta.FindStatusCondition(rm.Status.Conditions, v1alpha1.RolloutManagerConditionTypeDegraded)
+			Expect(degraded).ToNot(BeNil())
+			Expect(degraded.Status).To(Equal(metav1.ConditionFalse))
+		})
+	})
+})
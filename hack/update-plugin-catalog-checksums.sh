@@ -0,0 +1,40 @@
+#!/bin/bash
+
+# Downloads each location of the embedded plugin catalog, including the location of each architecture of a version, and
+# writes its SHA256 checksum into the 'sha256' field next to it. Run this script whenever a version is added to
+# controllers/plugin_catalog.yaml.
+
+set -euo pipefail
+
+SCRIPTPATH="$(
+  cd -- "$(dirname "$0")" >/dev/null 2>&1 || exit
+  pwd -P
+)"
+
+CATALOG="$SCRIPTPATH/../controllers/plugin_catalog.yaml"
+
+TMP_DIR=$(mktemp -d 2>/dev/null || mktemp -d -t 'mytmpdir')
+trap 'rm -rf "$TMP_DIR"' EXIT
+
+while IFS= read -r line; do
+
+  # Existing checksums are replaced by the checksum computed below
+  if [[ "$line" =~ ^[[:space:]]+sha256: ]]; then
+    continue
+  fi
+
+  echo "$line" >> "$TMP_DIR/catalog.yaml"
+
+  if [[ "$line" =~ ^([[:space:]]+)location:[[:space:]]*(.+)$ ]]; then
+    INDENT="${BASH_REMATCH[1]}"
+    LOCATION="${BASH_REMATCH[2]}"
+
+    echo "* Downloading $LOCATION" >&2
+    curl --fail --silent --show-error --location --output "$TMP_DIR/plugin" "$LOCATION"
+
+    echo "${INDENT}sha256: $(sha256sum "$TMP_DIR/plugin" | cut -d ' ' -f 1)" >> "$TMP_DIR/catalog.yaml"
+  fi
+
+done < "$CATALOG"
+
+mv "$TMP_DIR/catalog.yaml" "$CATALOG"